@@ -0,0 +1,53 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package main
+
+import (
+	"github.com/renato0307/canivete-api/pkg/admin"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-api/pkg/ratelimit"
+	"github.com/renato0307/canivete-api/pkg/tools"
+	"github.com/renato0307/canivete-api/pkg/versioning"
+)
+
+// newGraphqlServer creates the GraphQL server of the enabled service
+// groups, backed by the same core services as the REST endpoints. The
+// fields are disabled with the v1 routes of their tools and each resolved
+// field takes their cost.
+func newGraphqlServer(cfg config.Config, toggles *admin.Toggles, limiter *ratelimit.Limiter) *graphqlserver.Server {
+	s := graphqlserver.New(cfg.Graphql, cfg.Auth.Enabled)
+	s.SetToggles(toggles)
+
+	routes := map[string]string{}
+	for _, group := range tools.DefaultRegistry().Groups() {
+		if cfg.GroupEnabled(group.Name) && group.RegisterGraphqlFields != nil {
+			group.RegisterGraphqlFields(s.Group(group.Name))
+			for _, tool := range group.Tools {
+				if tool.GraphqlField != "" {
+					routes[tool.GraphqlField] = toolRoute(versioning.V1, group, tool)
+					toggles.Alias(tool.GraphqlField, routes[tool.GraphqlField])
+				}
+			}
+		}
+	}
+	if cfg.RateLimit.Enabled {
+		s.SetRateLimiter(limiter, routes)
+	}
+
+	return s
+}
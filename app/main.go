@@ -17,47 +17,115 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package main
 
 import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
 	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/gin-gonic/gin"
-	"github.com/renato0307/canivete-api/pkg/datetime"
-	"github.com/renato0307/canivete-api/pkg/finance"
-	"github.com/renato0307/canivete-api/pkg/internet"
-	"github.com/renato0307/canivete-api/pkg/programming"
-	datetimecore "github.com/renato0307/canivete-core/pkg/datetime"
-	financecore "github.com/renato0307/canivete-core/pkg/finance"
-	internetcore "github.com/renato0307/canivete-core/pkg/internet"
-	programmingcore "github.com/renato0307/canivete-core/pkg/programming"
+	"github.com/renato0307/canivete-api/pkg/admin"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/certs"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/server"
+	"github.com/renato0307/canivete-api/pkg/shutdown"
+	"github.com/renato0307/canivete-api/pkg/tracing"
+
+	// the service groups register in the tool registry
+	_ "github.com/renato0307/canivete-api/pkg/datetime"
+	_ "github.com/renato0307/canivete-api/pkg/finance"
+	_ "github.com/renato0307/canivete-api/pkg/internet"
+	_ "github.com/renato0307/canivete-api/pkg/programming"
 )
 
 func main() {
-
-	r := gin.Default()
-	err := r.SetTrustedProxies(nil) // https://pkg.go.dev/github.com/gin-gonic/gin#readme-don-t-trust-all-proxies
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
 	if err != nil {
-		log.Fatalf("error setting trusted proxies to nil: %s\n", err.Error())
+		log.Fatalf("error loading configuration: %s\n", err.Error())
+	}
+
+	if cfg.PrintConfig {
+		err = cfg.Print(os.Stdout)
+		if err != nil {
+			log.Fatalf("error printing configuration: %s\n", err.Error())
+		}
+		return
 	}
 
-	r.GET("/", func(c *gin.Context) {
-		c.String(200, "Welcome to canivete-api!")
+	err = logging.Setup(cfg.Logging)
+	if err != nil {
+		log.Fatalf("error setting up logging: %s\n", err.Error())
+	}
+	logger := logging.GetLogger()
+	shutdown.Register("logging", func(ctx context.Context) error {
+		return logging.Sync(logger)
 	})
 
-	v1 := r.Group("/v1")
+	// kubernetes sends a SIGTERM when a pod is being replaced
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	programmingService := programmingcore.Service{}
-	programming.SetRouterGroup(&programmingService, v1)
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		log.Fatalf("error setting up tracing: %s\n", err.Error())
+	}
+	shutdown.Register("tracing", shutdownTracing)
 
-	datetimeService := datetimecore.Service{}
-	datetime.SetRouterGroup(&datetimeService, v1)
+	closeAudit, err := audit.Setup(cfg.Audit)
+	if err != nil {
+		log.Fatalf("error setting up audit: %s\n", err.Error())
+	}
+	shutdown.Register("audit", closeAudit)
 
-	financeService := financecore.Service{}
-	finance.SetRouterGroup(&financeService, v1)
+	authenticators, err := newAuthenticators(cfg.Auth)
+	if err != nil {
+		log.Fatalf("error creating authenticators: %s\n", err.Error())
+	}
 
-	internetService := internetcore.Service{}
-	internet.SetRouterGroup(&internetService, v1)
+	toggles := admin.NewToggles()
+	limiter := newRateLimiter(cfg.RateLimit)
+	r, err := newRouter(cfg, authenticators, toggles, limiter)
+	if err != nil {
+		log.Fatalf("error creating router: %s\n", err.Error())
+	}
+
+	var tlsConfig *tls.Config
+	if cfg.Server.Tls.Enabled() {
+		reloader, err := certs.NewReloader(cfg.Server.Tls)
+		if err != nil {
+			log.Fatalf("error loading certificates: %s\n", err.Error())
+		}
+		go reloader.Watch(ctx)
+		tlsConfig = reloader.TlsConfig()
+	}
+
+	if cfg.Grpc.Enabled {
+		grpcServer := newGrpcServer(cfg, authenticators, toggles, limiter, tlsConfig)
+		listener, err := net.Listen("tcp", cfg.Grpc.Address)
+		if err != nil {
+			log.Fatalf("error listening for grpc: %s\n", err.Error())
+		}
+		go func() {
+			err := grpcServer.Serve(listener)
+			if err != nil {
+				// the http server is stopped too, so the api is restarted whole
+				logger.Errorw("grpc server failed", "error", err.Error())
+				stop()
+			}
+		}()
+		shutdown.Register("grpc", grpcServer.Stop)
+	}
 
-	err = r.Run()
+	err = server.New(cfg.Server, r, tlsConfig).Run(ctx)
 	if err != nil {
-		log.Fatalf("error running gin: %s\n", err.Error())
+		log.Fatalf("error running server: %s\n", err.Error())
 	}
 }
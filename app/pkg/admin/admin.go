@@ -0,0 +1,221 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package admin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/logging"
+)
+
+// validate validates the bodies of the admin endpoints.
+var validate = validator.New()
+
+// redacted replaces the secrets in the configuration dump.
+const redacted = "[redacted]"
+
+// started is when the api started, for the build info.
+var started = time.Now()
+
+// LogLevelInput is the input of the log level endpoint.
+type LogLevelInput struct {
+	Level string `json:"level" validate:"required"`
+}
+
+// LogLevelOutput is the answer of the log level endpoint.
+type LogLevelOutput struct {
+	Level string `json:"level"`
+}
+
+// RouteInput is the input of the route endpoint.
+type RouteInput struct {
+	Enabled *bool `json:"enabled" validate:"required"`
+}
+
+// RouteOutput is the state of a route of the tools.
+type RouteOutput struct {
+	Route   string `json:"route"`
+	Enabled bool   `json:"enabled"`
+}
+
+// RoutesOutput is the answer of the routes endpoint.
+type RoutesOutput struct {
+	Routes []RouteOutput `json:"routes"`
+}
+
+// BuildOutput is the answer of the build endpoint.
+type BuildOutput struct {
+	Version       string `json:"version"`
+	GoVersion     string `json:"goVersion"`
+	Module        string `json:"module,omitempty"`
+	ModuleVersion string `json:"moduleVersion,omitempty"`
+	Os            string `json:"os"`
+	Arch          string `json:"arch"`
+	StartedAt     string `json:"startedAt"`
+	Uptime        string `json:"uptime"`
+}
+
+// SetRouterGroup adds to the base group the endpoints changing the api at
+// runtime: the log level and the state of the routes of the tools, and
+// the endpoints dumping the effective configuration and the build info.
+// The pprof profiles are added when enabled in the configuration.
+func SetRouterGroup(cfg config.Config, toggles *Toggles, version string, base *gin.RouterGroup) *gin.RouterGroup {
+	base.GET("/log-level", getLogLevel)
+	base.PUT("/log-level", putLogLevel)
+	base.GET("/routes", getRoutes(toggles))
+	base.PUT("/routes/*route", putRoute(toggles))
+	base.GET("/config", getConfig(cfg, toggles))
+	base.GET("/build", getBuild(version))
+
+	if cfg.Admin.Pprof {
+		setPprofRoutes(base)
+	}
+
+	return base
+}
+
+// getLogLevel handles the log level request.
+// It returns 200 with the current level.
+func getLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, LogLevelOutput{Level: logging.Level().String()})
+}
+
+// putLogLevel handles the request changing the log level.
+// It returns 200 on success and 400 for an invalid level.
+func putLogLevel(c *gin.Context) {
+	input := LogLevelInput{}
+	if !bind(c, &input) {
+		return
+	}
+
+	err := logging.SetLevel(input.Level)
+	if err != nil {
+		apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeValidationFailed, err.Error()))
+		return
+	}
+
+	logging.FromContext(c).Infow("log level changed", "level", logging.Level().String())
+	c.JSON(http.StatusOK, LogLevelOutput{Level: logging.Level().String()})
+}
+
+func getRoutes(toggles *Toggles) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, RoutesOutput{Routes: toggles.Routes()})
+	}
+}
+
+// putRoute handles the request enabling or disabling a route, given by
+// its path, like /admin/routes/v1/internet/medium-to-md.
+// It returns 200 on success, 400 for an invalid body and 404 for an
+// unknown route.
+func putRoute(toggles *Toggles) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		input := RouteInput{}
+		if !bind(c, &input) {
+			return
+		}
+
+		route := c.Param("route")
+		err := toggles.Set(route, *input.Enabled)
+		if err != nil {
+			apierrors.Abort(c, apierrors.New(http.StatusNotFound, apierrors.CodeNotFound, err.Error()))
+			return
+		}
+
+		logging.FromContext(c).Infow("route toggled", "toggledRoute", route, "enabled", *input.Enabled)
+		c.JSON(http.StatusOK, RouteOutput{Route: route, Enabled: *input.Enabled})
+	}
+}
+
+// getConfig handles the configuration request. It answers with the
+// effective configuration as YAML, like --print-config, with the changes
+// made at runtime and without the secrets.
+func getConfig(cfg config.Config, toggles *Toggles) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cfg.Logging.Level = logging.Level().String()
+		cfg.Admin.DisabledRoutes = toggles.Disabled()
+
+		apiKeys := []config.ApiKeyConfig{}
+		for _, key := range cfg.Auth.ApiKeys {
+			key.Hash = redacted
+			apiKeys = append(apiKeys, key)
+		}
+		cfg.Auth.ApiKeys = apiKeys
+
+		out := bytes.Buffer{}
+		err := cfg.Print(&out)
+		if err != nil {
+			apierrors.Abort(c, apierrors.Internal(apierrors.CodeInternal, "unexpected error writing the configuration"))
+			return
+		}
+
+		c.Data(http.StatusOK, "application/yaml; charset=utf-8", out.Bytes())
+	}
+}
+
+func getBuild(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		output := BuildOutput{
+			Version:   version,
+			GoVersion: runtime.Version(),
+			Os:        runtime.GOOS,
+			Arch:      runtime.GOARCH,
+			StartedAt: started.UTC().Format(time.RFC3339),
+			Uptime:    time.Since(started).Round(time.Second).String(),
+		}
+		if info, ok := debug.ReadBuildInfo(); ok {
+			output.Module = info.Main.Path
+			output.ModuleVersion = info.Main.Version
+		}
+
+		c.JSON(http.StatusOK, output)
+	}
+}
+
+// bind reads the JSON body in input and validates it. It answers with 400
+// (BadRequest) and returns false when the body is invalid.
+func bind(c *gin.Context, input interface{}) bool {
+	body, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		apierrors.Abort(c, apierrors.Internal(apierrors.CodeInternal, "unexpected error reading the body"))
+		return false
+	}
+
+	err = json.Unmarshal(body, input)
+	if err != nil {
+		apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidBody, "request body is invalid: "+err.Error()))
+		return false
+	}
+
+	err = validate.Struct(input)
+	if err != nil {
+		apierrors.Abort(c, apierrors.Validation(err))
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,171 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGin(cfg config.Config) (*gin.Engine, *Toggles) {
+	toggles := NewToggles()
+	toggles.Add("/v1/programming/uuid")
+	toggles.Add("/v1/internet/medium-to-md")
+
+	r := gin.New()
+	SetRouterGroup(cfg, toggles, "1.2.3", r.Group("/admin"))
+
+	return r, toggles
+}
+
+func serve(r *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPutLogLevel(t *testing.T) {
+	// arrange
+	r, _ := setupGin(config.Default())
+	defer logging.SetLevel("info")
+
+	// act
+	w := serve(r, http.MethodPut, "/admin/log-level", `{"level":"debug"}`)
+	current := serve(r, http.MethodGet, "/admin/log-level", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "debug", logging.Level().String())
+	output := LogLevelOutput{}
+	json.Unmarshal(current.Body.Bytes(), &output)
+	assert.Equal(t, "debug", output.Level)
+}
+
+func TestPutLogLevelWithInvalidBody(t *testing.T) {
+	tests := []struct {
+		body string
+		code string
+	}{
+		{`{"level":`, apierrors.CodeInvalidBody},
+		{`{}`, apierrors.CodeValidationFailed},
+		{`{"level":"verbose"}`, apierrors.CodeValidationFailed},
+	}
+
+	for _, test := range tests {
+		// arrange
+		r, _ := setupGin(config.Default())
+
+		// act
+		w := serve(r, http.MethodPut, "/admin/log-level", test.body)
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, w.Code, test.body)
+		apiError, _ := apierrors.FromResponse(w.Result())
+		assert.Equal(t, test.code, apiError.Code, test.body)
+	}
+}
+
+func TestPutRoute(t *testing.T) {
+	// arrange
+	r, toggles := setupGin(config.Default())
+
+	// act
+	disabled := serve(r, http.MethodPut, "/admin/routes/v1/internet/medium-to-md", `{"enabled":false}`)
+	unknown := serve(r, http.MethodPut, "/admin/routes/v1/unknown", `{"enabled":false}`)
+	missing := serve(r, http.MethodPut, "/admin/routes/v1/programming/uuid", `{}`)
+	listed := serve(r, http.MethodGet, "/admin/routes", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, disabled.Code)
+	assert.False(t, toggles.Enabled("/v1/internet/medium-to-md"))
+	assert.Equal(t, http.StatusNotFound, unknown.Code)
+	assert.Equal(t, http.StatusBadRequest, missing.Code)
+	assert.True(t, toggles.Enabled("/v1/programming/uuid"))
+
+	output := RoutesOutput{}
+	json.Unmarshal(listed.Body.Bytes(), &output)
+	assert.Equal(t, []RouteOutput{
+		{Route: "/v1/internet/medium-to-md", Enabled: false},
+		{Route: "/v1/programming/uuid", Enabled: true},
+	}, output.Routes)
+}
+
+func TestGetConfig(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Auth.ApiKeys = []config.ApiKeyConfig{{Name: "ci", Hash: "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", Scopes: []string{config.ScopeAll}}}
+	r, toggles := setupGin(cfg)
+	toggles.Set("/v1/internet/medium-to-md", false)
+
+	// act
+	w := serve(r, http.MethodGet, "/admin/config", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/yaml")
+	assert.Contains(t, w.Body.String(), "address: :8080")
+	assert.Contains(t, w.Body.String(), "name: ci")
+	assert.Contains(t, w.Body.String(), "- /v1/internet/medium-to-md")
+	assert.NotContains(t, w.Body.String(), "9f86d081")
+	assert.Equal(t, "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08", cfg.Auth.ApiKeys[0].Hash)
+}
+
+func TestGetBuild(t *testing.T) {
+	// arrange
+	r, _ := setupGin(config.Default())
+
+	// act
+	w := serve(r, http.MethodGet, "/admin/build", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	output := BuildOutput{}
+	json.Unmarshal(w.Body.Bytes(), &output)
+	assert.Equal(t, "1.2.3", output.Version)
+	assert.True(t, strings.HasPrefix(output.GoVersion, "go"))
+	assert.NotEmpty(t, output.StartedAt)
+}
+
+func TestPprofRoutes(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	r, _ := setupGin(cfg)
+	cfg.Admin.Pprof = true
+	pprof, _ := setupGin(cfg)
+
+	// act
+	disabled := serve(r, http.MethodGet, "/admin/debug/pprof/", "")
+	index := serve(pprof, http.MethodGet, "/admin/debug/pprof/", "")
+	goroutine := serve(pprof, http.MethodGet, "/admin/debug/pprof/goroutine?debug=1", "")
+
+	// assert
+	assert.Equal(t, http.StatusNotFound, disabled.Code)
+	assert.Equal(t, http.StatusOK, index.Code)
+	assert.Contains(t, index.Body.String(), "goroutine")
+	assert.Equal(t, http.StatusOK, goroutine.Code)
+	assert.Contains(t, goroutine.Body.String(), "goroutine profile")
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+)
+
+// Toggles keeps which routes of the tools are enabled. The routes are
+// enabled when added, and can be disabled at runtime.
+type Toggles struct {
+	mu     sync.RWMutex
+	routes map[string]bool
+	// aliases are the routes followed by the other calls of the tools, by
+	// GraphQL field or gRPC method.
+	aliases map[string]string
+}
+
+// NewToggles returns toggles without routes.
+func NewToggles() *Toggles {
+	return &Toggles{routes: map[string]bool{}, aliases: map[string]string{}}
+}
+
+// Add adds an enabled route, like /v1/programming/uuid.
+func (t *Toggles) Add(route string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.routes[route] = true
+}
+
+// Alias makes the calls named name, like the GraphQL field Query.uuid or
+// the gRPC method /canivete.v1.ProgrammingService/NewUuid, enabled with
+// the route of their tool. The aliases are not listed with the routes.
+func (t *Toggles) Alias(name string, route string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.aliases[name] = route
+}
+
+// Set enables or disables the route. It fails when the route was not
+// added.
+func (t *Toggles) Set(route string, enabled bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.routes[route]; !ok {
+		return fmt.Errorf("unknown route %q", route)
+	}
+
+	t.routes[route] = enabled
+	return nil
+}
+
+// Enabled tells if the route, or the alias of a route, can be called. The
+// routes which were not added are enabled.
+func (t *Toggles) Enabled(route string) bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if aliased, ok := t.aliases[route]; ok {
+		route = aliased
+	}
+	enabled, ok := t.routes[route]
+	return !ok || enabled
+}
+
+// Routes returns the routes added with their state, sorted by route.
+func (t *Toggles) Routes() []RouteOutput {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	routes := []RouteOutput{}
+	for route, enabled := range t.routes {
+		routes = append(routes, RouteOutput{Route: route, Enabled: enabled})
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		return routes[i].Route < routes[j].Route
+	})
+
+	return routes
+}
+
+// Disabled returns the disabled routes, sorted.
+func (t *Toggles) Disabled() []string {
+	disabled := []string{}
+	for _, route := range t.Routes() {
+		if !route.Enabled {
+			disabled = append(disabled, route.Route)
+		}
+	}
+
+	return disabled
+}
+
+// Middleware answers with 503 (ServiceUnavailable) the requests to the
+// disabled routes, before the tools are called.
+func Middleware(t *Toggles) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !t.Enabled(c.FullPath()) {
+			apierrors.Abort(c, apierrors.New(http.StatusServiceUnavailable, apierrors.CodeRouteDisabled, "the route is disabled"))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToggles(t *testing.T) {
+	// arrange
+	toggles := NewToggles()
+	toggles.Add("/v1/programming/uuid")
+	toggles.Add("/v1/internet/medium-to-md")
+
+	// act
+	err := toggles.Set("/v1/internet/medium-to-md", false)
+	unknownErr := toggles.Set("/v1/unknown", false)
+
+	// assert
+	assert.Nil(t, err)
+	assert.NotNil(t, unknownErr)
+	assert.True(t, toggles.Enabled("/v1/programming/uuid"))
+	assert.False(t, toggles.Enabled("/v1/internet/medium-to-md"))
+	assert.True(t, toggles.Enabled("/v1/unknown"))
+	assert.Equal(t, []RouteOutput{
+		{Route: "/v1/internet/medium-to-md", Enabled: false},
+		{Route: "/v1/programming/uuid", Enabled: true},
+	}, toggles.Routes())
+	assert.Equal(t, []string{"/v1/internet/medium-to-md"}, toggles.Disabled())
+}
+
+func TestTogglesFollowTheRoutesOfTheAliases(t *testing.T) {
+	// arrange
+	toggles := NewToggles()
+	toggles.Add("/v1/programming/uuid")
+	toggles.Alias("Query.uuid", "/v1/programming/uuid")
+	toggles.Alias("/canivete.v1.ProgrammingService/NewUuid", "/v1/programming/uuid")
+
+	// act
+	err := toggles.Set("/v1/programming/uuid", false)
+	aliasErr := toggles.Set("Query.uuid", true)
+
+	// assert
+	assert.Nil(t, err)
+	assert.NotNil(t, aliasErr)
+	assert.False(t, toggles.Enabled("Query.uuid"))
+	assert.False(t, toggles.Enabled("/canivete.v1.ProgrammingService/NewUuid"))
+	assert.True(t, toggles.Enabled("Query.jwt"))
+	assert.Equal(t, []RouteOutput{{Route: "/v1/programming/uuid", Enabled: false}}, toggles.Routes())
+}
+
+func TestMiddlewareAnswersTheDisabledRoutes(t *testing.T) {
+	// arrange
+	toggles := NewToggles()
+	toggles.Add("/v1/tools/:name")
+	toggles.Set("/v1/tools/:name", false)
+	r := gin.New()
+	r.GET("/v1/tools/:name", Middleware(toggles), func(c *gin.Context) {
+		c.String(http.StatusOK, "called")
+	})
+	r.GET("/v1/other", Middleware(toggles), func(c *gin.Context) {
+		c.String(http.StatusOK, "called")
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// act
+	disabled := serve("/v1/tools/uuid")
+	other := serve("/v1/other")
+
+	// assert
+	assert.Equal(t, http.StatusServiceUnavailable, disabled.Code)
+	apiError, err := apierrors.FromResponse(disabled.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeRouteDisabled, apiError.Code)
+	assert.Equal(t, http.StatusOK, other.Code)
+}
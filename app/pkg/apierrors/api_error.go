@@ -18,19 +18,160 @@ package apierrors
 
 import (
 	"encoding/json"
-	"net/http/httptest"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/renato0307/canivete-api/pkg/requestid"
+)
+
+// ContentType is the media type of the error responses (RFC 7807).
+const ContentType = "application/problem+json"
+
+// typePrefix prefixes the code to build the problem type URI.
+const typePrefix = "urn:canivete-api:problem:"
+
+// Codes identifying each kind of error. They are stable: clients can rely
+// on them instead of parsing the detail.
+const (
+	CodeInvalidBody          = "invalid-body"
+	CodeValidationFailed     = "validation-failed"
+	CodeInvalidUnixTimestamp = "invalid-unix-timestamp"
+	CodeInvalidToken         = "invalid-token"
+	CodeCalculationFailed    = "calculation-failed"
+	CodeConversionFailed     = "conversion-failed"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not-found"
+	CodeNotAcceptable        = "not-acceptable"
+	CodeRateLimited          = "rate-limited"
+	CodeBodyTooLarge         = "body-too-large"
+	CodeBatchTooLarge        = "batch-too-large"
+	CodeQueryTooComplex      = "query-too-complex"
+	CodeTimeout              = "timeout"
+	CodeMethodNotAllowed     = "method-not-allowed"
+	CodeRouteDisabled        = "route-disabled"
+	CodeInternal             = "internal-error"
 )
 
+// ApiError is an RFC 7807 problem details object, returned by every
+// endpoint on failure.
 type ApiError struct {
-	Message string
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestId string       `json:"requestId,omitempty"`
+}
+
+// FieldError describes why a field of the request is invalid.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error makes ApiError usable as a Go error.
+func (e ApiError) Error() string {
+	if e.Detail == "" {
+		return fmt.Sprintf("%d %s (%s)", e.Status, e.Title, e.Code)
+	}
+
+	return fmt.Sprintf("%d %s (%s): %s", e.Status, e.Title, e.Code, e.Detail)
+}
+
+// New creates an error with the status, code and detail.
+func New(status int, code string, detail string) ApiError {
+	return ApiError{
+		Type:   typePrefix + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// BadRequest creates a 400 (BadRequest) error.
+func BadRequest(code string, detail string) ApiError {
+	return New(http.StatusBadRequest, code, detail)
+}
+
+// Internal creates a 500 (InternalServerError) error.
+func Internal(code string, detail string) ApiError {
+	return New(http.StatusInternalServerError, code, detail)
 }
 
-func FromResponseRecorder(w *httptest.ResponseRecorder) (ApiError, error) {
+// Validation creates a 400 (BadRequest) error from the errors returned by
+// the validator, with one FieldError per invalid field.
+func Validation(err error) ApiError {
+	apiError := BadRequest(CodeValidationFailed, "request body has invalid fields")
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		apiError.Detail = err.Error()
+		return apiError
+	}
+
+	for _, fieldError := range validationErrors {
+		apiError.Errors = append(apiError.Errors, FieldError{
+			Field:   fieldError.Field(),
+			Rule:    fieldError.Tag(),
+			Param:   fieldError.Param(),
+			Message: fieldMessage(fieldError),
+		})
+	}
+
+	return apiError
+}
+
+func fieldMessage(fieldError validator.FieldError) string {
+	switch fieldError.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fieldError.Field())
+	case "required_with":
+		return fmt.Sprintf("%s is required when %s is set", fieldError.Field(), fieldError.Param())
+	case "gt":
+		return fmt.Sprintf("%s must be greater than %s", fieldError.Field(), fieldError.Param())
+	default:
+		return fmt.Sprintf("%s failed on the %s rule", fieldError.Field(), fieldError.Tag())
+	}
+}
+
+// Abort stops the request and writes the error as application/problem+json.
+func Abort(c *gin.Context, apiError ApiError) {
+	apiError.Instance = c.Request.URL.Path
+	apiError.RequestId = requestid.Get(c)
+
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(apiError.Status, apiError)
+}
+
+// Decode reads an error from a response body.
+func Decode(body io.Reader) (ApiError, error) {
 	apiError := ApiError{}
-	err := json.Unmarshal(w.Body.Bytes(), &apiError)
+	err := json.NewDecoder(body).Decode(&apiError)
 	if err != nil {
 		return apiError, err
 	}
 
 	return apiError, nil
 }
+
+// FromResponse reads the error returned in an http response.
+// It fails if the response is not an RFC 7807 problem.
+func FromResponse(resp *http.Response) (ApiError, error) {
+	contentType := resp.Header.Get("Content-Type")
+	if !strings.HasPrefix(contentType, ContentType) {
+		return ApiError{}, fmt.Errorf("unexpected content type %q for an error", contentType)
+	}
+
+	return Decode(resp.Body)
+}
@@ -18,28 +18,110 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package apierrors
 
 import (
-	"bytes"
-	"fmt"
+	"errors"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/renato0307/canivete-api/pkg/requestid"
 	"github.com/stretchr/testify/assert"
 )
 
-func TestFromResponseRecorder(t *testing.T) {
-	message := "fake error"
-	errorText := fmt.Sprintf("{\"Message\":\"%s\"}", message)
-	response := httptest.ResponseRecorder{
-		Body: bytes.NewBuffer([]byte(errorText)),
-	}
+func TestNew(t *testing.T) {
+	// act
+	apiError := New(http.StatusNotFound, CodeNotFound, "fake detail")
 
-	apiError, _ := FromResponseRecorder(&response)
+	// assert
+	assert.Equal(t, "urn:canivete-api:problem:not-found", apiError.Type)
+	assert.Equal(t, "Not Found", apiError.Title)
+	assert.Equal(t, http.StatusNotFound, apiError.Status)
+	assert.Equal(t, CodeNotFound, apiError.Code)
+	assert.Equal(t, "fake detail", apiError.Detail)
+}
+
+func TestBadRequestAndInternal(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, BadRequest(CodeInvalidBody, "").Status)
+	assert.Equal(t, http.StatusInternalServerError, Internal(CodeInternal, "").Status)
+}
+
+func TestError(t *testing.T) {
+	assert.Equal(t, "400 Bad Request (invalid-body): fake detail", BadRequest(CodeInvalidBody, "fake detail").Error())
+	assert.Equal(t, "500 Internal Server Error (internal-error)", Internal(CodeInternal, "").Error())
+}
+
+func TestValidation(t *testing.T) {
+	// arrange
+	input := struct {
+		Amount       float64 `validate:"required"`
+		Contribution float64
+		Period       float64 `validate:"gt=0,required_with=Contribution"`
+	}{Contribution: 100}
+	err := validator.New().Struct(input)
+
+	// act
+	apiError := Validation(err)
+
+	// assert
+	assert.Equal(t, http.StatusBadRequest, apiError.Status)
+	assert.Equal(t, CodeValidationFailed, apiError.Code)
+	assert.Equal(t, []FieldError{
+		{Field: "Amount", Rule: "required", Message: "Amount is required"},
+		{Field: "Period", Rule: "gt", Param: "0", Message: "Period must be greater than 0"},
+	}, apiError.Errors)
+}
+
+func TestValidationWithOtherError(t *testing.T) {
+	// act
+	apiError := Validation(errors.New("fake error"))
 
-	assert.Equal(t, message, apiError.Message)
+	// assert
+	assert.Equal(t, CodeValidationFailed, apiError.Code)
+	assert.Equal(t, "fake error", apiError.Detail)
+	assert.Empty(t, apiError.Errors)
 }
 
-func TestFromResponseRecorderWithInvalidData(t *testing.T) {
-	response := httptest.ResponseRecorder{Body: bytes.NewBuffer([]byte(""))}
-	_, err := FromResponseRecorder(&response)
+func TestAbort(t *testing.T) {
+	// arrange
+	r := gin.Default()
+	r.Use(requestid.Middleware(requestid.DefaultHeader))
+	r.GET("/v1/tools", func(c *gin.Context) {
+		Abort(c, BadRequest(CodeInvalidBody, "fake detail"))
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/tools", nil)
+	req.Header.Set(requestid.DefaultHeader, "fake-request-id")
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+
+	apiError, err := FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, "/v1/tools", apiError.Instance)
+	assert.Equal(t, "fake-request-id", apiError.RequestId)
+	assert.Equal(t, "fake detail", apiError.Detail)
+}
+
+func TestDecodeWithInvalidData(t *testing.T) {
+	_, err := Decode(strings.NewReader(""))
+	assert.NotNil(t, err)
+}
+
+func TestFromResponseWithOtherContentType(t *testing.T) {
+	// arrange
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteString(`{"code":"not-found"}`)
+
+	// act
+	_, err := FromResponse(w.Result())
+
+	// assert
 	assert.NotNil(t, err)
 }
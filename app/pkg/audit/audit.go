@@ -0,0 +1,289 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"go.uber.org/zap"
+)
+
+var logger *zap.SugaredLogger = logging.GetLogger()
+
+// Modes of the inputs: kept as is, replaced by their SHA-256 or by
+// [redacted].
+const (
+	Keep   = config.AuditKeep
+	Hash   = config.AuditHash
+	Redact = config.AuditRedact
+)
+
+// Outcomes of the calls.
+const (
+	OutcomeSuccess = "success"
+	// OutcomeRejected is the outcome of the calls the tools refused, like
+	// the invalid inputs.
+	OutcomeRejected = "rejected"
+	OutcomeFailed   = "failed"
+)
+
+// Transports of the calls.
+const (
+	TransportHttp    = "http"
+	TransportGrpc    = "grpc"
+	TransportGraphql = "graphql"
+)
+
+// redacted replaces the inputs with the redact mode.
+const redacted = "[redacted]"
+
+// stdout is where the stdout sink writes; tests replace it.
+var stdout io.Writer = os.Stdout
+
+// Record is an entry of the audit log: who called which tool, when, with
+// which outcome and which inputs.
+type Record struct {
+	Time       time.Time `json:"time"`
+	RequestId  string    `json:"requestId,omitempty"`
+	Caller     string    `json:"caller,omitempty"`
+	AuthMethod string    `json:"authMethod,omitempty"`
+	ClientCert string    `json:"clientCert,omitempty"`
+	Client     string    `json:"client,omitempty"`
+	Transport  string    `json:"transport"`
+	Method     string    `json:"method,omitempty"`
+	// Route is the route template, the full gRPC method or the GraphQL
+	// field, like Query.jwt.
+	Route string `json:"route"`
+	// Status is the http status, or the gRPC code for the gRPC calls.
+	Status     string `json:"status"`
+	Outcome    string `json:"outcome"`
+	DurationMs int64  `json:"durationMs"`
+	// Inputs are the inputs of the call, by name, with the mode of their
+	// rule applied: body, or the name of a parameter or of a field.
+	Inputs map[string]string `json:"inputs,omitempty"`
+}
+
+// Policy is how the inputs of a route are recorded.
+type Policy struct {
+	// Inputs are the modes of the inputs, by name. The others use the
+	// configured redaction.
+	Inputs map[string]string
+	// Secrets are the inputs never kept, whatever the configuration: they
+	// are hashed instead.
+	Secrets []string
+}
+
+func (p Policy) secret(input string) bool {
+	for _, secret := range p.Secrets {
+		if secret == input {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Policies keeps the audit policy of each route.
+type Policies struct {
+	mu       sync.Mutex
+	policies map[string]Policy
+}
+
+// NewPolicies returns an empty policy registry, where every input uses the
+// configured redaction.
+func NewPolicies() *Policies {
+	return &Policies{policies: map[string]Policy{}}
+}
+
+// Set sets the policy of the route.
+func (p *Policies) Set(route string, policy Policy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.policies[route] = policy
+}
+
+// Get returns the policy of the route, if it was set.
+func (p *Policies) Get(route string) (Policy, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	policy, ok := p.policies[route]
+	return policy, ok
+}
+
+var defaultPolicies = NewPolicies()
+
+// SetPolicy sets the policy of a route registered on the group with the
+// relative path in the default registry.
+func SetPolicy(group *gin.RouterGroup, relativePath string, policy Policy) {
+	defaultPolicies.Set(strings.TrimSuffix(group.BasePath(), "/")+relativePath, policy)
+}
+
+// SetRoutePolicy sets the policy of a route which is not a gin route in
+// the default registry: a full gRPC method, like
+// /canivete.v1.ProgrammingService/DebugJwt, or a GraphQL field, like
+// Query.jwt.
+func SetRoutePolicy(route string, policy Policy) {
+	defaultPolicies.Set(route, policy)
+}
+
+// DefaultPolicies returns the registry where the service groups set the
+// policies of their routes.
+func DefaultPolicies() *Policies {
+	return defaultPolicies
+}
+
+// Auditor writes the records of the calls in a sink, with the inputs
+// redacted by the policies of their routes.
+type Auditor struct {
+	sink      Sink
+	policies  *Policies
+	redaction string
+	overrides map[string]map[string]string
+}
+
+// New returns an auditor writing in the sink. The configured rules
+// override the modes of the policies.
+func New(cfg config.AuditConfig, sink Sink, policies *Policies) *Auditor {
+	return &Auditor{
+		sink:      sink,
+		policies:  policies,
+		redaction: cfg.Redaction,
+		overrides: cfg.Routes,
+	}
+}
+
+// Sink returns the sink of the auditor.
+func (a *Auditor) Sink() Sink {
+	return a.sink
+}
+
+// Record writes the record with the inputs of the call. The calls are not
+// failed when the sink cannot be written, the errors are logged.
+func (a *Auditor) Record(record Record, inputs map[string]string) {
+	record.Inputs = a.redact(record.Route, inputs)
+
+	err := a.sink.Write(record)
+	if err != nil {
+		logger.Errorw("error writing the audit record", "route", record.Route, "error", err.Error())
+	}
+}
+
+func (a *Auditor) redact(route string, inputs map[string]string) map[string]string {
+	if len(inputs) == 0 {
+		return nil
+	}
+
+	policy, _ := a.policies.Get(route)
+	redactedInputs := map[string]string{}
+	for name, value := range inputs {
+		mode := a.redaction
+		if policyMode, ok := policy.Inputs[name]; ok {
+			mode = policyMode
+		}
+		if configuredMode, ok := a.overrides[route][name]; ok {
+			mode = configuredMode
+		}
+		if mode == Keep && policy.secret(name) {
+			mode = Hash
+		}
+
+		switch mode {
+		case Keep:
+			redactedInputs[name] = value
+		case Redact:
+			redactedInputs[name] = redacted
+		default:
+			redactedInputs[name] = Digest(value)
+		}
+	}
+
+	return redactedInputs
+}
+
+// Digest returns the digest recorded for an input with the hash mode, so
+// the calls with a known input can be found.
+func Digest(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Outcome returns the outcome of a call answered with the http status.
+func Outcome(status int) string {
+	switch {
+	case status >= 500:
+		return OutcomeFailed
+	case status >= 400:
+		return OutcomeRejected
+	}
+
+	return OutcomeSuccess
+}
+
+var (
+	mu             sync.RWMutex
+	defaultAuditor *Auditor
+)
+
+// Setup makes the default auditor write in the sink of the configuration,
+// with the policies of the default registry. The returned function closes
+// the sink and must be called before exiting.
+func Setup(cfg config.AuditConfig) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		SetDefault(nil)
+		return func(context.Context) error { return nil }, nil
+	}
+
+	sink, err := NewSink(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	SetDefault(New(cfg, sink, DefaultPolicies()))
+	return func(context.Context) error {
+		return sink.Close()
+	}, nil
+}
+
+// SetDefault sets the auditor used by the middlewares. The calls are not
+// recorded when it is nil.
+func SetDefault(auditor *Auditor) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	defaultAuditor = auditor
+}
+
+// Default returns the auditor set by Setup, or nil when the calls are not
+// recorded.
+func Default() *Auditor {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return defaultAuditor
+}
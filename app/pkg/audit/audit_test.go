@@ -0,0 +1,176 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package audit
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func testConfig() config.AuditConfig {
+	cfg := config.Default().Audit
+	cfg.Enabled = true
+	cfg.Sink = "memory"
+	return cfg
+}
+
+func TestRecordHashesTheInputsByDefault(t *testing.T) {
+	// arrange
+	ring := NewRing(10)
+	auditor := New(testConfig(), ring, NewPolicies())
+
+	// act
+	auditor.Record(Record{Route: "/v1/programming/slug"}, map[string]string{"body": "hello"})
+
+	// assert
+	records := ring.Records(func(Record) bool { return true })
+	assert.Len(t, records, 1)
+	assert.Equal(t, Digest("hello"), records[0].Inputs["body"])
+}
+
+func TestRecordAppliesThePolicyOfTheRoute(t *testing.T) {
+	// arrange
+	policies := NewPolicies()
+	policies.Set("/v1/internet/medium-to-md", Policy{Inputs: map[string]string{"body": Keep, "format": Redact}})
+	ring := NewRing(10)
+	auditor := New(testConfig(), ring, policies)
+
+	// act
+	auditor.Record(Record{Route: "/v1/internet/medium-to-md"}, map[string]string{"body": "123", "format": "json", "other": "x"})
+
+	// assert
+	inputs := ring.Records(func(Record) bool { return true })[0].Inputs
+	assert.Equal(t, "123", inputs["body"])
+	assert.Equal(t, "[redacted]", inputs["format"])
+	assert.Equal(t, Digest("x"), inputs["other"])
+}
+
+func TestRecordAppliesTheConfiguredRules(t *testing.T) {
+	// arrange
+	cfg := testConfig()
+	cfg.Routes = map[string]map[string]string{"/v1/internet/medium-to-md": {"body": Redact}}
+	policies := NewPolicies()
+	policies.Set("/v1/internet/medium-to-md", Policy{Inputs: map[string]string{"body": Keep}})
+	ring := NewRing(10)
+	auditor := New(cfg, ring, policies)
+
+	// act
+	auditor.Record(Record{Route: "/v1/internet/medium-to-md"}, map[string]string{"body": "123"})
+
+	// assert
+	assert.Equal(t, "[redacted]", ring.Records(func(Record) bool { return true })[0].Inputs["body"])
+}
+
+func TestRecordNeverKeepsTheSecrets(t *testing.T) {
+	// arrange
+	cfg := testConfig()
+	cfg.Redaction = Keep
+	cfg.Routes = map[string]map[string]string{"/v1/programming/jwt-debugger": {"body": Keep}}
+	policies := NewPolicies()
+	policies.Set("/v1/programming/jwt-debugger", Policy{Secrets: []string{"body"}})
+	ring := NewRing(10)
+	auditor := New(cfg, ring, policies)
+
+	// act
+	auditor.Record(Record{Route: "/v1/programming/jwt-debugger"}, map[string]string{"body": "token", "format": "json"})
+
+	// assert
+	inputs := ring.Records(func(Record) bool { return true })[0].Inputs
+	assert.Equal(t, Digest("token"), inputs["body"])
+	assert.Equal(t, "json", inputs["format"])
+}
+
+func TestDigest(t *testing.T) {
+	// act
+	digest := Digest("hello")
+
+	// assert
+	assert.Equal(t, "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", digest)
+}
+
+func TestOutcome(t *testing.T) {
+	testCases := []struct {
+		status  int
+		outcome string
+	}{
+		{200, OutcomeSuccess},
+		{304, OutcomeSuccess},
+		{400, OutcomeRejected},
+		{403, OutcomeRejected},
+		{500, OutcomeFailed},
+		{504, OutcomeFailed},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.outcome, Outcome(tc.status), tc.status)
+	}
+}
+
+func TestSetupWithFileSink(t *testing.T) {
+	// arrange
+	cfg := testConfig()
+	cfg.Sink = "file"
+	cfg.File = filepath.Join(t.TempDir(), "audit.log")
+	defer SetDefault(nil)
+
+	// act
+	shutdown, err := Setup(cfg)
+	Default().Record(Record{Route: "/v1/programming/uuid", Outcome: OutcomeSuccess}, nil)
+	Default().Record(Record{Route: "/v1/programming/slug", Outcome: OutcomeSuccess}, nil)
+	closeErr := shutdown(context.Background())
+
+	// assert
+	assert.NoError(t, err)
+	assert.NoError(t, closeErr)
+	content, _ := ioutil.ReadFile(cfg.File)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"route":"/v1/programming/uuid"`)
+	assert.Contains(t, lines[1], `"route":"/v1/programming/slug"`)
+}
+
+func TestSetupWhenDisabled(t *testing.T) {
+	// arrange
+	SetDefault(New(testConfig(), NewRing(1), NewPolicies()))
+
+	// act
+	shutdown, err := Setup(config.Default().Audit)
+
+	// assert
+	assert.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Nil(t, Default())
+}
+
+func TestSetupWithInvalidFile(t *testing.T) {
+	// arrange
+	cfg := testConfig()
+	cfg.Sink = "file"
+	cfg.File = filepath.Join(t.TempDir(), "missing", "audit.log")
+
+	// act
+	_, err := Setup(cfg)
+
+	// assert
+	assert.Error(t, err)
+}
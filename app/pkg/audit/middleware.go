@@ -0,0 +1,156 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package audit
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/certs"
+	"github.com/renato0307/canivete-api/pkg/requestid"
+)
+
+// defaultLimit is the number of records listed when the limit is not set.
+const defaultLimit = 100
+
+// RecordsOutput is the answer of the audit endpoint.
+type RecordsOutput struct {
+	Records []Record `json:"records"`
+}
+
+// Middleware records the calls of the routes with the default auditor.
+// The inputs are the body and the path and query parameters. It runs
+// before the authentication and the other checks, so the calls they
+// refuse are recorded too: the caller is read once the call is answered
+// and the body is recorded as the next handlers read it, so it is never
+// read past the limits of the route.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		auditor := Default()
+		if auditor == nil {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		var body *bodyRecorder
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			body = &bodyRecorder{ReadCloser: c.Request.Body}
+			c.Request.Body = body
+		}
+
+		c.Next()
+
+		record := Record{
+			Time:       start.UTC(),
+			RequestId:  requestid.Get(c),
+			Client:     c.ClientIP(),
+			Transport:  TransportHttp,
+			Method:     c.Request.Method,
+			Route:      c.FullPath(),
+			Status:     strconv.Itoa(c.Writer.Status()),
+			Outcome:    Outcome(c.Writer.Status()),
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if identity, ok := auth.GetIdentity(c); ok {
+			record.Caller = identity.Name
+			record.AuthMethod = identity.Method
+		}
+		if identity, ok := certs.GetClientIdentity(c); ok {
+			record.ClientCert = identity.Name()
+		}
+		auditor.Record(record, readInputs(c, body))
+	}
+}
+
+// bodyRecorder keeps what is read of a body.
+type bodyRecorder struct {
+	io.ReadCloser
+	read bytes.Buffer
+}
+
+func (b *bodyRecorder) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read.Write(p[:n])
+	return n, err
+}
+
+func readInputs(c *gin.Context, body *bodyRecorder) map[string]string {
+	inputs := map[string]string{}
+
+	if body != nil && body.read.Len() > 0 {
+		inputs["body"] = body.read.String()
+	}
+
+	for _, param := range c.Params {
+		inputs[param.Key] = param.Value
+	}
+	for name, values := range c.Request.URL.Query() {
+		inputs[name] = strings.Join(values, ",")
+	}
+
+	return inputs
+}
+
+// SetRouterGroup adds to the base group the endpoint listing the records
+// kept by the memory sink of the default auditor, from the newest. They
+// can be filtered by caller and route.
+func SetRouterGroup(base *gin.RouterGroup) *gin.RouterGroup {
+	base.GET("/audit", getRecords)
+	return base
+}
+
+// getRecords handles the audit request.
+// It returns 200 on success, 400 for an invalid limit and 404 when the
+// records are not kept in memory.
+func getRecords(c *gin.Context) {
+	var ring *Ring
+	if auditor := Default(); auditor != nil {
+		ring, _ = auditor.Sink().(*Ring)
+	}
+	if ring == nil {
+		apierrors.Abort(c, apierrors.New(http.StatusNotFound, apierrors.CodeNotFound, "the audit records are not kept in memory"))
+		return
+	}
+
+	limit := defaultLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeValidationFailed, "limit must be a positive integer"))
+			return
+		}
+		limit = parsed
+	}
+
+	caller, route := c.Query("caller"), c.Query("route")
+	records := ring.Records(func(record Record) bool {
+		return (caller == "" || record.Caller == caller) && (route == "" || record.Route == route)
+	})
+	if len(records) > limit {
+		records = records[:limit]
+	}
+
+	c.JSON(http.StatusOK, RecordsOutput{Records: records})
+}
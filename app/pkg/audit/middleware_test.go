@@ -0,0 +1,222 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGin() (*gin.Engine, *Ring) {
+	ring := NewRing(10)
+	policies := NewPolicies()
+	policies.Set("/v1/tools/:name", Policy{Inputs: map[string]string{"name": Keep, "format": Keep}})
+	SetDefault(New(testConfig(), ring, policies))
+
+	r := gin.New()
+	v1 := r.Group("/v1", func(c *gin.Context) {
+		if caller := c.GetHeader("X-Caller"); caller != "" {
+			auth.SetIdentity(c, auth.Identity{Name: caller, Method: "api-key"})
+		}
+	}, Middleware())
+	v1.POST("/tools/:name", func(c *gin.Context) {
+		if c.Request.Body != nil {
+			c.GetRawData()
+		}
+		if c.Param("name") == "invalid" {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.String(http.StatusOK, "done")
+	})
+	SetRouterGroup(r.Group("/admin"))
+
+	return r, ring
+}
+
+func TestMiddlewareRecordsTheCall(t *testing.T) {
+	// arrange
+	r, ring := setupGin()
+	defer SetDefault(nil)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/tools/slug?format=json", strings.NewReader("hello"))
+	req.Header.Set("X-Caller", "ci")
+	w := httptest.NewRecorder()
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	records := ring.Records(func(Record) bool { return true })
+	assert.Len(t, records, 1)
+	assert.Equal(t, "ci", records[0].Caller)
+	assert.Equal(t, "api-key", records[0].AuthMethod)
+	assert.Equal(t, TransportHttp, records[0].Transport)
+	assert.Equal(t, http.MethodPost, records[0].Method)
+	assert.Equal(t, "/v1/tools/:name", records[0].Route)
+	assert.Equal(t, "200", records[0].Status)
+	assert.Equal(t, OutcomeSuccess, records[0].Outcome)
+	assert.Equal(t, map[string]string{"body": Digest("hello"), "name": "slug", "format": "json"}, records[0].Inputs)
+}
+
+func TestMiddlewareKeepsTheBodyForTheHandler(t *testing.T) {
+	// arrange
+	ring := NewRing(10)
+	SetDefault(New(testConfig(), ring, NewPolicies()))
+	defer SetDefault(nil)
+	r := gin.New()
+	r.POST("/echo", Middleware(), func(c *gin.Context) {
+		body, _ := c.GetRawData()
+		c.String(http.StatusOK, string(body))
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/echo", strings.NewReader("hello"))
+	w := httptest.NewRecorder()
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, "hello", w.Body.String())
+}
+
+func TestMiddlewareRecordsTheCallsRefusedBeforeReadingTheBody(t *testing.T) {
+	// arrange
+	ring := NewRing(10)
+	SetDefault(New(testConfig(), ring, NewPolicies()))
+	defer SetDefault(nil)
+	r := gin.New()
+	r.POST("/tools", Middleware(), func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	})
+	req, _ := http.NewRequest(http.MethodPost, "/tools", strings.NewReader("hello"))
+
+	// act
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	// assert
+	records := ring.Records(func(Record) bool { return true })
+	assert.Len(t, records, 1)
+	assert.Equal(t, "401", records[0].Status)
+	assert.Equal(t, OutcomeRejected, records[0].Outcome)
+	assert.Empty(t, records[0].Inputs)
+}
+
+func TestMiddlewareWhenDisabled(t *testing.T) {
+	// arrange
+	r, ring := setupGin()
+	SetDefault(nil)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/tools/slug", nil)
+	w := httptest.NewRecorder()
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, ring.Records(func(Record) bool { return true }))
+}
+
+func TestGetRecords(t *testing.T) {
+	// arrange
+	r, _ := setupGin()
+	defer SetDefault(nil)
+	for _, call := range []struct{ caller, name string }{{"ci", "slug"}, {"portal", "slug"}, {"ci", "invalid"}, {"ci", "uuid"}} {
+		req, _ := http.NewRequest(http.MethodPost, "/v1/tools/"+call.name, nil)
+		req.Header.Set("X-Caller", call.caller)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	testCases := []struct {
+		query string
+		names []string
+	}{
+		{"", []string{"uuid", "invalid", "slug", "slug"}},
+		{"?caller=ci", []string{"uuid", "invalid", "slug"}},
+		{"?caller=ci&limit=2", []string{"uuid", "invalid"}},
+		{"?caller=nobody", []string{}},
+	}
+
+	for _, tc := range testCases {
+		// act
+		req, _ := http.NewRequest(http.MethodGet, "/admin/audit"+tc.query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Equal(t, http.StatusOK, w.Code, tc.query)
+		output := RecordsOutput{}
+		json.Unmarshal(w.Body.Bytes(), &output)
+		names := []string{}
+		for _, record := range output.Records {
+			names = append(names, record.Inputs["name"])
+		}
+		assert.Equal(t, tc.names, names, tc.query)
+	}
+}
+
+func TestGetRecordsOfRejectedCalls(t *testing.T) {
+	// arrange
+	r, ring := setupGin()
+	defer SetDefault(nil)
+	req, _ := http.NewRequest(http.MethodPost, "/v1/tools/invalid", nil)
+
+	// act
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	// assert
+	records := ring.Records(func(Record) bool { return true })
+	assert.Equal(t, "400", records[0].Status)
+	assert.Equal(t, OutcomeRejected, records[0].Outcome)
+	assert.Empty(t, records[0].Caller)
+}
+
+func TestGetRecordsWithInvalidLimit(t *testing.T) {
+	// arrange
+	r, _ := setupGin()
+	defer SetDefault(nil)
+	req, _ := http.NewRequest(http.MethodGet, "/admin/audit?limit=0", nil)
+	w := httptest.NewRecorder()
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "validation-failed")
+}
+
+func TestGetRecordsWithoutMemorySink(t *testing.T) {
+	// arrange
+	r, _ := setupGin()
+	SetDefault(New(testConfig(), NewWriterSink(nopCloser{&strings.Builder{}}), NewPolicies()))
+	defer SetDefault(nil)
+	req, _ := http.NewRequest(http.MethodGet, "/admin/audit", nil)
+	w := httptest.NewRecorder()
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
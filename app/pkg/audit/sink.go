@@ -0,0 +1,139 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package audit
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/renato0307/canivete-api/pkg/config"
+)
+
+// Sink is where the records are written.
+type Sink interface {
+	Write(record Record) error
+	Close() error
+}
+
+// NewSink returns the sink of the configuration.
+func NewSink(cfg config.AuditConfig) (Sink, error) {
+	switch cfg.Sink {
+	case "file":
+		file, err := os.OpenFile(cfg.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return nil, err
+		}
+		return NewWriterSink(file), nil
+	case "memory":
+		return NewRing(cfg.BufferSize), nil
+	}
+
+	return NewWriterSink(nopCloser{stdout}), nil
+}
+
+// WriterSink writes the records as JSON lines.
+type WriterSink struct {
+	mu      sync.Mutex
+	writer  io.WriteCloser
+	encoder *json.Encoder
+}
+
+// NewWriterSink returns a sink writing in the writer, closed with the
+// sink.
+func NewWriterSink(writer io.WriteCloser) *WriterSink {
+	return &WriterSink{writer: writer, encoder: json.NewEncoder(writer)}
+}
+
+// Write writes the record on a line.
+func (s *WriterSink) Write(record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.encoder.Encode(record)
+}
+
+// Close closes the writer.
+func (s *WriterSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.writer.Close()
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// Ring keeps the last records in memory, served by the admin endpoint.
+type Ring struct {
+	mu      sync.Mutex
+	records []Record
+	next    int
+	full    bool
+}
+
+// NewRing returns a ring keeping the last size records.
+func NewRing(size int) *Ring {
+	return &Ring{records: make([]Record, size)}
+}
+
+// Write keeps the record, replacing the oldest one when the ring is full.
+func (r *Ring) Write(record Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[r.next] = record
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+
+	return nil
+}
+
+// Records returns the records kept, from the newest, which match the
+// filter.
+func (r *Ring) Records(match func(Record) bool) []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := r.next
+	if r.full {
+		count = len(r.records)
+	}
+
+	records := []Record{}
+	for i := 1; i <= count; i++ {
+		record := r.records[(r.next-i+len(r.records))%len(r.records)]
+		if match(record) {
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+// Close does nothing, the records stay readable.
+func (r *Ring) Close() error {
+	return nil
+}
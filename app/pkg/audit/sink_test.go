@@ -0,0 +1,88 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingKeepsTheLastRecords(t *testing.T) {
+	// arrange
+	ring := NewRing(2)
+
+	// act
+	ring.Write(Record{Route: "/a"})
+	ring.Write(Record{Route: "/b"})
+	ring.Write(Record{Route: "/c"})
+
+	// assert
+	records := ring.Records(func(Record) bool { return true })
+	assert.Equal(t, []Record{{Route: "/c"}, {Route: "/b"}}, records)
+}
+
+func TestRingFiltersTheRecords(t *testing.T) {
+	// arrange
+	ring := NewRing(10)
+	ring.Write(Record{Route: "/a", Caller: "ci"})
+	ring.Write(Record{Route: "/b", Caller: "portal"})
+	ring.Write(Record{Route: "/c", Caller: "ci"})
+
+	// act
+	records := ring.Records(func(record Record) bool { return record.Caller == "ci" })
+
+	// assert
+	assert.Equal(t, []Record{{Route: "/c", Caller: "ci"}, {Route: "/a", Caller: "ci"}}, records)
+}
+
+func TestWriterSinkWritesJsonLines(t *testing.T) {
+	// arrange
+	buffer := &bytes.Buffer{}
+	sink := NewWriterSink(nopCloser{buffer})
+
+	// act
+	sink.Write(Record{Route: "/a", Transport: TransportHttp, Status: "200", Outcome: OutcomeSuccess})
+	sink.Write(Record{Route: "/b", Transport: TransportGrpc, Status: "OK", Outcome: OutcomeSuccess})
+
+	// assert
+	decoder := json.NewDecoder(buffer)
+	first, second := Record{}, Record{}
+	assert.NoError(t, decoder.Decode(&first))
+	assert.NoError(t, decoder.Decode(&second))
+	assert.Equal(t, "/a", first.Route)
+	assert.Equal(t, TransportGrpc, second.Transport)
+	assert.Equal(t, "OK", second.Status)
+}
+
+func TestNewSinkDefaultsToStdout(t *testing.T) {
+	// arrange
+	buffer := &bytes.Buffer{}
+	stdout = buffer
+	cfg := testConfig()
+	cfg.Sink = "stdout"
+
+	// act
+	sink, err := NewSink(cfg)
+	sink.Write(Record{Route: "/a"})
+
+	// assert
+	assert.NoError(t, err)
+	assert.Contains(t, buffer.String(), `"route":"/a"`)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/renato0307/canivete-api/pkg/config"
+	"gopkg.in/yaml.v2"
+)
+
+// ApiKeyHeader is the header carrying the api key.
+const ApiKeyHeader = "X-API-Key"
+
+// MethodApiKey is the method of the identities authenticated by api key.
+const MethodApiKey = "api-key"
+
+// ApiKeyAuthenticator authenticates the requests with the api key in the
+// X-API-Key header.
+type ApiKeyAuthenticator struct {
+	// keys are indexed by hash, the keys themselves are never stored
+	keys map[string]config.ApiKeyConfig
+}
+
+// NewApiKeyAuthenticator returns an authenticator accepting the keys.
+func NewApiKeyAuthenticator(keys []config.ApiKeyConfig) (*ApiKeyAuthenticator, error) {
+	a := ApiKeyAuthenticator{keys: map[string]config.ApiKeyConfig{}}
+	for _, key := range keys {
+		err := key.Validate()
+		if err != nil {
+			return nil, err
+		}
+
+		hash, _ := hex.DecodeString(key.Hash)
+		a.keys[hex.EncodeToString(hash)] = key
+	}
+
+	return &a, nil
+}
+
+// Authenticate implements Authenticator.
+func (a *ApiKeyAuthenticator) Authenticate(r *http.Request) (Identity, error) {
+	apiKey := r.Header.Get(ApiKeyHeader)
+	if apiKey == "" {
+		return Identity{}, ErrNoCredentials
+	}
+
+	key, ok := a.keys[HashApiKey(apiKey)]
+	if !ok {
+		return Identity{}, errors.New("api key is invalid")
+	}
+
+	return Identity{Name: key.Name, Method: MethodApiKey, Scopes: key.Scopes}, nil
+}
+
+// Challenge implements Authenticator.
+func (a *ApiKeyAuthenticator) Challenge() string {
+	return fmt.Sprintf(`ApiKey realm="canivete-api", header=%q`, ApiKeyHeader)
+}
+
+// HashApiKey returns the hash of the key, as expected in the configuration.
+func HashApiKey(apiKey string) string {
+	hash := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(hash[:])
+}
+
+// LoadApiKeys reads the keys listed in a YAML file:
+//
+//	apiKeys:
+//	  - name: ci
+//	    hash: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+//	    scopes: [programming, datetime]
+func LoadApiKeys(path string) ([]config.ApiKeyConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading api keys file: %s", err.Error())
+	}
+
+	file := struct {
+		ApiKeys []config.ApiKeyConfig `yaml:"apiKeys"`
+	}{}
+	err = yaml.UnmarshalStrict(data, &file)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing api keys file %s: %s", path, err.Error())
+	}
+
+	return file.ApiKeys, nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"go.uber.org/zap"
+)
+
+var logger *zap.SugaredLogger = logging.GetLogger()
+
+// ErrNoCredentials is returned by an Authenticator when the request does
+// not carry its kind of credentials.
+var ErrNoCredentials = errors.New("no credentials")
+
+// identityKey is the key of the identity in the gin context.
+const identityKey = "canivete-api/identity"
+
+type contextKey struct{}
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	// Name identifies the caller in the logs.
+	Name string
+	// Method is the authentication method, like api-key.
+	Method string
+	// Scopes are the service groups the caller can call.
+	Scopes []string
+}
+
+// HasScope tells if the caller can call the service group, or the admin
+// endpoints for config.ScopeAdmin.
+func (i Identity) HasScope(scope string) bool {
+	for _, s := range i.Scopes {
+		if s == scope || (s == config.ScopeAll && scope != config.ScopeAdmin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Authenticator identifies the caller of a request from one kind of
+// credentials.
+type Authenticator interface {
+	// Authenticate returns ErrNoCredentials when the request carries none
+	// of the credentials it handles and another error when they are not
+	// valid.
+	Authenticate(r *http.Request) (Identity, error)
+	// Challenge is the WWW-Authenticate value for the credentials.
+	Challenge() string
+}
+
+// Middleware authenticates the requests with the first authenticator
+// finding its credentials. It answers 401 (Unauthorized) if there are none
+// or if they are invalid.
+func Middleware(authenticators ...Authenticator) gin.HandlerFunc {
+	challenges := []string{}
+	for _, authenticator := range authenticators {
+		challenges = append(challenges, authenticator.Challenge())
+	}
+	challenge := strings.Join(challenges, ", ")
+
+	return func(c *gin.Context) {
+		identity, err := Authenticate(authenticators, c.Request)
+		if errors.Is(err, ErrNoCredentials) {
+			unauthorized(c, challenge, "credentials are missing")
+			return
+		}
+		if err != nil {
+			logging.FromContext(c).Debugw("authentication failed", "error", err.Error())
+			unauthorized(c, challenge, err.Error())
+			return
+		}
+
+		SetIdentity(c, identity)
+		c.Next()
+	}
+}
+
+// Authenticate identifies the caller of the request with the first
+// authenticator finding its credentials. It returns ErrNoCredentials when
+// none does.
+func Authenticate(authenticators []Authenticator, r *http.Request) (Identity, error) {
+	for _, authenticator := range authenticators {
+		identity, err := authenticator.Authenticate(r)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+
+		return identity, err
+	}
+
+	return Identity{}, ErrNoCredentials
+}
+
+func unauthorized(c *gin.Context, challenge, detail string) {
+	c.Header("WWW-Authenticate", challenge)
+	apierrors.Abort(c, apierrors.New(http.StatusUnauthorized, apierrors.CodeUnauthorized, detail))
+}
+
+// RequireScope answers 403 (Forbidden) unless the caller has the scope.
+// It must run after Middleware.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, _ := GetIdentity(c)
+		if !identity.HasScope(scope) {
+			detail := "the credentials do not grant access to " + scope
+			apierrors.Abort(c, apierrors.New(http.StatusForbidden, apierrors.CodeForbidden, detail))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// SetIdentity sets the caller of the request and adds it to the request
+// logger.
+func SetIdentity(c *gin.Context, identity Identity) {
+	c.Set(identityKey, identity)
+	logging.With(c, "caller", identity.Name)
+}
+
+// NewContext returns a context carrying the caller, for the requests not
+// served by gin, like the gRPC calls.
+func NewContext(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// FromContext returns the caller set by NewContext, if any.
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(contextKey{}).(Identity)
+	return identity, ok
+}
+
+// GetIdentity returns the caller set by Middleware, if any.
+func GetIdentity(c *gin.Context) (Identity, bool) {
+	identity, ok := c.Value(identityKey).(Identity)
+	return identity, ok
+}
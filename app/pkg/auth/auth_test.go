@@ -0,0 +1,191 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package auth
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var testKeys = []config.ApiKeyConfig{
+	{Name: "dates", Hash: HashApiKey("dates-key"), Scopes: []string{config.GroupDatetime}},
+	{Name: "admin", Hash: HashApiKey("admin-key"), Scopes: []string{config.ScopeAll}},
+}
+
+func setupGin(t *testing.T) *gin.Engine {
+	apiKeys, err := NewApiKeyAuthenticator(testKeys)
+	assert.Nil(t, err)
+
+	r := gin.New()
+	authenticated := r.Group("/v1", Middleware(apiKeys))
+	authenticated.GET("/finance/tool", RequireScope(config.GroupFinance), func(c *gin.Context) {
+		identity, _ := GetIdentity(c)
+		c.String(http.StatusOK, identity.Name)
+	})
+	authenticated.GET("/datetime/tool", RequireScope(config.GroupDatetime), func(c *gin.Context) {
+		identity, _ := GetIdentity(c)
+		c.String(http.StatusOK, identity.Name)
+	})
+
+	return r
+}
+
+func serve(r *gin.Engine, path, apiKey string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	if apiKey != "" {
+		req.Header.Set(ApiKeyHeader, apiKey)
+	}
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestMiddlewareWithValidKey(t *testing.T) {
+	// arrange
+	r := setupGin(t)
+
+	// act
+	dates := serve(r, "/v1/datetime/tool", "dates-key")
+	admin := serve(r, "/v1/finance/tool", "admin-key")
+
+	// assert
+	assert.Equal(t, http.StatusOK, dates.Code)
+	assert.Equal(t, "dates", dates.Body.String())
+	assert.Equal(t, http.StatusOK, admin.Code)
+	assert.Equal(t, "admin", admin.Body.String())
+}
+
+func TestMiddlewareWithoutKey(t *testing.T) {
+	// arrange
+	r := setupGin(t)
+
+	// act
+	w := serve(r, "/v1/datetime/tool", "")
+
+	// assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Header().Get("WWW-Authenticate"), "ApiKey")
+
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeUnauthorized, apiError.Code)
+	assert.Equal(t, "credentials are missing", apiError.Detail)
+}
+
+func TestMiddlewareWithInvalidKey(t *testing.T) {
+	// arrange
+	r := setupGin(t)
+
+	// act
+	w := serve(r, "/v1/datetime/tool", "wrong-key")
+
+	// assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	apiError, _ := apierrors.FromResponse(w.Result())
+	assert.Equal(t, "api key is invalid", apiError.Detail)
+}
+
+func TestRequireScopeWithoutScope(t *testing.T) {
+	// arrange
+	r := setupGin(t)
+
+	// act
+	w := serve(r, "/v1/finance/tool", "dates-key")
+
+	// assert
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	apiError, _ := apierrors.FromResponse(w.Result())
+	assert.Equal(t, apierrors.CodeForbidden, apiError.Code)
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		scopes   []string
+		scope    string
+		expected bool
+	}{
+		{[]string{"datetime"}, "datetime", true},
+		{[]string{"datetime"}, "finance", false},
+		{[]string{config.ScopeAll}, "finance", true},
+		{[]string{config.ScopeAll}, config.ScopeAdmin, false},
+		{[]string{config.ScopeAdmin}, config.ScopeAdmin, true},
+		{[]string{config.ScopeAdmin}, "finance", false},
+	}
+
+	for _, test := range tests {
+		// arrange
+		identity := Identity{Name: "ci", Scopes: test.scopes}
+
+		// act
+		hasScope := identity.HasScope(test.scope)
+
+		// assert
+		assert.Equal(t, test.expected, hasScope, "%v with %s", test.scopes, test.scope)
+	}
+}
+
+func TestNewApiKeyAuthenticatorWithInvalidKey(t *testing.T) {
+	tests := []config.ApiKeyConfig{
+		{Name: "", Hash: HashApiKey("key"), Scopes: []string{config.ScopeAll}},
+		{Name: "plain", Hash: "key", Scopes: []string{config.ScopeAll}},
+		{Name: "unknown", Hash: HashApiKey("key"), Scopes: []string{"weather"}},
+	}
+
+	for _, key := range tests {
+		_, err := NewApiKeyAuthenticator([]config.ApiKeyConfig{key})
+		assert.NotNil(t, err, "expected error for %v", key)
+	}
+}
+
+func TestLoadApiKeys(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	content := "apiKeys:\n  - name: ci\n    hash: " + HashApiKey("ci-key") + "\n    scopes: [programming]\n"
+	assert.Nil(t, ioutil.WriteFile(path, []byte(content), 0600))
+
+	// act
+	keys, err := LoadApiKeys(path)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, []config.ApiKeyConfig{
+		{Name: "ci", Hash: HashApiKey("ci-key"), Scopes: []string{"programming"}},
+	}, keys)
+}
+
+func TestLoadApiKeysWithUnknownField(t *testing.T) {
+	// arrange
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("apiKeys:\n  - name: ci\n    key: secret\n"), 0600))
+
+	// act
+	_, err := LoadApiKeys(path)
+
+	// assert
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,297 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval limits the refreshes triggered by tokens signed with
+// unknown keys.
+const minRefreshInterval = 30 * time.Second
+
+// maxRetryInterval bounds the wait between the refreshes after failed ones,
+// which doubles with each failure from minRefreshInterval.
+const maxRetryInterval = 10 * time.Minute
+
+// jwk is a JSON Web Key (RFC 7517), restricted to the public keys used to
+// sign tokens.
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// Jwks caches the keys of a JSON Web Key Set. The keys are read again
+// when the refresh interval expires or when a token is signed by an
+// unknown key, so the keys rotated by the provider are picked up.
+//
+// A single refresh runs at a time, outside the lock, and the known keys
+// are served while it runs. After a failed refresh, the next one waits
+// longer with each failure.
+type Jwks struct {
+	read            func(ctx context.Context) ([]byte, error)
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+	// refreshing is closed when the refresh in flight ends, nil when none
+	// is running.
+	refreshing chan struct{}
+	failures   int
+	retryAt    time.Time
+	lastErr    error
+}
+
+// NewJwksFromUrl returns the keys served on the url.
+func NewJwksFromUrl(url string, refreshInterval time.Duration) *Jwks {
+	client := &http.Client{Timeout: 10 * time.Second}
+	return newJwks(func(ctx context.Context) ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("jwks url returned status %d", resp.StatusCode)
+		}
+
+		return ioutil.ReadAll(resp.Body)
+	}, refreshInterval)
+}
+
+// NewJwksFromFile returns the keys of the file.
+func NewJwksFromFile(path string, refreshInterval time.Duration) *Jwks {
+	return newJwks(func(context.Context) ([]byte, error) {
+		return ioutil.ReadFile(path)
+	}, refreshInterval)
+}
+
+func newJwks(read func(ctx context.Context) ([]byte, error), refreshInterval time.Duration) *Jwks {
+	return &Jwks{
+		read:            read,
+		refreshInterval: refreshInterval,
+		now:             time.Now,
+		keys:            map[string]crypto.PublicKey{},
+	}
+}
+
+// Key returns the key with the id. The callers with an unknown key wait
+// for the refresh, up to the end of ctx.
+func (j *Jwks) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	j.mu.Lock()
+	key, ok := j.keys[kid]
+	now := j.now()
+	age := now.Sub(j.fetchedAt)
+	switch {
+	case ok && age < j.refreshInterval:
+		j.mu.Unlock()
+		return key, nil
+	case !ok && age < minRefreshInterval:
+		j.mu.Unlock()
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	case now.Before(j.retryAt):
+		err := j.lastErr
+		j.mu.Unlock()
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+	done := j.startRefresh()
+	j.mu.Unlock()
+
+	if ok {
+		// the known keys are served while the refresh runs, and kept when it
+		// fails, so the provider being slow or down does not reject them
+		return key, nil
+	}
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	key, ok = j.keys[kid]
+	if !ok {
+		if j.failures > 0 {
+			return nil, j.lastErr
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	return key, nil
+}
+
+// startRefresh starts a refresh, unless one is running, and returns the
+// channel closed when it ends. It must be called with the lock held.
+func (j *Jwks) startRefresh() chan struct{} {
+	if j.refreshing != nil {
+		return j.refreshing
+	}
+
+	done := make(chan struct{})
+	j.refreshing = done
+	go func() {
+		// the refresh is shared, so it does not end with the request starting it
+		keys, err := j.fetch(context.Background())
+
+		j.mu.Lock()
+		defer j.mu.Unlock()
+
+		if err != nil {
+			j.failures++
+			j.retryAt = j.now().Add(retryInterval(j.failures))
+			j.lastErr = err
+			logger.Warnw("error refreshing the jwks", "error", err.Error(), "failures", j.failures)
+		} else {
+			j.keys = keys
+			j.fetchedAt = j.now()
+			j.failures = 0
+			j.retryAt = time.Time{}
+			j.lastErr = nil
+		}
+		j.refreshing = nil
+		close(done)
+	}()
+
+	return done
+}
+
+// retryInterval returns the wait before the refresh following the failed
+// ones.
+func retryInterval(failures int) time.Duration {
+	interval := minRefreshInterval
+	for i := 1; i < failures && interval < maxRetryInterval; i++ {
+		interval *= 2
+	}
+	if interval > maxRetryInterval {
+		return maxRetryInterval
+	}
+
+	return interval
+}
+
+// fetch reads and parses the keys.
+func (j *Jwks) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	data, err := j.read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading the jwks: %s", err.Error())
+	}
+
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	err = json.Unmarshal(data, &set)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the jwks: %s", err.Error())
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			logger.Warnw("ignoring jwks key", "kid", k.Kid, "error", err.Error())
+			continue
+		}
+		keys[k.Kid] = key
+	}
+
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key parameter: %s", err.Error())
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
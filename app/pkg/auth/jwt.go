@@ -0,0 +1,150 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/renato0307/canivete-api/pkg/config"
+)
+
+// MethodJwt is the method of the identities authenticated by JWT.
+const MethodJwt = "jwt"
+
+// signingMethods are the algorithms accepted, asymmetric only as the keys
+// come from a JWKS.
+var signingMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+}
+
+// JwtAuthenticator authenticates the requests with a bearer JWT signed by
+// one of the keys of a JWKS.
+type JwtAuthenticator struct {
+	cfg  config.JwtConfig
+	jwks *Jwks
+	now  func() time.Time
+}
+
+// NewJwtAuthenticator returns an authenticator checking the tokens with
+// the keys of the JWKS and the claims expected by cfg.
+func NewJwtAuthenticator(cfg config.JwtConfig, jwks *Jwks) *JwtAuthenticator {
+	return &JwtAuthenticator{cfg: cfg, jwks: jwks, now: time.Now}
+}
+
+// Authenticate implements Authenticator.
+func (a *JwtAuthenticator) Authenticate(r *http.Request) (Identity, error) {
+	header := r.Header.Get("Authorization")
+	if len(header) < 7 || !strings.EqualFold(header[:7], "Bearer ") {
+		return Identity{}, ErrNoCredentials
+	}
+
+	parser := jwt.NewParser(jwt.WithValidMethods(signingMethods), jwt.WithoutClaimsValidation())
+	claims := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(strings.TrimSpace(header[7:]), claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return a.jwks.Key(r.Context(), kid)
+	})
+	if err != nil {
+		return Identity{}, fmt.Errorf("token is invalid: %s", err.Error())
+	}
+
+	err = a.validateClaims(claims)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	return Identity{Name: subject(claims), Method: MethodJwt, Scopes: a.scopes(claims)}, nil
+}
+
+// subject names the caller of the token: its subject or, for the client
+// credentials tokens of some providers, its client id.
+func subject(claims jwt.MapClaims) string {
+	for _, claim := range []string{"sub", "client_id", "azp"} {
+		if name, _ := claims[claim].(string); name != "" {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// Challenge implements Authenticator.
+func (a *JwtAuthenticator) Challenge() string {
+	return `Bearer realm="canivete-api"`
+}
+
+// validateClaims checks the times, with the clock skew, the issuer and the
+// audience of the token.
+func (a *JwtAuthenticator) validateClaims(claims jwt.MapClaims) error {
+	now := a.now()
+	skew := a.cfg.ClockSkew
+
+	if _, ok := claims["exp"]; !ok {
+		return errors.New("token has no expiration")
+	}
+	if !claims.VerifyExpiresAt(now.Add(-skew).Unix(), true) {
+		return errors.New("token is expired")
+	}
+	if !claims.VerifyNotBefore(now.Add(skew).Unix(), false) {
+		return errors.New("token is not valid yet")
+	}
+	if !claims.VerifyIssuedAt(now.Add(skew).Unix(), false) {
+		return errors.New("token is issued in the future")
+	}
+
+	if a.cfg.Issuer != "" && !claims.VerifyIssuer(a.cfg.Issuer, true) {
+		return errors.New("token issuer is not accepted")
+	}
+	if a.cfg.Audience != "" && !claims.VerifyAudience(a.cfg.Audience, true) {
+		return errors.New("token audience is not accepted")
+	}
+
+	return nil
+}
+
+// scopes maps the values of the scopes claim to service groups.
+func (a *JwtAuthenticator) scopes(claims jwt.MapClaims) []string {
+	values := []string{}
+	switch claim := claims[a.cfg.ScopesClaim].(type) {
+	case string:
+		values = strings.Fields(claim)
+	case []interface{}:
+		for _, value := range claim {
+			if s, ok := value.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	if len(a.cfg.ScopeMapping) == 0 {
+		return values
+	}
+
+	scopes := []string{}
+	for _, value := range values {
+		scopes = append(scopes, a.cfg.ScopeMapping[value]...)
+	}
+
+	return scopes
+}
@@ -0,0 +1,326 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package batch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/openapi"
+	"github.com/renato0307/canivete-api/pkg/requestid"
+	"go.uber.org/zap"
+)
+
+// requestValidator checks the fields of the requests of the batches.
+var requestValidator = validator.New()
+
+var logger *zap.SugaredLogger = logging.GetLogger()
+
+// Request is a request of a batch.
+type Request struct {
+	Method string `json:"method" validate:"required,oneof=GET POST PUT PATCH DELETE"`
+	Path   string `json:"path" validate:"required,startswith=/"`
+	// Headers are added to the headers of the batch request; only the
+	// allowedHeaders can be set.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is sent as is when it is a string, as JSON otherwise.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// Result is the response to a request of a batch.
+type Result struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is kept as is when the response is JSON, as a string otherwise.
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// forbiddenPrefixes are the paths the requests of a batch cannot call: the
+// admin endpoints, pprof included, which are not tools.
+var forbiddenPrefixes = []string{"/admin", "/debug/pprof"}
+
+// headersNotCopied are the headers of the batch request not given to its
+// requests, as they describe the batch body.
+var headersNotCopied = []string{"Content-Length", "Content-Type", "Content-Encoding"}
+
+// allowedHeaders are the headers a request of the batch can set. The
+// others, like the request id and the forwarding headers, come from the
+// batch request only.
+var allowedHeaders = []string{"Accept", "Content-Type"}
+
+// SetRouterGroup registers the batch endpoint, which runs its requests
+// through handler, the engine of the api, so they go through the same
+// middlewares as the direct calls.
+func SetRouterGroup(handler http.Handler, cfg config.BatchConfig, base *gin.RouterGroup) *gin.RouterGroup {
+	base.POST("/batch", postBatch(handler, cfg))
+
+	openapi.Describe(base, http.MethodPost, "/batch", openapi.Operation{
+		Summary: "Calls several tools in a single request",
+		Description: fmt.Sprintf("Runs up to %d requests, %d at a time, and answers with their results in order. "+
+			"The requests get the headers of the batch request, like the credentials.", cfg.MaxSize, cfg.Parallelism),
+		Request:  &openapi.Body{Type: []Request{}},
+		Response: openapi.Body{Type: []Result{}},
+		Errors:   []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge},
+	})
+
+	return base
+}
+
+// postBatch handles the batch request.
+// It returns:
+//
+// 200 (OK) with the results of the requests, whatever their status;
+// 400 (BadRequest) if the body or one of the requests is invalid;
+// 413 (RequestEntityTooLarge) if the batch has too many requests.
+func postBatch(handler http.Handler, cfg config.BatchConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			apierrors.Abort(c, apierrors.Internal(apierrors.CodeInternal, "unexpected error reading the body"))
+			return
+		}
+
+		requests := []Request{}
+		err = json.Unmarshal(body, &requests)
+		if err != nil || len(requests) == 0 {
+			apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidBody, "request body must be a non empty array of requests"))
+			return
+		}
+
+		if len(requests) > cfg.MaxSize {
+			detail := fmt.Sprintf("a batch has at most %d requests", cfg.MaxSize)
+			apierrors.Abort(c, apierrors.New(http.StatusRequestEntityTooLarge, apierrors.CodeBatchTooLarge, detail))
+			return
+		}
+
+		apiError, ok := validate(requests, c.FullPath())
+		if !ok {
+			logging.FromContext(c).Debugw("bad request received for a batch", "errors", apiError.Errors)
+			apierrors.Abort(c, apiError)
+			return
+		}
+
+		results := make([]Result, len(requests))
+		semaphore := make(chan struct{}, cfg.Parallelism)
+		var wg sync.WaitGroup
+		for i, request := range requests {
+			wg.Add(1)
+			semaphore <- struct{}{}
+			go func(i int, request Request) {
+				defer wg.Done()
+				defer func() { <-semaphore }()
+				results[i] = serve(handler, c, i, request)
+			}(i, request)
+		}
+		wg.Wait()
+
+		logging.FromContext(c).Debugw("batch completed", "size", len(requests))
+		c.JSON(http.StatusOK, results)
+	}
+}
+
+// validate checks the requests, which cannot call the batch endpoint.
+func validate(requests []Request, batchPath string) (apierrors.ApiError, bool) {
+	apiError := apierrors.BadRequest(apierrors.CodeValidationFailed, "batch has invalid requests")
+
+	for i, request := range requests {
+		err := requestValidator.Struct(request)
+		if err != nil {
+			for _, fieldError := range apierrors.Validation(err).Errors {
+				fieldError.Field = fmt.Sprintf("[%d].%s", i, fieldError.Field)
+				apiError.Errors = append(apiError.Errors, fieldError)
+			}
+		}
+
+		for name := range request.Headers {
+			if !allowedHeader(name) {
+				apiError.Errors = append(apiError.Errors, apierrors.FieldError{
+					Field:   fmt.Sprintf("[%d].Headers.%s", i, name),
+					Rule:    "header",
+					Message: "header cannot be set, only " + strings.Join(allowedHeaders, " and ") + " can",
+				})
+			}
+		}
+
+		if request.Path == "" {
+			continue
+		}
+		rule, message, ok := validatePath(request.Path, batchPath)
+		if !ok {
+			apiError.Errors = append(apiError.Errors, apierrors.FieldError{
+				Field:   fmt.Sprintf("[%d].Path", i),
+				Rule:    rule,
+				Message: message,
+			})
+		}
+	}
+
+	return apiError, len(apiError.Errors) == 0
+}
+
+// allowedHeader tells if a request of the batch can set the header.
+func allowedHeader(name string) bool {
+	for _, allowed := range allowedHeaders {
+		if http.CanonicalHeaderKey(name) == allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validatePath checks the path of a request the way the router reads it:
+// decoded, without the query and the fragment, and cleaned. It returns the
+// rule and the message of the error when the path is rejected.
+func validatePath(rawPath string, batchPath string) (string, string, bool) {
+	u, err := url.Parse(rawPath)
+	if err != nil || u.Scheme != "" || u.Host != "" {
+		return "path", "path must be a path of the api", false
+	}
+
+	p := path.Clean(u.Path)
+	if p == batchPath {
+		return "nested", "batches cannot be nested", false
+	}
+	for _, prefix := range forbiddenPrefixes {
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return "forbidden", "batches cannot call " + prefix, false
+		}
+	}
+
+	return "", "", true
+}
+
+// serve runs a request of the batch, with the headers and the context of
+// the batch request.
+func serve(handler http.Handler, c *gin.Context, i int, request Request) Result {
+	body, contentType := requestBody(request.Body)
+	req, err := http.NewRequestWithContext(c.Request.Context(), request.Method, request.Path, body)
+	if err != nil {
+		return problem(apierrors.BadRequest(apierrors.CodeInvalidBody, err.Error()))
+	}
+
+	req.Header = c.Request.Header.Clone()
+	for _, name := range headersNotCopied {
+		req.Header.Del(name)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if header := requestid.HeaderFromContext(c.Request.Context()); header != "" {
+		// the requests are correlated with the batch by their id
+		id := requestid.FromContext(c.Request.Context())
+		req.Header.Set(header, fmt.Sprintf("%s.%d", id, i))
+	}
+	for name, value := range request.Headers {
+		req.Header.Set(name, value)
+	}
+	req.RemoteAddr = c.Request.RemoteAddr
+
+	w := &recorder{header: http.Header{}}
+	handler.ServeHTTP(w, req)
+
+	return w.result()
+}
+
+// requestBody returns the body to send for the body of a request of the
+// batch, and its content type.
+func requestBody(raw json.RawMessage) (*bytes.Reader, string) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return bytes.NewReader(nil), ""
+	}
+
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return bytes.NewReader([]byte(text)), "text/plain"
+	}
+
+	return bytes.NewReader(raw), "application/json"
+}
+
+func problem(apiError apierrors.ApiError) Result {
+	body, _ := json.Marshal(apiError)
+	return Result{
+		Status:  apiError.Status,
+		Headers: map[string]string{"Content-Type": apierrors.ContentType},
+		Body:    body,
+	}
+}
+
+// recorder keeps the response to a request of the batch.
+type recorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(b)
+}
+
+func (r *recorder) result() Result {
+	result := Result{Status: r.status, Headers: map[string]string{}}
+	if result.Status == 0 {
+		result.Status = http.StatusOK
+	}
+
+	for name := range r.header {
+		result.Headers[name] = r.header.Get(name)
+	}
+
+	if r.body.Len() == 0 {
+		return result
+	}
+
+	contentType := r.header.Get("Content-Type")
+	if isJson(contentType) && json.Valid(r.body.Bytes()) {
+		result.Body = r.body.Bytes()
+	} else {
+		result.Body, _ = json.Marshal(r.body.String())
+	}
+
+	return result
+}
+
+// isJson tells if a content type, like application/problem+json, is JSON.
+func isJson(contentType string) bool {
+	mediaType := strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
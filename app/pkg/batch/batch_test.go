@@ -0,0 +1,194 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package batch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGin(cfg config.BatchConfig) *gin.Engine {
+	r := gin.New()
+	r.Use(requestid.Middleware(requestid.DefaultHeader))
+	v1 := r.Group("/v1")
+	v1.GET("/uuid", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"uuid": "d967aaad", "caller": c.GetHeader("X-API-Key")})
+	})
+	v1.POST("/echo", func(c *gin.Context) {
+		body, _ := ioutil.ReadAll(c.Request.Body)
+		c.Data(http.StatusOK, "text/plain", body)
+	})
+	v1.GET("/request-id", func(c *gin.Context) {
+		c.String(http.StatusOK, requestid.Get(c))
+	})
+	SetRouterGroup(r, cfg, v1)
+
+	return r
+}
+
+func postBatchRequest(r *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", "secret")
+	req.Header.Set(requestid.DefaultHeader, "batch-1")
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestPostBatch(t *testing.T) {
+	// arrange
+	r := setupGin(config.BatchConfig{MaxSize: 10, Parallelism: 2})
+	body := `[
+		{"method": "GET", "path": "/v1/uuid"},
+		{"method": "POST", "path": "/v1/echo", "body": "1638964800"},
+		{"method": "POST", "path": "/v1/echo", "body": {"time": 2}},
+		{"method": "GET", "path": "/v1/request-id"},
+		{"method": "GET", "path": "/v1/unknown"}
+	]`
+
+	// act
+	w := postBatchRequest(r, body)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	results := []Result{}
+	err := json.Unmarshal(w.Body.Bytes(), &results)
+	assert.Nil(t, err)
+	assert.Len(t, results, 5)
+	assert.Equal(t, http.StatusOK, results[0].Status)
+	assert.JSONEq(t, `{"uuid":"d967aaad","caller":"secret"}`, string(results[0].Body))
+	assert.Equal(t, `"1638964800"`, string(results[1].Body))
+	assert.Equal(t, `"{\"time\": 2}"`, string(results[2].Body))
+	assert.Equal(t, `"batch-1.3"`, string(results[3].Body))
+	assert.Equal(t, http.StatusNotFound, results[4].Status)
+}
+
+func TestPostBatchRunsInParallel(t *testing.T) {
+	// arrange
+	var running, maxRunning int32
+	r := setupGin(config.BatchConfig{MaxSize: 10, Parallelism: 3})
+	r.GET("/v1/slow", func(c *gin.Context) {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		c.Status(http.StatusNoContent)
+	})
+	body := "[" + strings.TrimSuffix(strings.Repeat(`{"method": "GET", "path": "/v1/slow"},`, 8), ",") + "]"
+
+	// act
+	w := postBatchRequest(r, body)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&maxRunning))
+}
+
+func TestPostBatchTooLarge(t *testing.T) {
+	// arrange
+	r := setupGin(config.BatchConfig{MaxSize: 1, Parallelism: 1})
+	body := `[{"method": "GET", "path": "/v1/uuid"}, {"method": "GET", "path": "/v1/uuid"}]`
+
+	// act
+	w := postBatchRequest(r, body)
+
+	// assert
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeBatchTooLarge, apiError.Code)
+}
+
+func TestPostBatchWithInvalidRequests(t *testing.T) {
+	tests := []struct {
+		body   string
+		code   string
+		fields []string
+	}{
+		{`{"method": "GET"}`, apierrors.CodeInvalidBody, nil},
+		{`[]`, apierrors.CodeInvalidBody, nil},
+		{
+			`[{"method": "TRACE", "path": "/v1/uuid"}, {"method": "GET", "path": "v1/uuid"}]`,
+			apierrors.CodeValidationFailed,
+			[]string{"[0].Method", "[1].Path"},
+		},
+		{
+			`[{"method": "POST", "path": "/v1/batch?x=1", "body": []}]`,
+			apierrors.CodeValidationFailed,
+			[]string{"[0].Path"},
+		},
+		{
+			`[{"method": "POST", "path": "/v1/%62atch"}, {"method": "POST", "path": "/v1/batch#x"},
+			  {"method": "POST", "path": "/v1/./batch"}, {"method": "GET", "path": "/v1/uuid"}]`,
+			apierrors.CodeValidationFailed,
+			[]string{"[0].Path", "[1].Path", "[2].Path"},
+		},
+		{
+			`[{"method": "GET", "path": "/admin/config"}, {"method": "GET", "path": "/admin/debug/pprof/heap"},
+			  {"method": "GET", "path": "/debug/pprof/"}, {"method": "GET", "path": "//example.com/v1/uuid"}]`,
+			apierrors.CodeValidationFailed,
+			[]string{"[0].Path", "[1].Path", "[2].Path", "[3].Path"},
+		},
+		{
+			`[{"method": "GET", "path": "/v1/request-id", "headers": {"X-Request-Id": "other"}},
+			  {"method": "GET", "path": "/v1/uuid", "headers": {"accept": "application/json"}},
+			  {"method": "GET", "path": "/v1/uuid", "headers": {"X-Forwarded-For": "10.0.0.1"}}]`,
+			apierrors.CodeValidationFailed,
+			[]string{"[0].Headers.X-Request-Id", "[2].Headers.X-Forwarded-For"},
+		},
+	}
+
+	for _, test := range tests {
+		// arrange
+		r := setupGin(config.BatchConfig{MaxSize: 10, Parallelism: 1})
+
+		// act
+		w := postBatchRequest(r, test.body)
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, w.Code, test.body)
+		apiError, err := apierrors.FromResponse(w.Result())
+		assert.Nil(t, err)
+		assert.Equal(t, test.code, apiError.Code, test.body)
+		fields := []string{}
+		for _, fieldError := range apiError.Errors {
+			fields = append(fields, fieldError.Field)
+		}
+		if test.fields != nil {
+			assert.Equal(t, test.fields, fields, test.body)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGin(overrides map[string]string, lru *LRU, calls *int) *gin.Engine {
+	r := gin.New()
+	r.Use(Middleware(testPolicies(), overrides, lru))
+	r.POST("/echo", func(c *gin.Context) {
+		*calls++
+		body, _ := ioutil.ReadAll(c.Request.Body)
+		if len(body) == 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "empty"})
+			return
+		}
+		c.Data(http.StatusOK, "text/plain", body)
+	})
+	r.GET("/random", func(c *gin.Context) {
+		*calls++
+		c.String(http.StatusOK, "4")
+	})
+
+	return r
+}
+
+func testPolicies() *Policies {
+	policies := NewPolicies()
+	r := gin.New()
+	policies.Set(&r.RouterGroup, "/echo", Policy{CacheControl: "public, max-age=60", Deterministic: true})
+	policies.Set(&r.RouterGroup, "/random", Policy{CacheControl: NoStore})
+
+	return policies
+}
+
+func serve(r *gin.Engine, method string, path string, body string, ifNoneMatch string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestLRU(t *testing.T) {
+	// arrange
+	lru := NewLRU(2)
+	lru.Add("a", Entry{ETag: `"a"`})
+	lru.Add("b", Entry{ETag: `"b"`})
+
+	// act
+	_, hitA := lru.Get("a")
+	lru.Add("c", Entry{ETag: `"c"`})
+	_, hitB := lru.Get("b")
+	entryC, hitC := lru.Get("c")
+
+	// assert
+	assert.True(t, hitA)
+	assert.False(t, hitB)
+	assert.True(t, hitC)
+	assert.Equal(t, `"c"`, entryC.ETag)
+}
+
+func TestMiddlewareSetsETags(t *testing.T) {
+	// arrange
+	calls := 0
+	r := setupGin(nil, nil, &calls)
+
+	// act
+	first := serve(r, http.MethodPost, "/echo", "1638964800", "")
+	etag := first.Header().Get("ETag")
+	second := serve(r, http.MethodPost, "/echo", "1638964800", `"other", `+etag)
+	other := serve(r, http.MethodPost, "/echo", "1638964801", etag)
+
+	// assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, "1638964800", first.Body.String())
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, "public, max-age=60", first.Header().Get("Cache-Control"))
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Empty(t, second.Body.String())
+	assert.Equal(t, etag, second.Header().Get("ETag"))
+	assert.Equal(t, http.StatusOK, other.Code)
+	assert.NotEqual(t, etag, other.Header().Get("ETag"))
+	assert.Equal(t, 3, calls)
+}
+
+func TestMiddlewareDoesNotCacheErrors(t *testing.T) {
+	// arrange
+	calls := 0
+	r := setupGin(nil, NewLRU(10), &calls)
+
+	// act
+	first := serve(r, http.MethodPost, "/echo", "", "")
+	second := serve(r, http.MethodPost, "/echo", "", "")
+
+	// assert
+	assert.Equal(t, http.StatusBadRequest, first.Code)
+	assert.JSONEq(t, `{"error":"empty"}`, first.Body.String())
+	assert.Empty(t, first.Header().Get("ETag"))
+	assert.Equal(t, http.StatusBadRequest, second.Code)
+	assert.Equal(t, 2, calls)
+}
+
+func TestMiddlewareServesFromTheLRU(t *testing.T) {
+	// arrange
+	calls := 0
+	lru := NewLRU(10)
+	r := setupGin(nil, lru, &calls)
+	hits := testutil.ToFloat64(metrics.CacheLookups.WithLabelValues("/echo", resultHit))
+
+	// act
+	first := serve(r, http.MethodPost, "/echo", "1638964800", "")
+	second := serve(r, http.MethodPost, "/echo", "1638964800", "")
+	third := serve(r, http.MethodPost, "/echo", "1638964800", first.Header().Get("ETag"))
+
+	// assert
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, first.Body.String(), second.Body.String())
+	assert.Equal(t, first.Header().Get("ETag"), second.Header().Get("ETag"))
+	assert.Equal(t, "text/plain", second.Header().Get("Content-Type"))
+	assert.Equal(t, http.StatusNotModified, third.Code)
+	assert.Equal(t, hits+2, testutil.ToFloat64(metrics.CacheLookups.WithLabelValues("/echo", resultHit)))
+}
+
+func TestMiddlewareCacheControl(t *testing.T) {
+	// arrange
+	calls := 0
+	r := setupGin(map[string]string{"/echo": "private, max-age=10"}, NewLRU(10), &calls)
+
+	// act
+	random := serve(r, http.MethodGet, "/random", "", "")
+	echo := serve(r, http.MethodPost, "/echo", "1", "")
+
+	// assert
+	assert.Equal(t, NoStore, random.Header().Get("Cache-Control"))
+	assert.Empty(t, random.Header().Get("ETag"))
+	assert.Equal(t, "private, max-age=10", echo.Header().Get("Cache-Control"))
+}
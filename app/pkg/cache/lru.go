@@ -0,0 +1,85 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Entry is a response kept in the LRU.
+type Entry struct {
+	ContentType string
+	ETag        string
+	Body        []byte
+}
+
+// LRU keeps the most recently used responses, up to its size.
+type LRU struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type item struct {
+	key   string
+	entry Entry
+}
+
+// NewLRU returns an empty LRU keeping up to size entries.
+func NewLRU(size int) *LRU {
+	return &LRU{
+		size:    size,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// Get returns the entry of the key, if it is kept.
+func (l *LRU) Get(key string) (Entry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.entries[key]
+	if !ok {
+		return Entry{}, false
+	}
+
+	l.order.MoveToFront(element)
+	return element.Value.(*item).entry, true
+}
+
+// Add keeps the entry of the key, evicting the least recently used entry
+// when the LRU is full.
+func (l *LRU) Add(key string, entry Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.entries[key]; ok {
+		element.Value.(*item).entry = entry
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.entries[key] = l.order.PushFront(&item{key: key, entry: entry})
+	if l.order.Len() > l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*item).key)
+	}
+}
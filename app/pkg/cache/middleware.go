@@ -0,0 +1,257 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+)
+
+// Results of the LRU lookups, used as the result label.
+const (
+	resultHit  = "hit"
+	resultMiss = "miss"
+)
+
+// NoStore is the Cache-Control of the responses that must not be cached.
+const NoStore = "no-store"
+
+// Policy is how the responses of a route can be cached.
+type Policy struct {
+	// CacheControl is the Cache-Control header of the responses.
+	CacheControl string
+	// Deterministic tells the responses only depend on the request, so
+	// they get an ETag and can be kept in the LRU.
+	Deterministic bool
+}
+
+// Policies keeps the caching policy of each route.
+type Policies struct {
+	mu       sync.Mutex
+	policies map[string]Policy
+}
+
+// NewPolicies returns an empty policy registry, where no route is cached.
+func NewPolicies() *Policies {
+	return &Policies{policies: map[string]Policy{}}
+}
+
+// Set sets the policy of the route registered on the group with the
+// relative path.
+func (p *Policies) Set(group *gin.RouterGroup, relativePath string, policy Policy) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.policies[strings.TrimSuffix(group.BasePath(), "/")+relativePath] = policy
+}
+
+// Get returns the policy of the route, if it was set.
+func (p *Policies) Get(route string) (Policy, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	policy, ok := p.policies[route]
+	return policy, ok
+}
+
+// Middleware sets the Cache-Control header of the responses with the
+// policy of their route. The successful responses of deterministic routes
+// get a strong ETag, and are answered with 304 (NotModified) when it
+// matches the If-None-Match header.
+// When lru is not nil, the responses of deterministic routes are kept and
+// served again without calling the tools.
+// overrides replaces the Cache-Control of the registry for some routes.
+func Middleware(policies *Policies, overrides map[string]string, lru *LRU) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		policy, ok := policies.Get(route)
+		if cacheControl, overridden := overrides[route]; overridden {
+			policy.CacheControl = cacheControl
+			ok = true
+		}
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if !policy.Deterministic {
+			c.Header("Cache-Control", policy.CacheControl)
+			c.Next()
+			return
+		}
+
+		var key string
+		if lru != nil {
+			key = requestKey(c)
+			entry, hit := lru.Get(key)
+			if hit {
+				metrics.CacheLookups.WithLabelValues(route, resultHit).Inc()
+				write(c, policy, entry)
+				c.Abort()
+				return
+			}
+			metrics.CacheLookups.WithLabelValues(route, resultMiss).Inc()
+		}
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if w.status != http.StatusOK {
+			w.flush()
+			return
+		}
+
+		contentType := w.Header().Get("Content-Type")
+		entry := Entry{
+			ContentType: contentType,
+			ETag:        etag(contentType, w.body.Bytes()),
+			Body:        w.body.Bytes(),
+		}
+		if lru != nil {
+			lru.Add(key, entry)
+		}
+		write(c, policy, entry)
+	}
+}
+
+// write answers with the entry, or with 304 (NotModified) when the client
+// has it already.
+func write(c *gin.Context, policy Policy, entry Entry) {
+	c.Header("ETag", entry.ETag)
+	// added, the response can vary on the origin too
+	c.Writer.Header().Add("Vary", "Accept")
+	if policy.CacheControl != "" {
+		c.Header("Cache-Control", policy.CacheControl)
+	}
+
+	if matches(c.GetHeader("If-None-Match"), entry.ETag) {
+		c.Status(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+
+	c.Data(http.StatusOK, entry.ContentType, entry.Body)
+}
+
+// requestKey identifies a request by everything the response depends on:
+// the method, the url, the accepted formats and the body.
+// The body was read by limits.Middleware already, so it is in memory.
+func requestKey(c *gin.Context) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + "\n" + c.Request.URL.RequestURI() + "\n" + c.GetHeader("Accept") + "\n"))
+	if c.Request.Body != nil && c.Request.Body != http.NoBody {
+		body, _ := ioutil.ReadAll(c.Request.Body)
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		hash.Write(body)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// etag returns a strong ETag, changing with any byte of the response.
+func etag(contentType string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(contentType + "\n"))
+	hash.Write(body)
+
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// matches tells if an If-None-Match header matches the ETag, with the weak
+// comparison required for this header.
+func matches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bufferedWriter keeps the response of the handlers, so its ETag is known
+// before it is written.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.WriteHeaderNow()
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *bufferedWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.status != 0
+}
+
+// flush writes the response kept as is.
+func (w *bufferedWriter) flush() {
+	w.ResponseWriter.WriteHeader(w.Status())
+	w.ResponseWriter.WriteHeaderNow()
+	w.ResponseWriter.Write(w.body.Bytes())
+}
+
+var defaultPolicies = NewPolicies()
+
+// SetPolicy sets the policy of a route in the default registry.
+func SetPolicy(group *gin.RouterGroup, relativePath string, policy Policy) {
+	defaultPolicies.Set(group, relativePath, policy)
+}
+
+// DefaultPolicies returns the registry where the service groups set the
+// policy of their routes.
+func DefaultPolicies() *Policies {
+	return defaultPolicies
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package certs
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/logging"
+)
+
+// clientIdentityKey is the key of the client identity in the gin context.
+const clientIdentityKey = "canivete-api/client-identity"
+
+// ClientIdentity is the identity of a verified client certificate.
+type ClientIdentity struct {
+	CommonName string
+	DnsNames   []string
+	// Uris are the URI SANs, like SPIFFE ids.
+	Uris         []string
+	SerialNumber string
+	// Fingerprint is the hex encoded SHA-256 of the certificate.
+	Fingerprint string
+}
+
+// Name identifies the client in the logs: the common name, or the first
+// URI or DNS SAN when there is none.
+func (i ClientIdentity) Name() string {
+	switch {
+	case i.CommonName != "":
+		return i.CommonName
+	case len(i.Uris) > 0:
+		return i.Uris[0]
+	case len(i.DnsNames) > 0:
+		return i.DnsNames[0]
+	}
+
+	return i.Fingerprint
+}
+
+// IdentityFromState returns the identity of the client certificate of the
+// connection, if there is one.
+func IdentityFromState(state *tls.ConnectionState) (ClientIdentity, bool) {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return ClientIdentity{}, false
+	}
+
+	certificate := state.PeerCertificates[0]
+	fingerprint := sha256.Sum256(certificate.Raw)
+	identity := ClientIdentity{
+		CommonName:   certificate.Subject.CommonName,
+		DnsNames:     certificate.DNSNames,
+		Uris:         []string{},
+		SerialNumber: certificate.SerialNumber.String(),
+		Fingerprint:  hex.EncodeToString(fingerprint[:]),
+	}
+	for _, uri := range certificate.URIs {
+		identity.Uris = append(identity.Uris, uri.String())
+	}
+
+	return identity, true
+}
+
+// Middleware sets the identity of the client certificate of the request,
+// if there is one, and adds it to the request logger.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := IdentityFromState(c.Request.TLS)
+		if ok {
+			c.Set(clientIdentityKey, identity)
+			logging.With(c, "clientCert", identity.Name(), "clientCertFingerprint", identity.Fingerprint)
+		}
+
+		c.Next()
+	}
+}
+
+// GetClientIdentity returns the identity set by Middleware, if any.
+func GetClientIdentity(c *gin.Context) (ClientIdentity, bool) {
+	identity, ok := c.Value(clientIdentityKey).(ClientIdentity)
+	return identity, ok
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGin(state *tls.ConnectionState) (*gin.Engine, *http.Request) {
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/whoami", func(c *gin.Context) {
+		identity, ok := GetClientIdentity(c)
+		if !ok {
+			c.String(http.StatusOK, "anonymous")
+			return
+		}
+		c.String(http.StatusOK, identity.Name())
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/whoami", nil)
+	req.TLS = state
+
+	return r, req
+}
+
+func TestIdentityFromState(t *testing.T) {
+	// arrange
+	spiffe, _ := url.Parse("spiffe://canivete/ci")
+	certificate := &x509.Certificate{
+		Raw:          []byte("certificate"),
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "ci-pipeline"},
+		DNSNames:     []string{"ci.canivete.local"},
+		URIs:         []*url.URL{spiffe},
+	}
+
+	// act
+	identity, ok := IdentityFromState(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}})
+
+	// assert
+	assert.True(t, ok)
+	assert.Equal(t, "ci-pipeline", identity.CommonName)
+	assert.Equal(t, []string{"ci.canivete.local"}, identity.DnsNames)
+	assert.Equal(t, []string{"spiffe://canivete/ci"}, identity.Uris)
+	assert.Equal(t, "42", identity.SerialNumber)
+	assert.Equal(t, "03d66dd08835c1ca3f128cceacd1f31ac94163096b20f445ae84285bc0832d72", identity.Fingerprint)
+}
+
+func TestClientIdentityName(t *testing.T) {
+	tests := []struct {
+		identity ClientIdentity
+		expected string
+	}{
+		{ClientIdentity{CommonName: "ci", Uris: []string{"spiffe://canivete/ci"}}, "ci"},
+		{ClientIdentity{Uris: []string{"spiffe://canivete/ci"}, DnsNames: []string{"ci.local"}}, "spiffe://canivete/ci"},
+		{ClientIdentity{DnsNames: []string{"ci.local"}}, "ci.local"},
+		{ClientIdentity{Fingerprint: "abc"}, "abc"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.identity.Name())
+	}
+}
+
+func TestMiddlewareSetsTheClientIdentity(t *testing.T) {
+	// arrange
+	certificate := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "ci-pipeline"}}
+	r, req := setupGin(&tls.ConnectionState{PeerCertificates: []*x509.Certificate{certificate}})
+	w := httptest.NewRecorder()
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, "ci-pipeline", w.Body.String())
+}
+
+func TestMiddlewareWithoutClientCertificate(t *testing.T) {
+	tests := []*tls.ConnectionState{nil, {}}
+
+	for _, state := range tests {
+		// arrange
+		r, req := setupGin(state)
+		w := httptest.NewRecorder()
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Equal(t, "anonymous", w.Body.String())
+	}
+}
@@ -0,0 +1,204 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"go.uber.org/zap"
+)
+
+var logger *zap.SugaredLogger = logging.GetLogger()
+
+// Reloader holds the certificate of the servers and the CAs verifying the
+// client certificates. Watch reads the files again when they change, so
+// the rotated certificates are served without restarting.
+type Reloader struct {
+	cfg config.TlsConfig
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	clientCas   *x509.CertPool
+	files       [][]byte
+}
+
+// NewReloader loads the files of the configuration.
+func NewReloader(cfg config.TlsConfig) (*Reloader, error) {
+	r := &Reloader{cfg: cfg}
+	_, err := r.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload reads the files and tells if they changed. The previous
+// certificate is kept when they are not valid.
+func (r *Reloader) Reload() (bool, error) {
+	files := [][]byte{}
+	for _, path := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCaFile} {
+		if path == "" {
+			continue
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return false, fmt.Errorf("error reading %s: %s", path, err.Error())
+		}
+		files = append(files, data)
+	}
+
+	r.mu.RLock()
+	changed := !sameFiles(r.files, files)
+	r.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	certificate, err := tls.X509KeyPair(files[0], files[1])
+	if err != nil {
+		return false, fmt.Errorf("error loading the certificate: %s", err.Error())
+	}
+	certificate.Leaf, err = x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return false, fmt.Errorf("error parsing the certificate: %s", err.Error())
+	}
+
+	var clientCas *x509.CertPool
+	if r.cfg.ClientCaFile != "" {
+		clientCas = x509.NewCertPool()
+		if !clientCas.AppendCertsFromPEM(files[2]) {
+			return false, fmt.Errorf("no certificates found in %s", r.cfg.ClientCaFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.certificate = &certificate
+	r.clientCas = clientCas
+	r.files = files
+	r.mu.Unlock()
+
+	return true, nil
+}
+
+func sameFiles(previous, current [][]byte) bool {
+	if len(previous) != len(current) {
+		return false
+	}
+	for i := range previous {
+		if !bytes.Equal(previous[i], current[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Watch reloads the files on each reload interval until the context is
+// done.
+func (r *Reloader) Watch(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := r.Reload()
+			if err != nil {
+				// a half written rotation must not stop serving the current certificate
+				logger.Warnw("error reloading the certificates, keeping the current ones", "error", err.Error())
+				continue
+			}
+			if changed {
+				logger.Infow("certificates reloaded", "notAfter", r.Certificate().Leaf.NotAfter)
+			}
+		}
+	}
+}
+
+// Certificate returns the current certificate of the servers.
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.certificate
+}
+
+// TlsConfig returns the configuration of the servers, always serving the
+// current certificate. The client certificates are verified against the
+// current CAs by VerifyConnection instead of ClientCAs, which cannot
+// change once the listener started. Unlike VerifyPeerCertificate, it also
+// runs on the resumed sessions, so they are checked after a CA rotation.
+func (r *Reloader) TlsConfig() *tls.Config {
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return r.Certificate(), nil
+		},
+	}
+
+	if r.cfg.ClientCaFile == "" {
+		return tlsConfig
+	}
+
+	tlsConfig.ClientAuth = tls.RequireAnyClientCert
+	if r.cfg.ClientAuth == "optional" {
+		tlsConfig.ClientAuth = tls.RequestClientCert
+	}
+	tlsConfig.VerifyConnection = r.verifyClient
+
+	return tlsConfig
+}
+
+func (r *Reloader) verifyClient(state tls.ConnectionState) error {
+	certificates := state.PeerCertificates
+	if len(certificates) == 0 {
+		// only accepted when the client authentication is optional
+		return nil
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	r.mu.RLock()
+	clientCas := r.clientCas
+	r.mu.RUnlock()
+
+	_, err := certificates[0].Verify(x509.VerifyOptions{
+		Roots:         clientCas,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("the client certificate is not trusted: %s", err.Error())
+	}
+
+	return nil
+}
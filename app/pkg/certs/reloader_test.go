@@ -0,0 +1,388 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// testCa issues the certificates of the tests.
+type testCa struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	pem         []byte
+}
+
+func newTestCa(t *testing.T) testCa {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "canivete test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	return testCa{
+		certificate: certificate,
+		key:         key,
+		pem:         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+func (ca testCa) pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.certificate)
+	return pool
+}
+
+// issue returns a certificate and its key, both PEM encoded.
+func (ca testCa) issue(t *testing.T, commonName string, serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, &key.PublicKey, ca.key)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})
+}
+
+func (ca testCa) clientCertificate(t *testing.T, commonName string) tls.Certificate {
+	certPem, keyPem := ca.issue(t, commonName, 100, x509.ExtKeyUsageClientAuth)
+	certificate, err := tls.X509KeyPair(certPem, keyPem)
+	assert.Nil(t, err)
+	return certificate
+}
+
+// setupFiles writes a server certificate issued by the ca and the ca
+// bundle in a temporary directory.
+func setupFiles(t *testing.T, ca testCa, clientAuth string) config.TlsConfig {
+	dir := t.TempDir()
+	cfg := config.TlsConfig{
+		CertFile:       filepath.Join(dir, "tls.crt"),
+		KeyFile:        filepath.Join(dir, "tls.key"),
+		ClientCaFile:   filepath.Join(dir, "ca.crt"),
+		ClientAuth:     clientAuth,
+		ReloadInterval: 10 * time.Millisecond,
+	}
+	writeCertificate(t, ca, cfg, 2)
+	assert.Nil(t, ioutil.WriteFile(cfg.ClientCaFile, ca.pem, 0600))
+
+	return cfg
+}
+
+func writeCertificate(t *testing.T, ca testCa, cfg config.TlsConfig, serial int64) {
+	certPem, keyPem := ca.issue(t, "canivete", serial, x509.ExtKeyUsageServerAuth)
+	assert.Nil(t, ioutil.WriteFile(cfg.CertFile, certPem, 0600))
+	assert.Nil(t, ioutil.WriteFile(cfg.KeyFile, keyPem, 0600))
+}
+
+// serve serves over TLS a handler answering the client certificate name.
+func serve(t *testing.T, tlsConfig *tls.Config) string {
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/whoami", func(c *gin.Context) {
+		identity, ok := GetClientIdentity(c)
+		if !ok {
+			c.String(http.StatusOK, "anonymous")
+			return
+		}
+		c.String(http.StatusOK, identity.Name())
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := &http.Server{Handler: r, TLSConfig: tlsConfig}
+	go server.ServeTLS(listener, "", "")
+	t.Cleanup(func() { server.Close() })
+
+	return "https://" + listener.Addr().String() + "/whoami"
+}
+
+// get calls the url and returns the body and the serial number of the
+// server certificate.
+func get(ca testCa, url string, certificates ...tls.Certificate) (string, int64, error) {
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: ca.pool(), Certificates: certificates},
+	}}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return string(body), resp.TLS.PeerCertificates[0].SerialNumber.Int64(), nil
+}
+
+func TestNewReloaderFailsWhenFilesAreMissing(t *testing.T) {
+	// arrange
+	cfg := setupFiles(t, newTestCa(t), "require")
+	cfg.KeyFile = filepath.Join(t.TempDir(), "missing.key")
+
+	// act
+	_, err := NewReloader(cfg)
+
+	// assert
+	assert.NotNil(t, err)
+}
+
+func TestNewReloaderFailsWhenCaBundleIsEmpty(t *testing.T) {
+	// arrange
+	cfg := setupFiles(t, newTestCa(t), "require")
+	assert.Nil(t, ioutil.WriteFile(cfg.ClientCaFile, []byte("not a certificate"), 0600))
+
+	// act
+	_, err := NewReloader(cfg)
+
+	// assert
+	assert.NotNil(t, err)
+}
+
+func TestTlsConfigWithoutClientCa(t *testing.T) {
+	// arrange
+	ca := newTestCa(t)
+	cfg := setupFiles(t, ca, "require")
+	cfg.ClientCaFile = ""
+	reloader, err := NewReloader(cfg)
+	assert.Nil(t, err)
+	url := serve(t, reloader.TlsConfig())
+
+	// act
+	body, serial, err := get(ca, url)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "anonymous", body)
+	assert.Equal(t, int64(2), serial)
+}
+
+func TestTlsConfigRequiresTrustedClientCertificates(t *testing.T) {
+	// arrange
+	ca := newTestCa(t)
+	reloader, err := NewReloader(setupFiles(t, ca, "require"))
+	assert.Nil(t, err)
+	url := serve(t, reloader.TlsConfig())
+
+	tests := []struct {
+		name         string
+		certificates []tls.Certificate
+		expected     string
+	}{
+		{"trusted", []tls.Certificate{ca.clientCertificate(t, "ci-pipeline")}, "ci-pipeline"},
+		{"untrusted", []tls.Certificate{newTestCa(t).clientCertificate(t, "intruder")}, ""},
+		{"missing", nil, ""},
+	}
+
+	for _, test := range tests {
+		// act
+		body, _, err := get(ca, url, test.certificates...)
+
+		// assert
+		if test.expected == "" {
+			assert.NotNil(t, err, test.name)
+			continue
+		}
+		assert.Nil(t, err, test.name)
+		assert.Equal(t, test.expected, body, test.name)
+	}
+}
+
+func TestTlsConfigAcceptsClientsWithoutCertificatesWhenOptional(t *testing.T) {
+	// arrange
+	ca := newTestCa(t)
+	reloader, err := NewReloader(setupFiles(t, ca, "optional"))
+	assert.Nil(t, err)
+	url := serve(t, reloader.TlsConfig())
+
+	// act
+	anonymous, _, anonymousErr := get(ca, url)
+	named, _, namedErr := get(ca, url, ca.clientCertificate(t, "ci-pipeline"))
+	_, _, untrustedErr := get(ca, url, newTestCa(t).clientCertificate(t, "intruder"))
+
+	// assert
+	assert.Nil(t, anonymousErr)
+	assert.Equal(t, "anonymous", anonymous)
+	assert.Nil(t, namedErr)
+	assert.Equal(t, "ci-pipeline", named)
+	assert.NotNil(t, untrustedErr)
+}
+
+func TestReloadServesTheNewCertificate(t *testing.T) {
+	// arrange
+	ca := newTestCa(t)
+	cfg := setupFiles(t, ca, "require")
+	reloader, err := NewReloader(cfg)
+	assert.Nil(t, err)
+	url := serve(t, reloader.TlsConfig())
+	writeCertificate(t, ca, cfg, 3)
+
+	// act
+	changed, err := reloader.Reload()
+	_, serial, getErr := get(ca, url, ca.clientCertificate(t, "ci-pipeline"))
+	changedAgain, _ := reloader.Reload()
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, changed)
+	assert.Nil(t, getErr)
+	assert.Equal(t, int64(3), serial)
+	assert.False(t, changedAgain)
+}
+
+func TestReloadKeepsTheCurrentCertificateWhenFilesAreInvalid(t *testing.T) {
+	// arrange
+	ca := newTestCa(t)
+	cfg := setupFiles(t, ca, "require")
+	reloader, err := NewReloader(cfg)
+	assert.Nil(t, err)
+	url := serve(t, reloader.TlsConfig())
+	assert.Nil(t, ioutil.WriteFile(cfg.KeyFile, []byte("half written key"), 0600))
+
+	// act
+	changed, err := reloader.Reload()
+	_, serial, getErr := get(ca, url, ca.clientCertificate(t, "ci-pipeline"))
+
+	// assert
+	assert.NotNil(t, err)
+	assert.False(t, changed)
+	assert.Nil(t, getErr)
+	assert.Equal(t, int64(2), serial)
+}
+
+func TestReloadTrustsTheNewClientCa(t *testing.T) {
+	// arrange
+	ca := newTestCa(t)
+	cfg := setupFiles(t, ca, "require")
+	reloader, err := NewReloader(cfg)
+	assert.Nil(t, err)
+	url := serve(t, reloader.TlsConfig())
+	newCa := newTestCa(t)
+	assert.Nil(t, ioutil.WriteFile(cfg.ClientCaFile, newCa.pem, 0600))
+
+	// act
+	_, err = reloader.Reload()
+	_, _, oldErr := get(ca, url, ca.clientCertificate(t, "ci-pipeline"))
+	body, _, newErr := get(ca, url, newCa.clientCertificate(t, "ci-pipeline"))
+
+	// assert
+	assert.Nil(t, err)
+	assert.NotNil(t, oldErr)
+	assert.Nil(t, newErr)
+	assert.Equal(t, "ci-pipeline", body)
+}
+
+func TestWatchReloadsChangedFiles(t *testing.T) {
+	// arrange
+	ca := newTestCa(t)
+	cfg := setupFiles(t, ca, "require")
+	reloader, err := NewReloader(cfg)
+	assert.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Watch(ctx)
+
+	// act
+	writeCertificate(t, ca, cfg, 4)
+
+	// assert
+	assert.Eventually(t, func() bool {
+		return reloader.Certificate().Leaf.SerialNumber.Int64() == 4
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestReloadChecksTheResumedSessionsAgainstTheNewClientCa(t *testing.T) {
+	// arrange
+	ca := newTestCa(t)
+	cfg := setupFiles(t, ca, "require")
+	reloader, err := NewReloader(cfg)
+	assert.Nil(t, err)
+	url := serve(t, reloader.TlsConfig())
+	client := &http.Client{Transport: &http.Transport{
+		DisableKeepAlives: true,
+		TLSClientConfig: &tls.Config{
+			RootCAs:            ca.pool(),
+			Certificates:       []tls.Certificate{ca.clientCertificate(t, "ci-pipeline")},
+			ClientSessionCache: tls.NewLRUClientSessionCache(1),
+		},
+	}}
+	get := func() (*http.Response, error) {
+		resp, err := client.Get(url)
+		if err != nil {
+			return nil, err
+		}
+		ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		return resp, nil
+	}
+	_, err = get()
+	assert.Nil(t, err)
+	resumed, err := get()
+	assert.Nil(t, err)
+	assert.True(t, resumed.TLS.DidResume)
+	assert.Nil(t, ioutil.WriteFile(cfg.ClientCaFile, newTestCa(t).pem, 0600))
+
+	// act
+	_, err = reloader.Reload()
+	_, resumedErr := get()
+
+	// assert
+	assert.Nil(t, err)
+	assert.NotNil(t, resumedErr)
+}
@@ -0,0 +1,263 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"go.uber.org/zap"
+)
+
+var logger *zap.SugaredLogger = logging.GetLogger()
+
+// userAgent identifies the client in the access logs of the api.
+const userAgent = "canivete-api-client"
+
+// Client calls the tools of a canivete-api server over HTTP. It implements
+// the interfaces of the core services, so remote and in-process calls are
+// interchangeable:
+//
+//	var p programming.Interface = &programmingcore.Service{}
+//	p, _ = client.New("https://canivete.example.com", client.WithApiKey(key))
+//
+// The interface methods cannot return the errors of NewUuid and
+// FromUnitTimestamp, which go to the error handler. The methods ending in
+// Context return every error and take a context.
+type Client struct {
+	baseUrl    *url.URL
+	httpClient *http.Client
+	headers    http.Header
+	timeout    time.Duration
+	retries    int
+	backoff    time.Duration
+	onError    func(error)
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHttpClient sets the http client sending the requests, by default
+// http.DefaultClient.
+func WithHttpClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout bounds each attempt of a call, 30 seconds by default.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithRetries sets how many times the calls failing to connect or with
+// 429, 502 or 503 are retried, 2 by default. The GET calls are also
+// retried on the other network errors and on 504, as the api may still be
+// running the others. The waits start at
+// backoff and double on each retry, unless the server asks for longer
+// with Retry-After.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// WithApiKey authenticates the calls with an api key.
+func WithApiKey(apiKey string) Option {
+	return WithHeader(auth.ApiKeyHeader, apiKey)
+}
+
+// WithBearerToken authenticates the calls with a JWT.
+func WithBearerToken(token string) Option {
+	return WithHeader("Authorization", "Bearer "+token)
+}
+
+// WithHeader adds a header to every call.
+func WithHeader(name, value string) Option {
+	return func(c *Client) {
+		c.headers.Set(name, value)
+	}
+}
+
+// WithErrorHandler sets the function receiving the errors the interface
+// methods cannot return. By default they are logged.
+func WithErrorHandler(onError func(error)) Option {
+	return func(c *Client) {
+		c.onError = onError
+	}
+}
+
+// New creates a client of the api served on baseUrl, like
+// http://localhost:8080.
+func New(baseUrl string, options ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseUrl, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid base url %q, must be http or https", baseUrl)
+	}
+
+	c := &Client{
+		baseUrl:    u,
+		httpClient: http.DefaultClient,
+		headers:    http.Header{},
+		timeout:    30 * time.Second,
+		retries:    2,
+		backoff:    200 * time.Millisecond,
+		onError: func(err error) {
+			logger.Warnw("canivete-api call failed", "error", err.Error())
+		},
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	return c, nil
+}
+
+// call sends the request, retrying it when it may succeed later, and
+// decodes the JSON response into output. The errors of the api are
+// returned as apierrors.ApiError.
+func (c *Client) call(ctx context.Context, method, path, contentType string, body []byte, output interface{}) error {
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retryAfter time.Duration
+		retryAfter, err = c.attempt(ctx, method, path, contentType, body, output)
+		if retryAfter < 0 || attempt >= c.retries || ctx.Err() != nil {
+			return err
+		}
+
+		wait := c.backoff << attempt
+		if retryAfter > wait {
+			wait = retryAfter
+		}
+		logging.FromContext(ctx).Debugw("retrying canivete-api call", "path", path, "attempt", attempt+1, "error", err.Error())
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends the request once. It returns a negative duration when the
+// call must not be retried, else how long the server asked to wait.
+func (c *Client) attempt(ctx context.Context, method, path, contentType string, body []byte, output interface{}) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseUrl.String()+path, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	for name, values := range c.headers {
+		req.Header[name] = values
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if method == http.MethodGet || notConnected(err) {
+			return 0, err
+		}
+		return -1, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		err = decodeError(resp)
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+			return retryAfter(resp), err
+		case http.StatusGatewayTimeout:
+			if method == http.MethodGet {
+				return retryAfter(resp), err
+			}
+		}
+		return -1, err
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(output)
+	if err != nil {
+		return -1, fmt.Errorf("invalid response from %s: %w", path, err)
+	}
+
+	return -1, nil
+}
+
+// notConnected tells if the request failed before reaching the api, so
+// it can be sent again whatever its method.
+func notConnected(err error) bool {
+	var opError *net.OpError
+	return errors.As(err, &opError) && opError.Op == "dial"
+}
+
+// decodeError reads the problem details of a failed call. Responses that
+// are not problems, like the ones of proxies, get an error with their
+// status.
+func decodeError(resp *http.Response) error {
+	apiError, err := apierrors.FromResponse(resp)
+	if err == nil {
+		return apiError
+	}
+
+	detail, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+	apiError = apierrors.New(resp.StatusCode, "", strings.TrimSpace(string(detail)))
+	apiError.Type = ""
+	return apiError
+}
+
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// IsCode tells if the error is an error of the api with the code, like
+// apierrors.CodeInvalidToken.
+func IsCode(err error, code string) bool {
+	var apiError apierrors.ApiError
+	return errors.As(err, &apiError) && apiError.Code == code
+}
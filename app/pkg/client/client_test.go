@@ -0,0 +1,320 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/datetime"
+	"github.com/renato0307/canivete-api/pkg/finance"
+	"github.com/renato0307/canivete-api/pkg/internet"
+	"github.com/renato0307/canivete-api/pkg/programming"
+	datetimecore "github.com/renato0307/canivete-core/interface/datetime"
+	financecore "github.com/renato0307/canivete-core/interface/finance"
+	internetcore "github.com/renato0307/canivete-core/interface/internet"
+	programmingcore "github.com/renato0307/canivete-core/interface/programming"
+	"github.com/stretchr/testify/assert"
+)
+
+type mocks struct {
+	programming programmingcore.MockInterface
+	datetime    datetimecore.MockInterface
+	finance     financecore.MockInterface
+	internet    internetcore.MockInterface
+}
+
+// setupServer serves the tool groups of the api, backed by mocks.
+func setupServer(t *testing.T) (*httptest.Server, *mocks) {
+	gin.SetMode(gin.TestMode)
+	m := &mocks{}
+	r := gin.New()
+	v1 := r.Group("/v1")
+	programming.SetRouterGroup(&m.programming, v1)
+	datetime.SetRouterGroup(&m.datetime, v1)
+	finance.SetRouterGroup(&m.finance, v1)
+	internet.SetRouterGroup(&m.internet, v1)
+
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server, m
+}
+
+func TestClientCallsTheTools(t *testing.T) {
+	// arrange
+	server, m := setupServer(t)
+	uuid := programmingcore.UuidOutput{UUID: "d967aaad-1df5-485d-96b4-43d4247972e7"}
+	jwt := programmingcore.JwtDebuggerOutput{
+		Header:  map[string]interface{}{"alg": "HS256"},
+		Payload: map[string]interface{}{"sub": "1234567890"},
+	}
+	fromUnix := datetimecore.FromUnixTimestampOutput{UnixTimestamp: 1638964800, UtcTimestamp: "Wed Dec  8 12:00:00 UTC 2021"}
+	detail := financecore.CompoundInterestsDetailOutput{FinalAmount: 5416, TotalContributions: 5000, Interests: 416}
+	interests := financecore.CompoundInterestsOutput{
+		Total:   detail,
+		History: []financecore.CompoundInterestsHistoryEntryOutput{{Period: "1", Totals: detail}},
+	}
+	medium := internetcore.ConvertMediumToMdOutput{Markdown: "# title", PostId: "a6e4a6e1a3f1"}
+	m.programming.On("NewUuid").Return(uuid)
+	m.programming.On("DebugJwt", "token").Return(jwt, nil)
+	m.datetime.On("FromUnitTimestamp", int64(1638964800)).Return(fromUnix)
+	m.finance.On("CalculateCompoundInterests", 5000.0, 12.0, 1.0, 100.0, 12.0, 8.0).Return(interests, nil)
+	m.internet.On("ConvertMediumToMd", "a6e4a6e1a3f1").Return(medium, nil)
+	c, err := New(server.URL)
+	assert.Nil(t, err)
+
+	// act
+	uuidOutput := c.NewUuid()
+	jwtOutput, jwtErr := c.DebugJwt("token")
+	fromUnixOutput := c.FromUnitTimestamp(1638964800)
+	interestsOutput, interestsErr := c.CalculateCompoundInterests(5000, 12, 1, 100, 12, 8)
+	mediumOutput, mediumErr := c.ConvertMediumToMd("a6e4a6e1a3f1")
+
+	// assert
+	assert.Equal(t, uuid, uuidOutput)
+	assert.Nil(t, jwtErr)
+	assert.Equal(t, jwt, jwtOutput)
+	assert.Equal(t, fromUnix, fromUnixOutput)
+	assert.Nil(t, interestsErr)
+	assert.Equal(t, interests, interestsOutput)
+	assert.Nil(t, mediumErr)
+	assert.Equal(t, medium, mediumOutput)
+}
+
+func TestClientDecodesTheErrors(t *testing.T) {
+	// arrange
+	server, m := setupServer(t)
+	m.programming.On("DebugJwt", "token").Return(programmingcore.JwtDebuggerOutput{}, errors.New("fake error"))
+	c, _ := New(server.URL)
+
+	// act
+	_, jwtErr := c.DebugJwt("token")
+	_, interestsErr := c.CalculateCompoundInterests(5000, 12, 1, 0, 12, 0)
+
+	// assert
+	apiError := apierrors.ApiError{}
+	if assert.True(t, errors.As(jwtErr, &apiError)) {
+		assert.Equal(t, http.StatusBadRequest, apiError.Status)
+		assert.Equal(t, "/v1/programming/jwt-debugger", apiError.Instance)
+	}
+	assert.True(t, IsCode(jwtErr, apierrors.CodeInvalidToken))
+	if assert.True(t, errors.As(interestsErr, &apiError)) {
+		assert.Equal(t, apierrors.CodeValidationFailed, apiError.Code)
+		assert.Equal(t, "InterestRate", apiError.Errors[0].Field)
+	}
+}
+
+func TestClientReportsTheErrorsOfTheInterfaceMethods(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	reported := []error{}
+	c, _ := New(server.URL, WithErrorHandler(func(err error) { reported = append(reported, err) }))
+
+	// act
+	uuid := c.NewUuid()
+	fromUnix := c.FromUnitTimestamp(1638964800)
+
+	// assert
+	assert.Empty(t, uuid.UUID)
+	assert.Empty(t, fromUnix.UtcTimestamp)
+	if assert.Len(t, reported, 2) {
+		apiError := apierrors.ApiError{}
+		assert.True(t, errors.As(reported[0], &apiError))
+		assert.Equal(t, http.StatusNotFound, apiError.Status)
+		assert.Equal(t, "404 page not found", apiError.Detail)
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	tests := []struct {
+		status   int
+		retried  bool
+		attempts int32
+	}{
+		{http.StatusServiceUnavailable, true, 3},
+		{http.StatusTooManyRequests, true, 3},
+		{http.StatusBadRequest, false, 1},
+		{http.StatusInternalServerError, false, 1},
+	}
+
+	for _, test := range tests {
+		// arrange
+		var attempts int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&attempts, 1)
+			w.Header().Set("Content-Type", apierrors.ContentType)
+			w.WriteHeader(test.status)
+			fmt.Fprintf(w, `{"status":%d}`, test.status)
+		}))
+		c, _ := New(server.URL, WithRetries(2, time.Millisecond))
+
+		// act
+		_, err := c.NewUuidContext(context.Background())
+
+		// assert
+		assert.NotNil(t, err)
+		assert.Equal(t, test.attempts, atomic.LoadInt32(&attempts), "unexpected attempts for %d", test.status)
+		server.Close()
+	}
+}
+
+func TestClientRetriesTheGatewayTimeoutsOfTheGetCallsOnly(t *testing.T) {
+	// arrange
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer server.Close()
+	c, _ := New(server.URL, WithRetries(2, time.Millisecond))
+
+	// act
+	_, getErr := c.NewUuidContext(context.Background())
+	getAttempts := atomic.SwapInt32(&attempts, 0)
+	_, postErr := c.ConvertMediumToMdContext(context.Background(), "a6e4a6e1a3f1")
+
+	// assert
+	assert.NotNil(t, getErr)
+	assert.NotNil(t, postErr)
+	assert.Equal(t, int32(3), getAttempts)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+func TestClientRetriesThePostCallsFailingToConnect(t *testing.T) {
+	// arrange
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	var attempts int32
+	c, _ := New(server.URL, WithRetries(2, time.Millisecond), WithHttpClient(&http.Client{
+		Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&attempts, 1)
+			return http.DefaultTransport.RoundTrip(req)
+		}),
+	}))
+
+	// act
+	_, err := c.ConvertMediumToMdContext(context.Background(), "a6e4a6e1a3f1")
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestClientRetriesUntilTheCallSucceeds(t *testing.T) {
+	// arrange
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"UUID":"d967aaad"}`))
+	}))
+	defer server.Close()
+	c, _ := New(server.URL, WithRetries(3, time.Millisecond))
+
+	// act
+	output, err := c.NewUuidContext(context.Background())
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "d967aaad", output.UUID)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestClientTimeout(t *testing.T) {
+	// arrange
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	c, _ := New(server.URL, WithTimeout(20*time.Millisecond), WithRetries(0, 0))
+
+	// act
+	start := time.Now()
+	_, err := c.NewUuidContext(context.Background())
+
+	// assert
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+}
+
+func TestClientSendsTheCredentials(t *testing.T) {
+	tests := []struct {
+		option Option
+		header string
+		value  string
+	}{
+		{WithApiKey("secret"), auth.ApiKeyHeader, "secret"},
+		{WithBearerToken("token"), "Authorization", "Bearer token"},
+		{WithHeader("X-Request-ID", "id-1"), "X-Request-ID", "id-1"},
+	}
+
+	for _, test := range tests {
+		// arrange
+		var received http.Header
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header
+			body, _ = ioutil.ReadAll(r.Body)
+			w.Write([]byte(`{"UnixTimestamp":1,"UtcTimestamp":"Thu Jan  1 00:00:01 UTC 1970"}`))
+		}))
+		c, _ := New(server.URL+"/", test.option)
+
+		// act
+		_, err := c.FromUnixTimestampContext(context.Background(), 1)
+
+		// assert
+		assert.Nil(t, err)
+		assert.Equal(t, test.value, received.Get(test.header))
+		assert.Equal(t, "1", string(body))
+		assert.Equal(t, userAgent, received.Get("User-Agent"))
+		server.Close()
+	}
+}
+
+func TestNewWithInvalidBaseUrl(t *testing.T) {
+	for _, baseUrl := range []string{"localhost:8080", "ftp://localhost", "://"} {
+		// act
+		_, err := New(baseUrl)
+
+		// assert
+		assert.NotNil(t, err, "expected error for %s", baseUrl)
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,134 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/renato0307/canivete-core/interface/datetime"
+	"github.com/renato0307/canivete-core/interface/finance"
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/renato0307/canivete-core/interface/programming"
+)
+
+var (
+	_ programming.Interface = (*Client)(nil)
+	_ datetime.Interface    = (*Client)(nil)
+	_ finance.Interface     = (*Client)(nil)
+	_ internet.Interface    = (*Client)(nil)
+)
+
+// textPlain is the content type of the tools taking a raw value.
+const textPlain = "text/plain; charset=utf-8"
+
+// compoundInterestsInput is the body of the compound interests calculation.
+type compoundInterestsInput struct {
+	InterestRate               float64
+	CompoundPeriods            float64
+	InvestAmount               float64
+	RegularContributions       float64
+	RegularContributionsPeriod float64
+	Time                       float64
+}
+
+// NewUuid implements programming.Interface.
+func (c *Client) NewUuid() programming.UuidOutput {
+	output, err := c.NewUuidContext(context.Background())
+	if err != nil {
+		c.onError(err)
+	}
+
+	return output
+}
+
+// NewUuidContext generates a random UUID.
+func (c *Client) NewUuidContext(ctx context.Context) (programming.UuidOutput, error) {
+	output := programming.UuidOutput{}
+	err := c.call(ctx, http.MethodGet, "/v1/programming/uuid", "", nil, &output)
+	return output, err
+}
+
+// DebugJwt implements programming.Interface.
+func (c *Client) DebugJwt(tokenString string) (programming.JwtDebuggerOutput, error) {
+	return c.DebugJwtContext(context.Background(), tokenString)
+}
+
+// DebugJwtContext decodes the header and the payload of a JWT.
+func (c *Client) DebugJwtContext(ctx context.Context, tokenString string) (programming.JwtDebuggerOutput, error) {
+	output := programming.JwtDebuggerOutput{}
+	err := c.call(ctx, http.MethodPost, "/v1/programming/jwt-debugger", textPlain, []byte(tokenString), &output)
+	return output, err
+}
+
+// FromUnitTimestamp implements datetime.Interface.
+func (c *Client) FromUnitTimestamp(unixTime int64) datetime.FromUnixTimestampOutput {
+	output, err := c.FromUnixTimestampContext(context.Background(), unixTime)
+	if err != nil {
+		c.onError(err)
+	}
+
+	return output
+}
+
+// FromUnixTimestampContext converts a unix timestamp to an UTC date.
+func (c *Client) FromUnixTimestampContext(ctx context.Context, unixTime int64) (datetime.FromUnixTimestampOutput, error) {
+	output := datetime.FromUnixTimestampOutput{}
+	body := []byte(strconv.FormatInt(unixTime, 10))
+	err := c.call(ctx, http.MethodPost, "/v1/datetime/fromunix", textPlain, body, &output)
+	return output, err
+}
+
+// CalculateCompoundInterests implements finance.Interface.
+func (c *Client) CalculateCompoundInterests(p, n, t, m, y, rInt float64) (finance.CompoundInterestsOutput, error) {
+	return c.CalculateCompoundInterestsContext(context.Background(), p, n, t, m, y, rInt)
+}
+
+// CalculateCompoundInterestsContext calculates the compound interests of
+// investing p, compounded n times by period for t periods, with regular
+// contributions of m made y times by period and an interest rate of rInt.
+func (c *Client) CalculateCompoundInterestsContext(ctx context.Context, p, n, t, m, y, rInt float64) (finance.CompoundInterestsOutput, error) {
+	output := finance.CompoundInterestsOutput{}
+	body, err := json.Marshal(compoundInterestsInput{
+		InterestRate:               rInt,
+		CompoundPeriods:            n,
+		InvestAmount:               p,
+		RegularContributions:       m,
+		RegularContributionsPeriod: y,
+		Time:                       t,
+	})
+	if err != nil {
+		return output, err
+	}
+
+	err = c.call(ctx, http.MethodPost, "/v1/finance/calculate-compound-interests", "application/json", body, &output)
+	return output, err
+}
+
+// ConvertMediumToMd implements internet.Interface.
+func (c *Client) ConvertMediumToMd(postId string) (internet.ConvertMediumToMdOutput, error) {
+	return c.ConvertMediumToMdContext(context.Background(), postId)
+}
+
+// ConvertMediumToMdContext converts a Medium post to markdown.
+func (c *Client) ConvertMediumToMdContext(ctx context.Context, postId string) (internet.ConvertMediumToMdOutput, error) {
+	output := internet.ConvertMediumToMdOutput{}
+	err := c.call(ctx, http.MethodPost, "/v1/internet/medium-to-md", textPlain, []byte(postId), &output)
+	return output, err
+}
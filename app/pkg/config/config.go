@@ -0,0 +1,1091 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v2"
+)
+
+// Names of the service groups mounted under /v1.
+const (
+	GroupProgramming = "programming"
+	GroupDatetime    = "datetime"
+	GroupFinance     = "finance"
+	GroupInternet    = "internet"
+)
+
+// Groups lists every service group known by the api.
+var Groups = []string{GroupProgramming, GroupDatetime, GroupFinance, GroupInternet}
+
+// Config holds the effective settings of the api.
+type Config struct {
+	Server     ServerConfig           `yaml:"server" toml:"server"`
+	Grpc       GrpcConfig             `yaml:"grpc" toml:"grpc"`
+	Logging    LoggingConfig          `yaml:"logging" toml:"logging"`
+	Auth       AuthConfig             `yaml:"auth" toml:"auth"`
+	RateLimit  RateLimitConfig        `yaml:"rateLimit" toml:"rateLimit"`
+	Limits     LimitsConfig           `yaml:"limits" toml:"limits"`
+	Batch      BatchConfig            `yaml:"batch" toml:"batch"`
+	Graphql    GraphqlConfig          `yaml:"graphql" toml:"graphql"`
+	Cache      CacheConfig            `yaml:"cache" toml:"cache"`
+	Cors       CorsConfig             `yaml:"cors" toml:"cors"`
+	Audit      AuditConfig            `yaml:"audit" toml:"audit"`
+	Admin      AdminConfig            `yaml:"admin" toml:"admin"`
+	Versioning VersioningConfig       `yaml:"versioning" toml:"versioning"`
+	Health     HealthConfig           `yaml:"health" toml:"health"`
+	Metrics    MetricsConfig          `yaml:"metrics" toml:"metrics"`
+	Tracing    TracingConfig          `yaml:"tracing" toml:"tracing"`
+	Groups     map[string]GroupConfig `yaml:"groups" toml:"groups"`
+
+	// ConfigFile is the path of the file the configuration was read from.
+	ConfigFile string `yaml:"-" toml:"-"`
+	// PrintConfig asks for the effective configuration to be dumped.
+	PrintConfig bool `yaml:"-" toml:"-"`
+}
+
+// ServerConfig holds the settings of the http server.
+type ServerConfig struct {
+	Address        string   `yaml:"address" toml:"address"`
+	Mode           string   `yaml:"mode" toml:"mode"`
+	TrustedProxies []string `yaml:"trustedProxies" toml:"trustedProxies"`
+	// RequestIdHeader is the header carrying the id correlating a request
+	// with the logs and the errors.
+	RequestIdHeader string `yaml:"requestIdHeader" toml:"requestIdHeader"`
+	// AssetsUrl is the base url the interactive documentation and GraphiQL
+	// load their scripts and styles from, by default the copies embedded in
+	// the binary.
+	AssetsUrl string `yaml:"assetsUrl" toml:"assetsUrl"`
+
+	ReadTimeout       time.Duration `yaml:"readTimeout" toml:"readTimeout"`
+	ReadHeaderTimeout time.Duration `yaml:"readHeaderTimeout" toml:"readHeaderTimeout"`
+	WriteTimeout      time.Duration `yaml:"writeTimeout" toml:"writeTimeout"`
+	IdleTimeout       time.Duration `yaml:"idleTimeout" toml:"idleTimeout"`
+	// ShutdownTimeout is how long in-flight requests have to complete
+	// once the server is asked to stop.
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" toml:"shutdownTimeout"`
+	// ShutdownHooksTimeout is how long the shutdown hooks have to run,
+	// like flushing the traces, once the requests are drained.
+	ShutdownHooksTimeout time.Duration `yaml:"shutdownHooksTimeout" toml:"shutdownHooksTimeout"`
+
+	Tls TlsConfig `yaml:"tls" toml:"tls"`
+}
+
+// TlsConfig holds the certificates of the http and gRPC servers. TLS is
+// enabled when the certificate is set.
+type TlsConfig struct {
+	CertFile string `yaml:"certFile" toml:"certFile"`
+	KeyFile  string `yaml:"keyFile" toml:"keyFile"`
+	// ClientCaFile is the bundle of the CAs verifying the certificates of
+	// the clients. Setting it enables mutual TLS.
+	ClientCaFile string `yaml:"clientCaFile" toml:"clientCaFile"`
+	// ClientAuth is require, or optional to accept the clients without
+	// certificates, whose certificates are still verified when sent.
+	ClientAuth string `yaml:"clientAuth" toml:"clientAuth"`
+	// ReloadInterval is how often the files are checked for changes.
+	ReloadInterval time.Duration `yaml:"reloadInterval" toml:"reloadInterval"`
+}
+
+// Enabled tells if the servers serve TLS.
+func (c TlsConfig) Enabled() bool {
+	return c.CertFile != ""
+}
+
+func (c TlsConfig) validate() error {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("tls cert file and key file must be set together")
+	}
+
+	if c.ClientCaFile != "" && c.CertFile == "" {
+		return fmt.Errorf("tls client ca file requires the cert file and key file")
+	}
+
+	switch c.ClientAuth {
+	case "require", "optional":
+	default:
+		return fmt.Errorf("invalid tls client auth %q, must be require or optional", c.ClientAuth)
+	}
+
+	if c.ReloadInterval <= 0 {
+		return fmt.Errorf("tls reload interval must be positive")
+	}
+
+	return nil
+}
+
+// GrpcConfig holds the settings of the gRPC server, serving the service
+// groups alongside the http server.
+type GrpcConfig struct {
+	Enabled bool   `yaml:"enabled" toml:"enabled"`
+	Address string `yaml:"address" toml:"address"`
+	// Reflection lets clients like grpcurl list the services.
+	Reflection bool `yaml:"reflection" toml:"reflection"`
+}
+
+// LoggingConfig holds the settings of the logger.
+type LoggingConfig struct {
+	// Format is json or console.
+	Format string `yaml:"format" toml:"format"`
+	// Level is the minimum level logged: debug, info, warn or error.
+	Level string `yaml:"level" toml:"level"`
+	// Outputs are the sinks the logs are written to: stdout, stderr or
+	// file paths.
+	Outputs  []string       `yaml:"outputs" toml:"outputs"`
+	Sampling SamplingConfig `yaml:"sampling" toml:"sampling"`
+	// AccessLog enables logging one entry per request.
+	AccessLog bool `yaml:"accessLog" toml:"accessLog"`
+}
+
+// SamplingConfig holds the settings of the log sampling. Each second the
+// first Initial entries with the same level and message are logged, then
+// one every Thereafter.
+type SamplingConfig struct {
+	Enabled    bool `yaml:"enabled" toml:"enabled"`
+	Initial    int  `yaml:"initial" toml:"initial"`
+	Thereafter int  `yaml:"thereafter" toml:"thereafter"`
+}
+
+// ScopeAll grants access to every service group.
+const ScopeAll = "*"
+
+// ScopeAdmin grants access to the admin endpoints. ScopeAll does not
+// include it, it must be granted on its own.
+const ScopeAdmin = "admin"
+
+// AuthConfig holds the settings of the authentication of the service
+// groups.
+type AuthConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// ApiKeysFile is a YAML file listing api keys, added to ApiKeys.
+	ApiKeysFile string         `yaml:"apiKeysFile" toml:"apiKeysFile"`
+	ApiKeys     []ApiKeyConfig `yaml:"apiKeys" toml:"apiKeys"`
+	Jwt         JwtConfig      `yaml:"jwt" toml:"jwt"`
+}
+
+// JwtConfig holds the settings of the authentication with bearer JWTs
+// signed by an OIDC provider.
+type JwtConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// JwksUrl is the address of the keys of the provider, exclusive with
+	// JwksFile.
+	JwksUrl  string `yaml:"jwksUrl" toml:"jwksUrl"`
+	JwksFile string `yaml:"jwksFile" toml:"jwksFile"`
+	// JwksRefreshInterval is how long the keys are cached.
+	JwksRefreshInterval time.Duration `yaml:"jwksRefreshInterval" toml:"jwksRefreshInterval"`
+	// Issuer and Audience are checked when not empty.
+	Issuer   string `yaml:"issuer" toml:"issuer"`
+	Audience string `yaml:"audience" toml:"audience"`
+	// ClockSkew is the tolerance when checking the token times.
+	ClockSkew time.Duration `yaml:"clockSkew" toml:"clockSkew"`
+	// ScopesClaim is the claim holding the scopes of the caller, either a
+	// space separated string or a list.
+	ScopesClaim string `yaml:"scopesClaim" toml:"scopesClaim"`
+	// ScopeMapping maps the values of the scopes claim to service groups.
+	// When empty, the values are the names of the service groups.
+	ScopeMapping map[string][]string `yaml:"scopeMapping" toml:"scopeMapping"`
+}
+
+// ApiKeyConfig describes an api key. Only the hash of the key is kept.
+type ApiKeyConfig struct {
+	// Name identifies the caller in the logs.
+	Name string `yaml:"name" toml:"name"`
+	// Hash is the hex encoded SHA-256 of the key.
+	Hash string `yaml:"hash" toml:"hash"`
+	// Scopes are the service groups the key can call, * for all.
+	Scopes []string `yaml:"scopes" toml:"scopes"`
+}
+
+// Validate checks the api key is usable.
+func (k ApiKeyConfig) Validate() error {
+	if k.Name == "" {
+		return fmt.Errorf("api key name cannot be empty")
+	}
+
+	hash, err := hex.DecodeString(k.Hash)
+	if err != nil || len(hash) != sha256.Size {
+		return fmt.Errorf("hash of api key %q must be a hex encoded SHA-256", k.Name)
+	}
+
+	for _, scope := range k.Scopes {
+		if !isKnownScope(scope) {
+			return fmt.Errorf("unknown scope %q for api key %q", scope, k.Name)
+		}
+	}
+
+	return nil
+}
+
+func (j JwtConfig) validate() error {
+	if (j.JwksUrl == "") == (j.JwksFile == "") {
+		return fmt.Errorf("jwt auth needs either a jwks url or a jwks file")
+	}
+
+	if j.JwksRefreshInterval <= 0 {
+		return fmt.Errorf("jwks refresh interval must be positive")
+	}
+
+	if j.ClockSkew < 0 {
+		return fmt.Errorf("jwt clock skew cannot be negative")
+	}
+
+	if j.ScopesClaim == "" {
+		return fmt.Errorf("jwt scopes claim cannot be empty")
+	}
+
+	for value, scopes := range j.ScopeMapping {
+		for _, scope := range scopes {
+			if !isKnownScope(scope) {
+				return fmt.Errorf("unknown scope %q mapped from %q", scope, value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RateLimitConfig holds the settings of the rate limiting of the service
+// groups. Each client has a bucket of Burst tokens, refilled at Rate tokens
+// per second, and each request takes the cost of its route.
+type RateLimitConfig struct {
+	Enabled bool    `yaml:"enabled" toml:"enabled"`
+	Rate    float64 `yaml:"rate" toml:"rate"`
+	Burst   int     `yaml:"burst" toml:"burst"`
+	// Costs overrides the cost of routes, like /v1/programming/uuid.
+	Costs map[string]int `yaml:"costs" toml:"costs"`
+}
+
+// LimitsConfig holds the limits of the requests to the service groups.
+type LimitsConfig struct {
+	// MaxBodySize is the maximum size of the bodies, in bytes.
+	MaxBodySize int64 `yaml:"maxBodySize" toml:"maxBodySize"`
+	// Timeout is how long the tools can run.
+	Timeout time.Duration `yaml:"timeout" toml:"timeout"`
+	// Routes overrides the limits of routes, like /v1/programming/uuid.
+	Routes map[string]RouteLimitsConfig `yaml:"routes" toml:"routes"`
+}
+
+// RouteLimitsConfig holds the limits of a route. Zero values keep the
+// defaults.
+type RouteLimitsConfig struct {
+	MaxBodySize int64         `yaml:"maxBodySize,omitempty" toml:"maxBodySize,omitempty"`
+	Timeout     time.Duration `yaml:"timeout,omitempty" toml:"timeout,omitempty"`
+}
+
+// For returns the limits of the route.
+func (l LimitsConfig) For(route string) (int64, time.Duration) {
+	maxBodySize, timeout := l.MaxBodySize, l.Timeout
+	if routeLimits, ok := l.Routes[route]; ok {
+		if routeLimits.MaxBodySize > 0 {
+			maxBodySize = routeLimits.MaxBodySize
+		}
+		if routeLimits.Timeout > 0 {
+			timeout = routeLimits.Timeout
+		}
+	}
+
+	return maxBodySize, timeout
+}
+
+// BatchConfig holds the settings of the batch endpoint, calling several
+// tools in a single request.
+type BatchConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// MaxSize is the maximum number of requests of a batch.
+	MaxSize int `yaml:"maxSize" toml:"maxSize"`
+	// Parallelism is how many requests of a batch run at the same time.
+	Parallelism int `yaml:"parallelism" toml:"parallelism"`
+}
+
+// GraphqlConfig holds the settings of the GraphQL endpoint, calling the
+// tools of the enabled groups in a single query.
+type GraphqlConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// MaxDepth is the maximum nesting of the fields of a query.
+	MaxDepth int `yaml:"maxDepth" toml:"maxDepth"`
+	// MaxComplexity is the maximum number of fields of a query.
+	MaxComplexity int `yaml:"maxComplexity" toml:"maxComplexity"`
+	// Playground serves GraphiQL to the browsers.
+	Playground bool `yaml:"playground" toml:"playground"`
+}
+
+// CacheConfig holds the settings of the caching of the responses.
+type CacheConfig struct {
+	// Enabled keeps the responses of the deterministic routes in memory.
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Size is the number of responses kept.
+	Size int `yaml:"size" toml:"size"`
+	// CacheControl overrides the Cache-Control header of routes, like
+	// /v1/datetime/fromunix.
+	CacheControl map[string]string `yaml:"cacheControl" toml:"cacheControl"`
+}
+
+// CorsConfig holds the CORS policy of the browser clients.
+type CorsConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// AllowedOrigins are origins like https://portal.example.com, * for
+	// any origin or https://*.example.com for the subdomains.
+	AllowedOrigins   []string      `yaml:"allowedOrigins" toml:"allowedOrigins"`
+	AllowedMethods   []string      `yaml:"allowedMethods" toml:"allowedMethods"`
+	AllowedHeaders   []string      `yaml:"allowedHeaders" toml:"allowedHeaders"`
+	ExposedHeaders   []string      `yaml:"exposedHeaders" toml:"exposedHeaders"`
+	AllowCredentials bool          `yaml:"allowCredentials" toml:"allowCredentials"`
+	MaxAge           time.Duration `yaml:"maxAge" toml:"maxAge"`
+	// Groups overrides the policy of the router groups, by path prefix
+	// like /v1/finance. The longest prefix of a route wins.
+	Groups map[string]CorsGroupConfig `yaml:"groups" toml:"groups"`
+}
+
+// CorsGroupConfig holds the CORS policy of a router group. Zero values
+// keep the defaults.
+type CorsGroupConfig struct {
+	AllowedOrigins   []string      `yaml:"allowedOrigins,omitempty" toml:"allowedOrigins,omitempty"`
+	AllowedMethods   []string      `yaml:"allowedMethods,omitempty" toml:"allowedMethods,omitempty"`
+	AllowedHeaders   []string      `yaml:"allowedHeaders,omitempty" toml:"allowedHeaders,omitempty"`
+	ExposedHeaders   []string      `yaml:"exposedHeaders,omitempty" toml:"exposedHeaders,omitempty"`
+	AllowCredentials *bool         `yaml:"allowCredentials,omitempty" toml:"allowCredentials,omitempty"`
+	MaxAge           time.Duration `yaml:"maxAge,omitempty" toml:"maxAge,omitempty"`
+}
+
+// For returns the policy of the route: the defaults overridden by the
+// group with the longest prefix of the route.
+func (c CorsConfig) For(route string) CorsConfig {
+	policy := c
+	prefix := ""
+	for groupPrefix := range c.Groups {
+		if hasPathPrefix(route, groupPrefix) && len(groupPrefix) > len(prefix) {
+			prefix = groupPrefix
+		}
+	}
+	if prefix == "" {
+		return policy
+	}
+
+	group := c.Groups[prefix]
+	if len(group.AllowedOrigins) > 0 {
+		policy.AllowedOrigins = group.AllowedOrigins
+	}
+	if len(group.AllowedMethods) > 0 {
+		policy.AllowedMethods = group.AllowedMethods
+	}
+	if len(group.AllowedHeaders) > 0 {
+		policy.AllowedHeaders = group.AllowedHeaders
+	}
+	if len(group.ExposedHeaders) > 0 {
+		policy.ExposedHeaders = group.ExposedHeaders
+	}
+	if group.AllowCredentials != nil {
+		policy.AllowCredentials = *group.AllowCredentials
+	}
+	if group.MaxAge > 0 {
+		policy.MaxAge = group.MaxAge
+	}
+
+	return policy
+}
+
+// hasPathPrefix tells if the route is the prefix or one of the paths
+// under it, so /v1 matches /v1/datetime but not /v10.
+func hasPathPrefix(route string, prefix string) bool {
+	return route == prefix || strings.HasPrefix(route, strings.TrimSuffix(prefix, "/")+"/")
+}
+
+func (c CorsConfig) validate() error {
+	if c.Enabled && len(c.AllowedOrigins) == 0 {
+		return fmt.Errorf("cors is enabled but no origins are allowed")
+	}
+
+	if c.MaxAge < 0 {
+		return fmt.Errorf("cors max age cannot be negative")
+	}
+
+	if err := validateCorsPolicy(c.AllowedOrigins, c.AllowCredentials); err != nil {
+		return err
+	}
+
+	for prefix := range c.Groups {
+		if !strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("cors group %q must start with /", prefix)
+		}
+		policy := c.For(prefix)
+		if policy.MaxAge < 0 {
+			return fmt.Errorf("cors max age of %s cannot be negative", prefix)
+		}
+		if err := validateCorsPolicy(policy.AllowedOrigins, policy.AllowCredentials); err != nil {
+			return fmt.Errorf("%s of %s", err.Error(), prefix)
+		}
+	}
+
+	return nil
+}
+
+func validateCorsPolicy(origins []string, allowCredentials bool) error {
+	for _, origin := range origins {
+		if origin == "*" {
+			if allowCredentials {
+				// browsers reject the credentials of any origin
+				return fmt.Errorf("cors cannot allow credentials for any origin")
+			}
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" ||
+			strings.Contains(strings.TrimPrefix(u.Host, "*."), "*") {
+			return fmt.Errorf("invalid cors origin %q, must be like https://example.com or https://*.example.com", origin)
+		}
+	}
+
+	return nil
+}
+
+// Modes of the inputs recorded in the audit log.
+const (
+	AuditKeep   = "keep"
+	AuditHash   = "hash"
+	AuditRedact = "redact"
+)
+
+// AuditConfig holds the settings of the audit log of the tool calls.
+type AuditConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Sink is where the records are written: file, stdout or memory.
+	Sink string `yaml:"sink" toml:"sink"`
+	// File is the JSON lines file of the file sink.
+	File string `yaml:"file" toml:"file"`
+	// BufferSize is the number of records kept by the memory sink.
+	BufferSize int `yaml:"bufferSize" toml:"bufferSize"`
+	// Redaction is the mode of the inputs without rule: hash, redact or
+	// keep.
+	Redaction string `yaml:"redaction" toml:"redaction"`
+	// Routes overrides the modes of the inputs of routes, like
+	// /v1/internet/medium-to-md, of full gRPC methods and of GraphQL
+	// fields, like Query.jwt, by input name.
+	Routes map[string]map[string]string `yaml:"routes" toml:"routes"`
+}
+
+func (c AuditConfig) validate() error {
+	switch c.Sink {
+	case "file":
+		if c.File == "" {
+			return fmt.Errorf("audit file cannot be empty with the file sink")
+		}
+	case "stdout", "memory":
+	default:
+		return fmt.Errorf("invalid audit sink %q, must be file, stdout or memory", c.Sink)
+	}
+
+	if c.BufferSize < 1 {
+		return fmt.Errorf("audit buffer size must be at least 1")
+	}
+
+	if !isAuditMode(c.Redaction) {
+		return fmt.Errorf("invalid audit redaction %q, must be hash, redact or keep", c.Redaction)
+	}
+
+	for route, inputs := range c.Routes {
+		if !strings.HasPrefix(route, "/") && !strings.HasPrefix(route, "Query.") && !strings.HasPrefix(route, "Mutation.") {
+			return fmt.Errorf("audit route %q must start with / or be a GraphQL field like Query.jwt", route)
+		}
+		for input, mode := range inputs {
+			if !isAuditMode(mode) {
+				return fmt.Errorf("invalid audit mode %q for %s of %s, must be hash, redact or keep", mode, input, route)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AdminConfig holds the settings of the admin endpoints, changing the api
+// at runtime.
+type AdminConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Pprof exposes the net/http/pprof profiles under /admin/debug/pprof.
+	Pprof bool `yaml:"pprof" toml:"pprof"`
+	// DisabledRoutes are the routes of the tools answered with 503 when
+	// the api starts, like /v1/internet/medium-to-md.
+	DisabledRoutes []string `yaml:"disabledRoutes" toml:"disabledRoutes"`
+}
+
+func (c AdminConfig) validate() error {
+	for _, route := range c.DisabledRoutes {
+		if !strings.HasPrefix(route, "/") {
+			return fmt.Errorf("disabled route %q must start with /", route)
+		}
+	}
+
+	return nil
+}
+
+// VersioningConfig holds the settings of the versions of the api.
+type VersioningConfig struct {
+	// Deprecations are the deprecated routes, by path prefix, like /v1 or
+	// /v1/datetime/fromunix. The longest prefix of a route applies.
+	Deprecations map[string]DeprecationConfig `yaml:"deprecations" toml:"deprecations"`
+}
+
+// DeprecationConfig describes the deprecation of routes, announced in the
+// Deprecation, Sunset and Link headers of their responses.
+type DeprecationConfig struct {
+	// Since is when the routes were deprecated.
+	Since time.Time `yaml:"since" toml:"since"`
+	// Sunset is when the routes will be removed, if planned.
+	Sunset time.Time `yaml:"sunset,omitempty" toml:"sunset,omitempty"`
+	// Link is the address documenting the deprecation.
+	Link string `yaml:"link,omitempty" toml:"link,omitempty"`
+	// Successor is the path or the address of the route replacing them,
+	// like /v2/datetime/fromunix.
+	Successor string `yaml:"successor,omitempty" toml:"successor,omitempty"`
+}
+
+// For returns the deprecation of the route, given by its longest matching
+// prefix, if it is deprecated.
+func (c VersioningConfig) For(route string) (DeprecationConfig, bool) {
+	prefix := ""
+	for deprecatedPrefix := range c.Deprecations {
+		if hasPathPrefix(route, deprecatedPrefix) && len(deprecatedPrefix) > len(prefix) {
+			prefix = deprecatedPrefix
+		}
+	}
+	if prefix == "" {
+		return DeprecationConfig{}, false
+	}
+
+	return c.Deprecations[prefix], true
+}
+
+func (c VersioningConfig) validate() error {
+	for prefix, deprecation := range c.Deprecations {
+		if !strings.HasPrefix(prefix, "/") {
+			return fmt.Errorf("deprecated routes %q must start with /", prefix)
+		}
+		if deprecation.Since.IsZero() {
+			return fmt.Errorf("deprecation of %s must have a since date", prefix)
+		}
+		if !deprecation.Sunset.IsZero() && !deprecation.Sunset.After(deprecation.Since) {
+			return fmt.Errorf("sunset of %s must be after its deprecation", prefix)
+		}
+		if deprecation.Link != "" && !isHttpUrl(deprecation.Link) {
+			return fmt.Errorf("deprecation link of %s must be an http or https address", prefix)
+		}
+		if deprecation.Successor != "" && !strings.HasPrefix(deprecation.Successor, "/") && !isHttpUrl(deprecation.Successor) {
+			return fmt.Errorf("successor of %s must be a path or an http or https address", prefix)
+		}
+	}
+
+	return nil
+}
+
+func isHttpUrl(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+func isAuditMode(mode string) bool {
+	return mode == AuditKeep || mode == AuditHash || mode == AuditRedact
+}
+
+// HealthConfig holds the settings of the health endpoints.
+type HealthConfig struct {
+	// CheckTimeout is how long each health check can run.
+	CheckTimeout time.Duration `yaml:"checkTimeout" toml:"checkTimeout"`
+	// CacheTtl is how long the result of a check is reused by the probes.
+	CacheTtl time.Duration `yaml:"cacheTtl" toml:"cacheTtl"`
+}
+
+// MetricsConfig holds the settings of the Prometheus metrics.
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled" toml:"enabled"`
+	Path    string `yaml:"path" toml:"path"`
+}
+
+// TracingConfig holds the settings of the OpenTelemetry tracing.
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled" toml:"enabled"`
+	// Exporter is one of otlp, stdout or none.
+	Exporter string `yaml:"exporter" toml:"exporter"`
+	// Protocol is the OTLP protocol, grpc or http.
+	Protocol string `yaml:"protocol" toml:"protocol"`
+	// Endpoint is the host:port of the OTLP collector.
+	Endpoint    string  `yaml:"endpoint" toml:"endpoint"`
+	Insecure    bool    `yaml:"insecure" toml:"insecure"`
+	SampleRatio float64 `yaml:"sampleRatio" toml:"sampleRatio"`
+	ServiceName string  `yaml:"serviceName" toml:"serviceName"`
+}
+
+// GroupConfig holds the settings of a service group.
+type GroupConfig struct {
+	// Enabled is nil when not set, meaning the group is enabled.
+	Enabled *bool             `yaml:"enabled,omitempty" toml:"enabled,omitempty"`
+	Options map[string]string `yaml:"options,omitempty" toml:"options,omitempty"`
+}
+
+// IsEnabled returns true unless the group was explicitly disabled.
+func (g GroupConfig) IsEnabled() bool {
+	return g.Enabled == nil || *g.Enabled
+}
+
+// Default returns the configuration used when nothing else is set.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Address:              ":8080",
+			Mode:                 gin.DebugMode,
+			RequestIdHeader:      "X-Request-ID",
+			AssetsUrl:            "/assets",
+			ReadTimeout:          30 * time.Second,
+			ReadHeaderTimeout:    10 * time.Second,
+			WriteTimeout:         30 * time.Second,
+			IdleTimeout:          120 * time.Second,
+			ShutdownTimeout:      20 * time.Second,
+			ShutdownHooksTimeout: 5 * time.Second,
+			Tls: TlsConfig{
+				ClientAuth:     "require",
+				ReloadInterval: 10 * time.Second,
+			},
+		},
+		Grpc: GrpcConfig{
+			Enabled:    false,
+			Address:    ":9090",
+			Reflection: true,
+		},
+		Logging: LoggingConfig{
+			Format:  "json",
+			Level:   "info",
+			Outputs: []string{"stderr"},
+			Sampling: SamplingConfig{
+				Enabled:    false,
+				Initial:    100,
+				Thereafter: 100,
+			},
+			AccessLog: true,
+		},
+		Auth: AuthConfig{
+			Jwt: JwtConfig{
+				JwksRefreshInterval: 10 * time.Minute,
+				ClockSkew:           time.Minute,
+				ScopesClaim:         "scope",
+			},
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: false,
+			Rate:    10,
+			Burst:   20,
+		},
+		Limits: LimitsConfig{
+			MaxBodySize: 1 << 20,
+			Timeout:     15 * time.Second,
+		},
+		Batch: BatchConfig{
+			Enabled:     true,
+			MaxSize:     20,
+			Parallelism: 4,
+		},
+		Graphql: GraphqlConfig{
+			Enabled:       true,
+			MaxDepth:      8,
+			MaxComplexity: 50,
+			Playground:    true,
+		},
+		Cache: CacheConfig{
+			Enabled: false,
+			Size:    1000,
+		},
+		Cors: CorsConfig{
+			Enabled:        false,
+			AllowedOrigins: []string{},
+			AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodHead},
+			AllowedHeaders: []string{"Accept", "Authorization", "Content-Type", "X-API-Key", "X-Request-ID"},
+			ExposedHeaders: []string{"ETag", "Retry-After", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "X-Request-ID", "Deprecation", "Sunset", "Link"},
+			MaxAge:         10 * time.Minute,
+		},
+		Audit: AuditConfig{
+			Enabled:    false,
+			Sink:       "stdout",
+			BufferSize: 1000,
+			Redaction:  AuditHash,
+		},
+		Admin: AdminConfig{
+			Enabled:        false,
+			Pprof:          false,
+			DisabledRoutes: []string{},
+		},
+		Health: HealthConfig{
+			CheckTimeout: 2 * time.Second,
+			CacheTtl:     5 * time.Second,
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+			Path:    "/metrics",
+		},
+		Tracing: TracingConfig{
+			Enabled:     false,
+			Exporter:    "otlp",
+			Protocol:    "grpc",
+			Endpoint:    "localhost:4317",
+			Insecure:    true,
+			SampleRatio: 1,
+			ServiceName: "canivete-api",
+		},
+		Groups: map[string]GroupConfig{},
+	}
+}
+
+// GroupEnabled returns true if the named service group must be mounted.
+func (c Config) GroupEnabled(name string) bool {
+	return c.Groups[name].IsEnabled()
+}
+
+// GroupOption returns the value of a group option or def if it is not set.
+func (c Config) GroupOption(name, key, def string) string {
+	value, ok := c.Groups[name].Options[key]
+	if !ok {
+		return def
+	}
+
+	return value
+}
+
+// Validate checks the configuration is usable.
+func (c Config) Validate() error {
+	switch c.Server.Mode {
+	case gin.DebugMode, gin.ReleaseMode, gin.TestMode:
+	default:
+		return fmt.Errorf("invalid server mode %q, must be one of debug, release or test", c.Server.Mode)
+	}
+
+	if c.Server.Address == "" {
+		return fmt.Errorf("server address cannot be empty")
+	}
+
+	if c.Grpc.Enabled && c.Grpc.Address == "" {
+		return fmt.Errorf("grpc address cannot be empty")
+	}
+
+	if c.Server.RequestIdHeader == "" {
+		return fmt.Errorf("request id header cannot be empty")
+	}
+
+	if !strings.HasPrefix(c.Server.AssetsUrl, "https://") && !strings.HasPrefix(c.Server.AssetsUrl, "http://") && !strings.HasPrefix(c.Server.AssetsUrl, "/") {
+		return fmt.Errorf("assets url must be an http(s) url or an absolute path")
+	}
+
+	if c.Server.ReadTimeout < 0 || c.Server.ReadHeaderTimeout < 0 ||
+		c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		return fmt.Errorf("server timeouts cannot be negative")
+	}
+
+	if c.Server.ShutdownTimeout <= 0 {
+		return fmt.Errorf("server shutdown timeout must be positive")
+	}
+
+	if c.Server.ShutdownHooksTimeout <= 0 {
+		return fmt.Errorf("server shutdown hooks timeout must be positive")
+	}
+
+	if err := c.Server.Tls.validate(); err != nil {
+		return err
+	}
+
+	switch c.Logging.Format {
+	case "json", "console":
+	default:
+		return fmt.Errorf("invalid logging format %q, must be json or console", c.Logging.Format)
+	}
+
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(c.Logging.Level)); err != nil {
+		return fmt.Errorf("invalid logging level %q, must be one of debug, info, warn or error", c.Logging.Level)
+	}
+
+	if len(c.Logging.Outputs) == 0 {
+		return fmt.Errorf("logging outputs cannot be empty")
+	}
+
+	if c.Logging.Sampling.Enabled && (c.Logging.Sampling.Initial <= 0 || c.Logging.Sampling.Thereafter <= 0) {
+		return fmt.Errorf("logging sampling initial and thereafter must be positive")
+	}
+
+	if c.Auth.Enabled && c.Auth.ApiKeysFile == "" && len(c.Auth.ApiKeys) == 0 && !c.Auth.Jwt.Enabled {
+		return fmt.Errorf("auth is enabled but neither api keys nor jwt are configured")
+	}
+
+	if c.Auth.Jwt.Enabled {
+		if err := c.Auth.Jwt.validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range c.Auth.ApiKeys {
+		if err := key.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if c.RateLimit.Rate <= 0 {
+		return fmt.Errorf("rate limit rate must be positive")
+	}
+
+	if c.RateLimit.Burst < 1 {
+		return fmt.Errorf("rate limit burst must be at least 1")
+	}
+
+	for route, cost := range c.RateLimit.Costs {
+		if !strings.HasPrefix(route, "/") {
+			return fmt.Errorf("rate limit cost route %q must start with /", route)
+		}
+		if cost < 0 {
+			return fmt.Errorf("rate limit cost of %s cannot be negative", route)
+		}
+	}
+
+	if c.Limits.MaxBodySize <= 0 {
+		return fmt.Errorf("max body size must be positive")
+	}
+
+	if c.Limits.Timeout <= 0 {
+		return fmt.Errorf("request timeout must be positive")
+	}
+
+	for route, routeLimits := range c.Limits.Routes {
+		if !strings.HasPrefix(route, "/") {
+			return fmt.Errorf("limits route %q must start with /", route)
+		}
+		if routeLimits.MaxBodySize < 0 || routeLimits.Timeout < 0 {
+			return fmt.Errorf("limits of %s cannot be negative", route)
+		}
+	}
+
+	if c.Batch.MaxSize < 1 {
+		return fmt.Errorf("batch max size must be at least 1")
+	}
+
+	if c.Batch.Parallelism < 1 {
+		return fmt.Errorf("batch parallelism must be at least 1")
+	}
+
+	if c.Graphql.MaxDepth < 1 {
+		return fmt.Errorf("graphql max depth must be at least 1")
+	}
+
+	if c.Graphql.MaxComplexity < 1 {
+		return fmt.Errorf("graphql max complexity must be at least 1")
+	}
+
+	if c.Cache.Size < 1 {
+		return fmt.Errorf("cache size must be at least 1")
+	}
+
+	for route := range c.Cache.CacheControl {
+		if !strings.HasPrefix(route, "/") {
+			return fmt.Errorf("cache control route %q must start with /", route)
+		}
+	}
+
+	if err := c.Cors.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Audit.validate(); err != nil {
+		return err
+	}
+
+	if err := c.Admin.validate(); err != nil {
+		return err
+	}
+
+	if c.Admin.Enabled && !c.Auth.Enabled {
+		return fmt.Errorf("admin endpoints cannot be enabled without auth")
+	}
+
+	if err := c.Versioning.validate(); err != nil {
+		return err
+	}
+
+	if c.Health.CheckTimeout <= 0 {
+		return fmt.Errorf("health check timeout must be positive")
+	}
+
+	if c.Health.CacheTtl < 0 {
+		return fmt.Errorf("health cache ttl must not be negative")
+	}
+
+	if c.Metrics.Enabled && !strings.HasPrefix(c.Metrics.Path, "/") {
+		return fmt.Errorf("metrics path must start with /")
+	}
+
+	switch c.Tracing.Exporter {
+	case "otlp", "stdout", "none":
+	default:
+		return fmt.Errorf("invalid tracing exporter %q, must be one of otlp, stdout or none", c.Tracing.Exporter)
+	}
+
+	switch c.Tracing.Protocol {
+	case "grpc", "http":
+	default:
+		return fmt.Errorf("invalid tracing protocol %q, must be grpc or http", c.Tracing.Protocol)
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("tracing sample ratio must be between 0 and 1")
+	}
+
+	for name := range c.Groups {
+		if !isKnownGroup(name) {
+			return fmt.Errorf("unknown service group %q", name)
+		}
+	}
+
+	return nil
+}
+
+// Print writes the configuration as YAML, listing every service group.
+func (c Config) Print(w io.Writer) error {
+	groups := map[string]GroupConfig{}
+	for _, name := range Groups {
+		group := c.Groups[name]
+		enabled := group.IsEnabled()
+		group.Enabled = &enabled
+		groups[name] = group
+	}
+	c.Groups = groups
+
+	out, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(out)
+	return err
+}
+
+func isKnownScope(scope string) bool {
+	return scope == ScopeAll || scope == ScopeAdmin || isKnownGroup(scope)
+}
+
+func isKnownGroup(name string) bool {
+	for _, group := range Groups {
+		if group == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Config) group(name string) GroupConfig {
+	if c.Groups == nil {
+		c.Groups = map[string]GroupConfig{}
+	}
+
+	return c.Groups[name]
+}
+
+func (c *Config) setGroupEnabled(name string, enabled bool) {
+	group := c.group(name)
+	group.Enabled = &enabled
+	c.Groups[name] = group
+}
+
+func (c *Config) setRateLimitCost(option string) error {
+	route, value, err := splitKeyValue(option)
+	if err != nil {
+		return err
+	}
+
+	cost, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid cost for %s: %s", route, err.Error())
+	}
+
+	costs := map[string]int{}
+	for k, v := range c.RateLimit.Costs {
+		costs[k] = v
+	}
+	costs[route] = cost
+	c.RateLimit.Costs = costs
+
+	return nil
+}
+
+// setAuditRule reads the mode of an input of a route, written as
+// /route=input:mode.
+func (c *Config) setAuditRule(option string) error {
+	route, value, err := splitKeyValue(option)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.SplitN(value, ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return fmt.Errorf("%q must be /route=input:mode", option)
+	}
+
+	routes := map[string]map[string]string{}
+	for k, v := range c.Audit.Routes {
+		routes[k] = v
+	}
+	inputs := map[string]string{}
+	for k, v := range routes[route] {
+		inputs[k] = v
+	}
+	inputs[parts[0]] = parts[1]
+	routes[route] = inputs
+	c.Audit.Routes = routes
+
+	return nil
+}
+
+func (c *Config) setGroupOption(name, key, value string) {
+	group := c.group(name)
+	options := map[string]string{}
+	for k, v := range group.Options {
+		options[k] = v
+	}
+	options[key] = value
+	group.Options = options
+	c.Groups[name] = group
+}
+
+// setCacheControl sets the Cache-Control of a route from an option like
+// /v1/datetime/fromunix=public, max-age=60.
+func (c *Config) setCacheControl(option string) error {
+	route, value, err := splitKeyValue(option)
+	if err != nil {
+		return err
+	}
+
+	cacheControl := map[string]string{}
+	for k, v := range c.Cache.CacheControl {
+		cacheControl[k] = v
+	}
+	cacheControl[route] = value
+	c.Cache.CacheControl = cacheControl
+
+	return nil
+}
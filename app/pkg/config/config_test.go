@@ -0,0 +1,719 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package config
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := ioutil.WriteFile(path, []byte(content), 0600)
+	assert.Nil(t, err)
+
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	// act
+	cfg, err := Load([]string{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, ":8080", cfg.Server.Address)
+	assert.Equal(t, "debug", cfg.Server.Mode)
+	assert.Empty(t, cfg.Server.TrustedProxies)
+	for _, name := range Groups {
+		assert.True(t, cfg.GroupEnabled(name))
+	}
+}
+
+func TestLoadYamlFile(t *testing.T) {
+	// arrange
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  address: ":9090"
+  mode: release
+  trustedProxies: ["10.0.0.0/8"]
+groups:
+  internet:
+    enabled: false
+    options:
+      key: value
+`)
+
+	// act
+	cfg, err := Load([]string{"--config", path})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, ":9090", cfg.Server.Address)
+	assert.Equal(t, "release", cfg.Server.Mode)
+	assert.Equal(t, []string{"10.0.0.0/8"}, cfg.Server.TrustedProxies)
+	assert.False(t, cfg.GroupEnabled(GroupInternet))
+	assert.True(t, cfg.GroupEnabled(GroupFinance))
+	assert.Equal(t, "value", cfg.GroupOption(GroupInternet, "key", ""))
+}
+
+func TestLoadTomlFile(t *testing.T) {
+	// arrange
+	path := writeConfigFile(t, "config.toml", `
+[server]
+address = ":9091"
+
+[groups.finance]
+enabled = false
+`)
+
+	// act
+	cfg, err := Load([]string{"--config", path})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, ":9091", cfg.Server.Address)
+	assert.False(t, cfg.GroupEnabled(GroupFinance))
+	assert.True(t, cfg.GroupEnabled(GroupInternet))
+}
+
+func TestLoadFileWithUnknownKey(t *testing.T) {
+	// arrange
+	path := writeConfigFile(t, "config.yaml", "server:\n  port: 80\n")
+
+	// act
+	_, err := Load([]string{"--config", path})
+
+	// assert
+	assert.NotNil(t, err)
+}
+
+func TestLoadFileWithUnsupportedFormat(t *testing.T) {
+	// arrange
+	path := writeConfigFile(t, "config.ini", "")
+
+	// act
+	_, err := Load([]string{"--config", path})
+
+	// assert
+	assert.NotNil(t, err)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	// arrange
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  address: ":9090"
+  mode: release
+`)
+	t.Setenv("CANIVETE_CONFIG", path)
+	t.Setenv("CANIVETE_ADDRESS", ":7070")
+	t.Setenv("CANIVETE_MODE", "test")
+	t.Setenv("CANIVETE_DATETIME_ENABLED", "false")
+	t.Setenv("CANIVETE_PROGRAMMING_OPTIONS", "a=1,b=2")
+
+	// act
+	cfg, err := Load([]string{"--mode", "debug", "--group-option", "programming.b=3"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, path, cfg.ConfigFile)
+	assert.Equal(t, ":7070", cfg.Server.Address)
+	assert.Equal(t, "debug", cfg.Server.Mode)
+	assert.False(t, cfg.GroupEnabled(GroupDatetime))
+	assert.Equal(t, "1", cfg.GroupOption(GroupProgramming, "a", ""))
+	assert.Equal(t, "3", cfg.GroupOption(GroupProgramming, "b", ""))
+}
+
+func TestLoadLegacyEnv(t *testing.T) {
+	// arrange
+	t.Setenv("PORT", "3000")
+	t.Setenv("GIN_MODE", "release")
+
+	// act
+	cfg, err := Load([]string{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, ":3000", cfg.Server.Address)
+	assert.Equal(t, "release", cfg.Server.Mode)
+}
+
+func TestLoadFlags(t *testing.T) {
+	// act
+	cfg, err := Load([]string{
+		"--address", "127.0.0.1:8081",
+		"--trusted-proxies", "10.0.0.1, 10.0.0.2",
+		"--disable-group", "internet",
+		"--disable-group", "finance",
+		"--print-config",
+	})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "127.0.0.1:8081", cfg.Server.Address)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, cfg.Server.TrustedProxies)
+	assert.False(t, cfg.GroupEnabled(GroupInternet))
+	assert.False(t, cfg.GroupEnabled(GroupFinance))
+	assert.True(t, cfg.PrintConfig)
+}
+
+func TestLoadTimeouts(t *testing.T) {
+	// arrange
+	path := writeConfigFile(t, "config.toml", `
+[server]
+readTimeout = "5s"
+idleTimeout = "1m"
+`)
+	t.Setenv("CANIVETE_WRITE_TIMEOUT", "15s")
+
+	// act
+	cfg, err := Load([]string{"--config", path, "--shutdown-timeout", "45s", "--shutdown-hooks-timeout", "10s"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, 5*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, time.Minute, cfg.Server.IdleTimeout)
+	assert.Equal(t, 15*time.Second, cfg.Server.WriteTimeout)
+	assert.Equal(t, 45*time.Second, cfg.Server.ShutdownTimeout)
+	assert.Equal(t, 10*time.Second, cfg.Server.ShutdownHooksTimeout)
+	assert.Equal(t, 10*time.Second, cfg.Server.ReadHeaderTimeout)
+}
+
+func TestLoadTracing(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_TRACING_ENABLED", "true")
+	t.Setenv("CANIVETE_TRACING_SAMPLE_RATIO", "0.5")
+	t.Setenv("CANIVETE_TRACING_ENDPOINT", "collector:4318")
+
+	// act
+	cfg, err := Load([]string{"--tracing-protocol", "http"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, cfg.Tracing.Enabled)
+	assert.Equal(t, 0.5, cfg.Tracing.SampleRatio)
+	assert.Equal(t, "collector:4318", cfg.Tracing.Endpoint)
+	assert.Equal(t, "http", cfg.Tracing.Protocol)
+}
+
+func TestLoadLogging(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_LOG_LEVEL", "debug")
+	t.Setenv("CANIVETE_LOG_OUTPUTS", "stdout, /tmp/canivete.log")
+	t.Setenv("CANIVETE_LOG_SAMPLING_INITIAL", "10")
+
+	// act
+	cfg, err := Load([]string{"--log-format", "console", "--log-sampling", "--access-log=false"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "console", cfg.Logging.Format)
+	assert.Equal(t, "debug", cfg.Logging.Level)
+	assert.Equal(t, []string{"stdout", "/tmp/canivete.log"}, cfg.Logging.Outputs)
+	assert.True(t, cfg.Logging.Sampling.Enabled)
+	assert.Equal(t, 10, cfg.Logging.Sampling.Initial)
+	assert.Equal(t, 100, cfg.Logging.Sampling.Thereafter)
+	assert.False(t, cfg.Logging.AccessLog)
+}
+
+func TestLoadAuth(t *testing.T) {
+	// arrange
+	hash := strings.Repeat("ab", 32)
+	t.Setenv("CANIVETE_AUTH_ENABLED", "true")
+	t.Setenv("CANIVETE_AUTH_API_KEYS", "ci:"+hash+":programming|datetime, admin:"+hash+":*")
+
+	// act
+	cfg, err := Load([]string{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, cfg.Auth.Enabled)
+	assert.Equal(t, []ApiKeyConfig{
+		{Name: "ci", Hash: hash, Scopes: []string{"programming", "datetime"}},
+		{Name: "admin", Hash: hash, Scopes: []string{"*"}},
+	}, cfg.Auth.ApiKeys)
+}
+
+func TestLoadJwtAuth(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_AUTH_JWT_ENABLED", "true")
+	t.Setenv("CANIVETE_AUTH_JWT_CLOCK_SKEW", "30s")
+	t.Setenv("CANIVETE_AUTH_JWT_ISSUER", "https://sso.example.com")
+
+	// act
+	cfg, err := Load([]string{"--auth", "--auth-jwt-jwks-url", "https://sso.example.com/jwks", "--auth-jwt-audience", "canivete-api"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, cfg.Auth.Jwt.Enabled)
+	assert.Equal(t, "https://sso.example.com/jwks", cfg.Auth.Jwt.JwksUrl)
+	assert.Equal(t, "https://sso.example.com", cfg.Auth.Jwt.Issuer)
+	assert.Equal(t, "canivete-api", cfg.Auth.Jwt.Audience)
+	assert.Equal(t, 30*time.Second, cfg.Auth.Jwt.ClockSkew)
+	assert.Equal(t, 10*time.Minute, cfg.Auth.Jwt.JwksRefreshInterval)
+	assert.Equal(t, "scope", cfg.Auth.Jwt.ScopesClaim)
+}
+
+func TestLoadRateLimit(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_RATE_LIMIT_ENABLED", "true")
+	t.Setenv("CANIVETE_RATE_LIMIT_RATE", "0.5")
+	t.Setenv("CANIVETE_RATE_LIMIT_COSTS", "/v1/programming/uuid=2")
+
+	// act
+	cfg, err := Load([]string{"--rate-limit-burst", "5", "--rate-limit-cost", "/v1/internet/medium-to-md=4"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, cfg.RateLimit.Enabled)
+	assert.Equal(t, 0.5, cfg.RateLimit.Rate)
+	assert.Equal(t, 5, cfg.RateLimit.Burst)
+	assert.Equal(t, map[string]int{
+		"/v1/programming/uuid":      2,
+		"/v1/internet/medium-to-md": 4,
+	}, cfg.RateLimit.Costs)
+}
+
+func TestLoadLimits(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_MAX_BODY_SIZE", "2048")
+	t.Setenv("CANIVETE_REQUEST_TIMEOUT", "5s")
+	path := writeConfigFile(t, "config.yaml", `
+limits:
+  routes:
+    /v1/internet/medium-to-md:
+      timeout: 30s
+`)
+
+	// act
+	cfg, err := Load([]string{"--config", path, "--max-body-size", "4096"})
+
+	// assert
+	assert.Nil(t, err)
+	maxBodySize, timeout := cfg.Limits.For("/v1/programming/uuid")
+	assert.Equal(t, int64(4096), maxBodySize)
+	assert.Equal(t, 5*time.Second, timeout)
+	maxBodySize, timeout = cfg.Limits.For("/v1/internet/medium-to-md")
+	assert.Equal(t, int64(4096), maxBodySize)
+	assert.Equal(t, 30*time.Second, timeout)
+}
+
+func TestLoadBatch(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_BATCH_ENABLED", "false")
+	t.Setenv("CANIVETE_BATCH_MAX_SIZE", "50")
+
+	// act
+	cfg, err := Load([]string{"--batch-parallelism", "8"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, BatchConfig{Enabled: false, MaxSize: 50, Parallelism: 8}, cfg.Batch)
+}
+
+func TestLoadCache(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_CACHE_ENABLED", "true")
+
+	// act
+	cfg, err := Load([]string{"--cache-size", "10", "--cache-control", "/v1/datetime/fromunix=public, max-age=60"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, cfg.Cache.Enabled)
+	assert.Equal(t, 10, cfg.Cache.Size)
+	assert.Equal(t, map[string]string{"/v1/datetime/fromunix": "public, max-age=60"}, cfg.Cache.CacheControl)
+}
+
+func TestLoadGrpc(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_GRPC_ENABLED", "true")
+	t.Setenv("CANIVETE_GRPC_REFLECTION", "false")
+
+	// act
+	cfg, err := Load([]string{"--grpc-address", ":50051"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, cfg.Grpc.Enabled)
+	assert.Equal(t, ":50051", cfg.Grpc.Address)
+	assert.False(t, cfg.Grpc.Reflection)
+}
+
+func TestLoadTls(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_TLS_CERT_FILE", "/etc/canivete/tls.crt")
+	t.Setenv("CANIVETE_TLS_KEY_FILE", "/etc/canivete/tls.key")
+	t.Setenv("CANIVETE_TLS_RELOAD_INTERVAL", "1m")
+
+	// act
+	cfg, err := Load([]string{"--tls-client-ca-file", "/etc/canivete/ca.crt", "--tls-client-auth", "optional"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, cfg.Server.Tls.Enabled())
+	assert.Equal(t, "/etc/canivete/tls.crt", cfg.Server.Tls.CertFile)
+	assert.Equal(t, "/etc/canivete/tls.key", cfg.Server.Tls.KeyFile)
+	assert.Equal(t, "/etc/canivete/ca.crt", cfg.Server.Tls.ClientCaFile)
+	assert.Equal(t, "optional", cfg.Server.Tls.ClientAuth)
+	assert.Equal(t, time.Minute, cfg.Server.Tls.ReloadInterval)
+}
+
+func TestLoadCors(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_CORS_ENABLED", "true")
+	t.Setenv("CANIVETE_CORS_ALLOWED_ORIGINS", "https://portal.example.com,https://*.example.org")
+	t.Setenv("CANIVETE_CORS_MAX_AGE", "1h")
+
+	// act
+	cfg, err := Load([]string{"--cors-allowed-methods", "GET,POST", "--cors-allow-credentials"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, cfg.Cors.Enabled)
+	assert.Equal(t, []string{"https://portal.example.com", "https://*.example.org"}, cfg.Cors.AllowedOrigins)
+	assert.Equal(t, []string{"GET", "POST"}, cfg.Cors.AllowedMethods)
+	assert.True(t, cfg.Cors.AllowCredentials)
+	assert.Equal(t, time.Hour, cfg.Cors.MaxAge)
+}
+
+func TestLoadCorsGroupsFile(t *testing.T) {
+	tests := []struct {
+		group string
+		valid bool
+	}{
+		{"/v1/finance", true},
+		{"v1/finance", false},
+	}
+
+	for _, test := range tests {
+		// arrange
+		path := writeConfigFile(t, "config.yaml", `
+cors:
+  enabled: true
+  allowedOrigins: ["https://portal.example.com"]
+  groups:
+    `+test.group+`:
+      allowedOrigins: ["https://finance.example.com"]
+      allowCredentials: true
+`)
+
+		// act
+		cfg, err := Load([]string{"--config", path})
+
+		// assert
+		if !test.valid {
+			assert.NotNil(t, err, test.group)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"https://finance.example.com"}, cfg.Cors.Groups[test.group].AllowedOrigins)
+		assert.True(t, *cfg.Cors.Groups[test.group].AllowCredentials)
+	}
+}
+
+func TestCorsFor(t *testing.T) {
+	// arrange
+	allowCredentials := true
+	cfg := Default().Cors
+	cfg.AllowedOrigins = []string{"https://portal.example.com"}
+	cfg.Groups = map[string]CorsGroupConfig{
+		"/v1":         {MaxAge: time.Hour},
+		"/v1/finance": {AllowedOrigins: []string{"https://finance.example.com"}, AllowCredentials: &allowCredentials},
+	}
+
+	// act
+	finance := cfg.For("/v1/finance/calculate-compound-interests")
+	programming := cfg.For("/v1/programming/uuid")
+	root := cfg.For("/")
+	v10 := cfg.For("/v10/finance/calculate-compound-interests")
+	financeV2 := cfg.For("/v1/finance-v2/calculate-compound-interests")
+
+	// assert
+	assert.Equal(t, []string{"https://finance.example.com"}, finance.AllowedOrigins)
+	assert.True(t, finance.AllowCredentials)
+	assert.Equal(t, cfg.MaxAge, finance.MaxAge)
+	assert.Equal(t, cfg.AllowedOrigins, programming.AllowedOrigins)
+	assert.Equal(t, time.Hour, programming.MaxAge)
+	assert.Equal(t, cfg.MaxAge, root.MaxAge)
+	assert.Equal(t, cfg.MaxAge, v10.MaxAge)
+	assert.Equal(t, cfg.AllowedOrigins, financeV2.AllowedOrigins)
+	assert.Equal(t, time.Hour, financeV2.MaxAge)
+}
+
+func TestLoadAudit(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_AUDIT_ENABLED", "true")
+	t.Setenv("CANIVETE_AUDIT_SINK", "memory")
+	t.Setenv("CANIVETE_AUDIT_RULES", "/v1/internet/medium-to-md=body:keep")
+
+	// act
+	cfg, err := Load([]string{"--audit-buffer-size", "50", "--audit-redaction", "redact", "--audit-rule", "Query.jwt=token:hash"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, cfg.Audit.Enabled)
+	assert.Equal(t, "memory", cfg.Audit.Sink)
+	assert.Equal(t, 50, cfg.Audit.BufferSize)
+	assert.Equal(t, AuditRedact, cfg.Audit.Redaction)
+	assert.Equal(t, map[string]map[string]string{
+		"/v1/internet/medium-to-md": {"body": AuditKeep},
+		"Query.jwt":                 {"token": AuditHash},
+	}, cfg.Audit.Routes)
+}
+
+func TestLoadAuditFile(t *testing.T) {
+	// arrange
+	path := writeConfigFile(t, "config.yaml", `
+audit:
+  enabled: true
+  sink: file
+  file: /var/log/canivete/audit.log
+  routes:
+    /v1/internet/medium-to-md:
+      body: keep
+`)
+
+	// act
+	cfg, err := Load([]string{"--config", path})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "file", cfg.Audit.Sink)
+	assert.Equal(t, "/var/log/canivete/audit.log", cfg.Audit.File)
+	assert.Equal(t, AuditHash, cfg.Audit.Redaction)
+	assert.Equal(t, AuditKeep, cfg.Audit.Routes["/v1/internet/medium-to-md"]["body"])
+}
+
+func TestLoadAdmin(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_ADMIN_ENABLED", "true")
+	t.Setenv("CANIVETE_ADMIN_DISABLED_ROUTES", "/v1/internet/medium-to-md,/v1/programming/uuid")
+
+	// act
+	cfg, err := Load([]string{"--admin-pprof", "--auth", "--auth-api-keys-file", "api-keys.yaml"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, cfg.Admin.Enabled)
+	assert.True(t, cfg.Admin.Pprof)
+	assert.Equal(t, []string{"/v1/internet/medium-to-md", "/v1/programming/uuid"}, cfg.Admin.DisabledRoutes)
+}
+
+func TestLoadVersioningFile(t *testing.T) {
+	// arrange
+	path := writeConfigFile(t, "config.yaml", `
+versioning:
+  deprecations:
+    /v1:
+      since: 2026-01-01T00:00:00Z
+      sunset: 2027-01-01T00:00:00Z
+      link: https://docs.example.com/migrate-to-v2
+      successor: /v2
+`)
+
+	// act
+	cfg, err := Load([]string{"--config", path})
+
+	// assert
+	assert.Nil(t, err)
+	deprecation, ok := cfg.Versioning.For("/v1/datetime/fromunix")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), deprecation.Since)
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC), deprecation.Sunset)
+	assert.Equal(t, "https://docs.example.com/migrate-to-v2", deprecation.Link)
+	assert.Equal(t, "/v2", deprecation.Successor)
+	_, ok = cfg.Versioning.For("/v2/datetime/fromunix")
+	assert.False(t, ok)
+	_, ok = cfg.Versioning.For("/v10/datetime/fromunix")
+	assert.False(t, ok)
+	_, ok = cfg.Versioning.For("/v1")
+	assert.True(t, ok)
+}
+
+func TestLoadInvalidVersioningFile(t *testing.T) {
+	tests := []string{
+		"v1: {since: 2026-01-01T00:00:00Z}",
+		"/v1: {link: https://docs.example.com}",
+		"/v1: {since: 2026-01-01T00:00:00Z, sunset: 2025-01-01T00:00:00Z}",
+		"/v1: {since: 2026-01-01T00:00:00Z, link: docs.example.com}",
+		"/v1: {since: 2026-01-01T00:00:00Z, successor: v2}",
+	}
+
+	for _, test := range tests {
+		// arrange
+		path := writeConfigFile(t, "config.yaml", `
+versioning:
+  deprecations:
+    `+test+`
+`)
+
+		// act
+		_, err := Load([]string{"--config", path})
+
+		// assert
+		assert.NotNil(t, err, test)
+	}
+}
+
+func TestLoadGraphql(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_GRAPHQL_PLAYGROUND", "false")
+	t.Setenv("CANIVETE_GRAPHQL_MAX_DEPTH", "4")
+
+	// act
+	cfg, err := Load([]string{"--graphql-max-complexity", "20"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.True(t, cfg.Graphql.Enabled)
+	assert.False(t, cfg.Graphql.Playground)
+	assert.Equal(t, 4, cfg.Graphql.MaxDepth)
+	assert.Equal(t, 20, cfg.Graphql.MaxComplexity)
+}
+
+func TestLoadInvalidAuth(t *testing.T) {
+	tests := []string{
+		"ci",
+		"ci:plain-key:programming",
+		"ci:" + strings.Repeat("ab", 32) + ":weather",
+	}
+
+	for _, keys := range tests {
+		// arrange
+		t.Setenv("CANIVETE_AUTH_API_KEYS", keys)
+
+		// act
+		_, err := Load([]string{})
+
+		// assert
+		assert.NotNil(t, err, "expected error for %s", keys)
+	}
+}
+
+func TestLoadInvalidValues(t *testing.T) {
+	tests := [][]string{
+		{"--mode", "production"},
+		{"--address", ""},
+		{"--disable-group", "unknown"},
+		{"--group-option", "internet"},
+		{"--group-option", "internet=1"},
+		{"--unknown-flag"},
+		{"--shutdown-timeout", "0s"},
+		{"--shutdown-hooks-timeout", "0s"},
+		{"--read-timeout", "-1s"},
+		{"--health-check-timeout", "0s"},
+		{"--health-cache-ttl", "-1s"},
+		{"--metrics-path", "metrics"},
+		{"--tracing-exporter", "zipkin"},
+		{"--tracing-protocol", "udp"},
+		{"--tracing-sample-ratio", "2"},
+		{"--request-id-header", ""},
+		{"--assets-url", "unpkg.com"},
+		{"--auth"},
+		{"--auth-jwt"},
+		{"--rate-limit-rate", "0"},
+		{"--rate-limit-burst", "0"},
+		{"--rate-limit-cost", "/v1/programming/uuid=many"},
+		{"--rate-limit-cost", "v1/programming/uuid=1"},
+		{"--rate-limit-cost", "/v1/programming/uuid=-1"},
+		{"--auth-jwt", "--auth-jwt-jwks-url", "https://sso", "--auth-jwt-jwks-file", "jwks.json"},
+		{"--auth-jwt", "--auth-jwt-jwks-file", "jwks.json", "--auth-jwt-clock-skew", "-1s"},
+		{"--auth-jwt", "--auth-jwt-jwks-file", "jwks.json", "--auth-jwt-jwks-refresh-interval", "0s"},
+		{"--log-format", "text"},
+		{"--log-level", "verbose"},
+		{"--log-outputs", ""},
+		{"--log-sampling", "--log-sampling-initial", "0"},
+		{"--max-body-size", "0"},
+		{"--request-timeout", "0s"},
+		{"--batch-max-size", "0"},
+		{"--batch-parallelism", "0"},
+		{"--cache-size", "0"},
+		{"--cache-control", "v1/datetime/fromunix=no-store"},
+		{"--grpc", "--grpc-address", ""},
+		{"--tls-cert-file", "tls.crt"},
+		{"--tls-key-file", "tls.key"},
+		{"--tls-client-ca-file", "ca.crt"},
+		{"--tls-cert-file", "tls.crt", "--tls-key-file", "tls.key", "--tls-client-auth", "never"},
+		{"--tls-cert-file", "tls.crt", "--tls-key-file", "tls.key", "--tls-reload-interval", "0s"},
+		{"--graphql-max-depth", "0"},
+		{"--cors"},
+		{"--cors", "--cors-allowed-origins", "portal.example.com"},
+		{"--cors", "--cors-allowed-origins", "https://portal.example.com/path"},
+		{"--cors", "--cors-allowed-origins", "https://portal.*.example.com"},
+		{"--cors", "--cors-allowed-origins", "*", "--cors-allow-credentials"},
+		{"--cors", "--cors-allowed-origins", "https://portal.example.com", "--cors-max-age", "-1s"},
+		{"--graphql-max-complexity", "0"},
+		{"--audit-sink", "kafka"},
+		{"--audit-sink", "file"},
+		{"--audit-buffer-size", "0"},
+		{"--audit-redaction", "encrypt"},
+		{"--audit-rule", "/v1/x=body"},
+		{"--audit-rule", "/v1/x=body:encrypt"},
+		{"--audit-rule", "v1/x=body:keep"},
+		{"--admin-disabled-routes", "v1/internet/medium-to-md"},
+		{"--admin"},
+	}
+
+	for _, args := range tests {
+		_, err := Load(args)
+		assert.NotNil(t, err, "expected error for %v", args)
+	}
+}
+
+func TestLoadInvalidDurationEnv(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_IDLE_TIMEOUT", "forever")
+
+	// act
+	_, err := Load([]string{})
+
+	// assert
+	assert.NotNil(t, err)
+}
+
+func TestLoadInvalidGroupEnv(t *testing.T) {
+	// arrange
+	t.Setenv("CANIVETE_INTERNET_ENABLED", "maybe")
+
+	// act
+	_, err := Load([]string{})
+
+	// assert
+	assert.NotNil(t, err)
+}
+
+func TestPrint(t *testing.T) {
+	// arrange
+	cfg := Default()
+	cfg.setGroupEnabled(GroupInternet, false)
+	out := bytes.Buffer{}
+
+	// act
+	err := cfg.Print(&out)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "address: :8080")
+	assert.Contains(t, out.String(), "enabled: false")
+}
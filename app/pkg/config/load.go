@@ -0,0 +1,561 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+// EnvPrefix is the prefix of every environment variable read by Load.
+const EnvPrefix = "CANIVETE_"
+
+// Load builds the effective configuration.
+//
+// Settings are applied in increasing order of precedence:
+//
+// 1. the defaults;
+// 2. the config file (YAML or TOML, chosen by extension);
+// 3. the environment variables;
+// 4. the command-line flags in args.
+func Load(args []string) (Config, error) {
+	cfg := Default()
+
+	// flags are parsed twice: first to find the config file and then to
+	// override what was read from the file and the environment
+	probe := Default()
+	err := newFlagSet(&probe).Parse(args)
+	if err != nil {
+		return cfg, err
+	}
+
+	cfg.ConfigFile = os.Getenv(EnvPrefix + "CONFIG")
+	if probe.ConfigFile != "" {
+		cfg.ConfigFile = probe.ConfigFile
+	}
+
+	if cfg.ConfigFile != "" {
+		err = loadFile(cfg.ConfigFile, &cfg)
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	err = loadEnv(&cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	err = newFlagSet(&cfg).Parse(args)
+	if err != nil {
+		return cfg, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %s", err.Error())
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, cfg)
+	case ".toml":
+		var meta toml.MetaData
+		meta, err = toml.Decode(string(data), cfg)
+		if err == nil && len(meta.Undecoded()) > 0 {
+			err = fmt.Errorf("unknown keys %v", meta.Undecoded())
+		}
+	default:
+		return fmt.Errorf("unsupported config file format %q, use .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("error parsing config file %s: %s", path, err.Error())
+	}
+
+	return nil
+}
+
+// loadEnv applies the environment variables to cfg.
+//
+// PORT and GIN_MODE are still honoured, as gin reads them on its own,
+// but the CANIVETE_ variables take precedence.
+func loadEnv(cfg *Config) error {
+	if port, ok := os.LookupEnv("PORT"); ok {
+		cfg.Server.Address = ":" + port
+	}
+	if address, ok := os.LookupEnv(EnvPrefix + "ADDRESS"); ok {
+		cfg.Server.Address = address
+	}
+
+	if mode, ok := os.LookupEnv("GIN_MODE"); ok {
+		cfg.Server.Mode = mode
+	}
+	if mode, ok := os.LookupEnv(EnvPrefix + "MODE"); ok {
+		cfg.Server.Mode = mode
+	}
+
+	if proxies, ok := os.LookupEnv(EnvPrefix + "TRUSTED_PROXIES"); ok {
+		cfg.Server.TrustedProxies = splitList(proxies)
+	}
+
+	durations := map[string]*time.Duration{
+		"READ_TIMEOUT":                   &cfg.Server.ReadTimeout,
+		"READ_HEADER_TIMEOUT":            &cfg.Server.ReadHeaderTimeout,
+		"WRITE_TIMEOUT":                  &cfg.Server.WriteTimeout,
+		"IDLE_TIMEOUT":                   &cfg.Server.IdleTimeout,
+		"SHUTDOWN_TIMEOUT":               &cfg.Server.ShutdownTimeout,
+		"SHUTDOWN_HOOKS_TIMEOUT":         &cfg.Server.ShutdownHooksTimeout,
+		"TLS_RELOAD_INTERVAL":            &cfg.Server.Tls.ReloadInterval,
+		"HEALTH_CHECK_TIMEOUT":           &cfg.Health.CheckTimeout,
+		"HEALTH_CACHE_TTL":               &cfg.Health.CacheTtl,
+		"REQUEST_TIMEOUT":                &cfg.Limits.Timeout,
+		"AUTH_JWT_JWKS_REFRESH_INTERVAL": &cfg.Auth.Jwt.JwksRefreshInterval,
+		"AUTH_JWT_CLOCK_SKEW":            &cfg.Auth.Jwt.ClockSkew,
+		"CORS_MAX_AGE":                   &cfg.Cors.MaxAge,
+	}
+	for name, duration := range durations {
+		err := lookupEnvDuration(EnvPrefix+name, duration)
+		if err != nil {
+			return err
+		}
+	}
+
+	texts := map[string]*string{
+		"REQUEST_ID_HEADER":     &cfg.Server.RequestIdHeader,
+		"ASSETS_URL":            &cfg.Server.AssetsUrl,
+		"GRPC_ADDRESS":          &cfg.Grpc.Address,
+		"TLS_CERT_FILE":         &cfg.Server.Tls.CertFile,
+		"TLS_KEY_FILE":          &cfg.Server.Tls.KeyFile,
+		"TLS_CLIENT_CA_FILE":    &cfg.Server.Tls.ClientCaFile,
+		"TLS_CLIENT_AUTH":       &cfg.Server.Tls.ClientAuth,
+		"LOG_FORMAT":            &cfg.Logging.Format,
+		"AUTH_API_KEYS_FILE":    &cfg.Auth.ApiKeysFile,
+		"AUTH_JWT_JWKS_URL":     &cfg.Auth.Jwt.JwksUrl,
+		"AUTH_JWT_JWKS_FILE":    &cfg.Auth.Jwt.JwksFile,
+		"AUTH_JWT_ISSUER":       &cfg.Auth.Jwt.Issuer,
+		"AUTH_JWT_AUDIENCE":     &cfg.Auth.Jwt.Audience,
+		"AUTH_JWT_SCOPES_CLAIM": &cfg.Auth.Jwt.ScopesClaim,
+		"LOG_LEVEL":             &cfg.Logging.Level,
+		"METRICS_PATH":          &cfg.Metrics.Path,
+		"TRACING_EXPORTER":      &cfg.Tracing.Exporter,
+		"TRACING_PROTOCOL":      &cfg.Tracing.Protocol,
+		"TRACING_ENDPOINT":      &cfg.Tracing.Endpoint,
+		"TRACING_SERVICE_NAME":  &cfg.Tracing.ServiceName,
+		"AUDIT_SINK":            &cfg.Audit.Sink,
+		"AUDIT_FILE":            &cfg.Audit.File,
+		"AUDIT_REDACTION":       &cfg.Audit.Redaction,
+	}
+	for name, value := range texts {
+		if envValue, ok := os.LookupEnv(EnvPrefix + name); ok {
+			*value = envValue
+		}
+	}
+
+	if outputs, ok := os.LookupEnv(EnvPrefix + "LOG_OUTPUTS"); ok {
+		cfg.Logging.Outputs = splitList(outputs)
+	}
+
+	lists := map[string]*[]string{
+		"CORS_ALLOWED_ORIGINS":  &cfg.Cors.AllowedOrigins,
+		"CORS_ALLOWED_METHODS":  &cfg.Cors.AllowedMethods,
+		"CORS_ALLOWED_HEADERS":  &cfg.Cors.AllowedHeaders,
+		"CORS_EXPOSED_HEADERS":  &cfg.Cors.ExposedHeaders,
+		"ADMIN_DISABLED_ROUTES": &cfg.Admin.DisabledRoutes,
+	}
+	for name, value := range lists {
+		if envValue, ok := os.LookupEnv(EnvPrefix + name); ok {
+			*value = splitList(envValue)
+		}
+	}
+
+	if keys, ok := os.LookupEnv(EnvPrefix + "AUTH_API_KEYS"); ok {
+		apiKeys, err := parseApiKeys(keys)
+		if err != nil {
+			return fmt.Errorf("invalid value for %sAUTH_API_KEYS: %s", EnvPrefix, err.Error())
+		}
+		cfg.Auth.ApiKeys = apiKeys
+	}
+
+	if costs, ok := os.LookupEnv(EnvPrefix + "RATE_LIMIT_COSTS"); ok {
+		for _, item := range splitList(costs) {
+			err := cfg.setRateLimitCost(item)
+			if err != nil {
+				return fmt.Errorf("invalid value for %sRATE_LIMIT_COSTS: %s", EnvPrefix, err.Error())
+			}
+		}
+	}
+
+	if rules, ok := os.LookupEnv(EnvPrefix + "AUDIT_RULES"); ok {
+		for _, item := range splitList(rules) {
+			err := cfg.setAuditRule(item)
+			if err != nil {
+				return fmt.Errorf("invalid value for %sAUDIT_RULES: %s", EnvPrefix, err.Error())
+			}
+		}
+	}
+
+	ints := map[string]*int{
+		"LOG_SAMPLING_INITIAL":    &cfg.Logging.Sampling.Initial,
+		"LOG_SAMPLING_THEREAFTER": &cfg.Logging.Sampling.Thereafter,
+		"RATE_LIMIT_BURST":        &cfg.RateLimit.Burst,
+		"BATCH_MAX_SIZE":          &cfg.Batch.MaxSize,
+		"BATCH_PARALLELISM":       &cfg.Batch.Parallelism,
+		"CACHE_SIZE":              &cfg.Cache.Size,
+		"GRAPHQL_MAX_DEPTH":       &cfg.Graphql.MaxDepth,
+		"GRAPHQL_MAX_COMPLEXITY":  &cfg.Graphql.MaxComplexity,
+		"AUDIT_BUFFER_SIZE":       &cfg.Audit.BufferSize,
+	}
+	for name, value := range ints {
+		err := lookupEnvInt(EnvPrefix+name, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	bools := map[string]*bool{
+		"LOG_SAMPLING":           &cfg.Logging.Sampling.Enabled,
+		"AUTH_ENABLED":           &cfg.Auth.Enabled,
+		"AUTH_JWT_ENABLED":       &cfg.Auth.Jwt.Enabled,
+		"RATE_LIMIT_ENABLED":     &cfg.RateLimit.Enabled,
+		"ACCESS_LOG":             &cfg.Logging.AccessLog,
+		"BATCH_ENABLED":          &cfg.Batch.Enabled,
+		"GRPC_ENABLED":           &cfg.Grpc.Enabled,
+		"GRPC_REFLECTION":        &cfg.Grpc.Reflection,
+		"GRAPHQL_ENABLED":        &cfg.Graphql.Enabled,
+		"GRAPHQL_PLAYGROUND":     &cfg.Graphql.Playground,
+		"CACHE_ENABLED":          &cfg.Cache.Enabled,
+		"CORS_ENABLED":           &cfg.Cors.Enabled,
+		"AUDIT_ENABLED":          &cfg.Audit.Enabled,
+		"ADMIN_ENABLED":          &cfg.Admin.Enabled,
+		"ADMIN_PPROF":            &cfg.Admin.Pprof,
+		"CORS_ALLOW_CREDENTIALS": &cfg.Cors.AllowCredentials,
+		"METRICS_ENABLED":        &cfg.Metrics.Enabled,
+		"TRACING_ENABLED":        &cfg.Tracing.Enabled,
+		"TRACING_INSECURE":       &cfg.Tracing.Insecure,
+	}
+	for name, value := range bools {
+		err := lookupEnvBool(EnvPrefix+name, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	if value, ok := os.LookupEnv(EnvPrefix + "MAX_BODY_SIZE"); ok {
+		maxBodySize, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid value for %sMAX_BODY_SIZE: %s", EnvPrefix, err.Error())
+		}
+		cfg.Limits.MaxBodySize = maxBodySize
+	}
+
+	floats := map[string]*float64{
+		"TRACING_SAMPLE_RATIO": &cfg.Tracing.SampleRatio,
+		"RATE_LIMIT_RATE":      &cfg.RateLimit.Rate,
+	}
+	for name, value := range floats {
+		err := lookupEnvFloat(EnvPrefix+name, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range Groups {
+		prefix := EnvPrefix + strings.ToUpper(name) + "_"
+
+		if value, ok := os.LookupEnv(prefix + "ENABLED"); ok {
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("invalid value for %sENABLED: %s", prefix, err.Error())
+			}
+			cfg.setGroupEnabled(name, enabled)
+		}
+
+		if value, ok := os.LookupEnv(prefix + "OPTIONS"); ok {
+			for _, option := range splitList(value) {
+				key, optionValue, err := splitKeyValue(option)
+				if err != nil {
+					return fmt.Errorf("invalid value for %sOPTIONS: %s", prefix, err.Error())
+				}
+				cfg.setGroupOption(name, key, optionValue)
+			}
+		}
+	}
+
+	return nil
+}
+
+// newFlagSet returns the command-line flags, bound to the fields of cfg.
+func newFlagSet(cfg *Config) *flag.FlagSet {
+	fs := flag.NewFlagSet("canivete-api", flag.ContinueOnError)
+
+	fs.StringVar(&cfg.ConfigFile, "config", cfg.ConfigFile, "path of a YAML or TOML config file")
+	fs.BoolVar(&cfg.PrintConfig, "print-config", cfg.PrintConfig, "print the effective configuration and exit")
+
+	fs.StringVar(&cfg.Server.Address, "address", cfg.Server.Address, "address the server listens on")
+	fs.StringVar(&cfg.Server.Mode, "mode", cfg.Server.Mode, "gin mode: debug, release or test")
+	fs.Func("trusted-proxies", "comma separated list of trusted proxies", func(value string) error {
+		cfg.Server.TrustedProxies = splitList(value)
+		return nil
+	})
+	fs.StringVar(&cfg.Server.RequestIdHeader, "request-id-header", cfg.Server.RequestIdHeader, "header carrying the request id")
+	fs.StringVar(&cfg.Server.AssetsUrl, "assets-url", cfg.Server.AssetsUrl, "base url of the scripts and styles of the docs and GraphiQL")
+	fs.DurationVar(&cfg.Server.ReadTimeout, "read-timeout", cfg.Server.ReadTimeout, "maximum duration for reading a request")
+	fs.DurationVar(&cfg.Server.ReadHeaderTimeout, "read-header-timeout", cfg.Server.ReadHeaderTimeout, "maximum duration for reading the request headers")
+	fs.DurationVar(&cfg.Server.WriteTimeout, "write-timeout", cfg.Server.WriteTimeout, "maximum duration before timing out writes of the response")
+	fs.DurationVar(&cfg.Server.IdleTimeout, "idle-timeout", cfg.Server.IdleTimeout, "maximum duration to wait for the next request on keep-alive connections")
+	fs.DurationVar(&cfg.Server.ShutdownTimeout, "shutdown-timeout", cfg.Server.ShutdownTimeout, "maximum duration to drain connections on shutdown")
+	fs.DurationVar(&cfg.Server.ShutdownHooksTimeout, "shutdown-hooks-timeout", cfg.Server.ShutdownHooksTimeout, "maximum duration of the shutdown hooks, after the drain")
+	fs.StringVar(&cfg.Server.Tls.CertFile, "tls-cert-file", cfg.Server.Tls.CertFile, "certificate served over TLS, enables TLS")
+	fs.StringVar(&cfg.Server.Tls.KeyFile, "tls-key-file", cfg.Server.Tls.KeyFile, "private key of the TLS certificate")
+	fs.StringVar(&cfg.Server.Tls.ClientCaFile, "tls-client-ca-file", cfg.Server.Tls.ClientCaFile, "CA bundle verifying client certificates, enables mutual TLS")
+	fs.StringVar(&cfg.Server.Tls.ClientAuth, "tls-client-auth", cfg.Server.Tls.ClientAuth, "client certificates with mutual TLS: require or optional")
+	fs.DurationVar(&cfg.Server.Tls.ReloadInterval, "tls-reload-interval", cfg.Server.Tls.ReloadInterval, "how often the certificate files are checked for changes")
+
+	fs.BoolVar(&cfg.Grpc.Enabled, "grpc", cfg.Grpc.Enabled, "serve the service groups over gRPC too")
+	fs.StringVar(&cfg.Grpc.Address, "grpc-address", cfg.Grpc.Address, "address the gRPC server listens on")
+	fs.BoolVar(&cfg.Grpc.Reflection, "grpc-reflection", cfg.Grpc.Reflection, "expose the gRPC reflection service")
+
+	fs.StringVar(&cfg.Logging.Format, "log-format", cfg.Logging.Format, "log format: json or console")
+	fs.StringVar(&cfg.Logging.Level, "log-level", cfg.Logging.Level, "minimum log level: debug, info, warn or error")
+	fs.Func("log-outputs", "comma separated list of log sinks: stdout, stderr or file paths", func(value string) error {
+		cfg.Logging.Outputs = splitList(value)
+		return nil
+	})
+	fs.BoolVar(&cfg.Logging.Sampling.Enabled, "log-sampling", cfg.Logging.Sampling.Enabled, "sample repeated log entries")
+	fs.IntVar(&cfg.Logging.Sampling.Initial, "log-sampling-initial", cfg.Logging.Sampling.Initial, "entries logged each second before sampling")
+	fs.IntVar(&cfg.Logging.Sampling.Thereafter, "log-sampling-thereafter", cfg.Logging.Sampling.Thereafter, "once sampling, log one entry every thereafter")
+	fs.BoolVar(&cfg.Logging.AccessLog, "access-log", cfg.Logging.AccessLog, "log one entry per request")
+
+	fs.BoolVar(&cfg.Auth.Enabled, "auth", cfg.Auth.Enabled, "require an api key to call the service groups")
+	fs.StringVar(&cfg.Auth.ApiKeysFile, "auth-api-keys-file", cfg.Auth.ApiKeysFile, "path of a YAML file listing the api keys")
+	fs.BoolVar(&cfg.Auth.Jwt.Enabled, "auth-jwt", cfg.Auth.Jwt.Enabled, "accept bearer JWTs signed by the keys of the jwks")
+	fs.StringVar(&cfg.Auth.Jwt.JwksUrl, "auth-jwt-jwks-url", cfg.Auth.Jwt.JwksUrl, "address of the jwks of the OIDC provider")
+	fs.StringVar(&cfg.Auth.Jwt.JwksFile, "auth-jwt-jwks-file", cfg.Auth.Jwt.JwksFile, "path of a jwks file")
+	fs.DurationVar(&cfg.Auth.Jwt.JwksRefreshInterval, "auth-jwt-jwks-refresh-interval", cfg.Auth.Jwt.JwksRefreshInterval, "how long the jwks is cached")
+	fs.StringVar(&cfg.Auth.Jwt.Issuer, "auth-jwt-issuer", cfg.Auth.Jwt.Issuer, "expected issuer of the JWTs")
+	fs.StringVar(&cfg.Auth.Jwt.Audience, "auth-jwt-audience", cfg.Auth.Jwt.Audience, "expected audience of the JWTs")
+	fs.DurationVar(&cfg.Auth.Jwt.ClockSkew, "auth-jwt-clock-skew", cfg.Auth.Jwt.ClockSkew, "tolerance when checking the times of the JWTs")
+	fs.StringVar(&cfg.Auth.Jwt.ScopesClaim, "auth-jwt-scopes-claim", cfg.Auth.Jwt.ScopesClaim, "claim holding the scopes of the caller")
+
+	fs.BoolVar(&cfg.RateLimit.Enabled, "rate-limit", cfg.RateLimit.Enabled, "limit the rate of requests of each client")
+	fs.Float64Var(&cfg.RateLimit.Rate, "rate-limit-rate", cfg.RateLimit.Rate, "tokens given to each client per second")
+	fs.IntVar(&cfg.RateLimit.Burst, "rate-limit-burst", cfg.RateLimit.Burst, "maximum tokens of each client")
+	fs.Func("rate-limit-cost", "cost of a route as /route=cost (repeatable)", cfg.setRateLimitCost)
+
+	fs.Int64Var(&cfg.Limits.MaxBodySize, "max-body-size", cfg.Limits.MaxBodySize, "maximum size of the request bodies, in bytes")
+	fs.DurationVar(&cfg.Limits.Timeout, "request-timeout", cfg.Limits.Timeout, "maximum duration of the tools")
+
+	fs.BoolVar(&cfg.Batch.Enabled, "batch", cfg.Batch.Enabled, "expose the batch endpoint")
+	fs.IntVar(&cfg.Batch.MaxSize, "batch-max-size", cfg.Batch.MaxSize, "maximum number of requests of a batch")
+	fs.IntVar(&cfg.Batch.Parallelism, "batch-parallelism", cfg.Batch.Parallelism, "requests of a batch run at the same time")
+
+	fs.BoolVar(&cfg.Graphql.Enabled, "graphql", cfg.Graphql.Enabled, "expose the GraphQL endpoint")
+	fs.IntVar(&cfg.Graphql.MaxDepth, "graphql-max-depth", cfg.Graphql.MaxDepth, "maximum nesting of the fields of a GraphQL query")
+	fs.IntVar(&cfg.Graphql.MaxComplexity, "graphql-max-complexity", cfg.Graphql.MaxComplexity, "maximum number of fields of a GraphQL query")
+	fs.BoolVar(&cfg.Graphql.Playground, "graphql-playground", cfg.Graphql.Playground, "serve GraphiQL on the GraphQL endpoint")
+
+	fs.BoolVar(&cfg.Cache.Enabled, "cache", cfg.Cache.Enabled, "keep the responses of the deterministic routes in memory")
+	fs.IntVar(&cfg.Cache.Size, "cache-size", cfg.Cache.Size, "number of responses kept in memory")
+	fs.Func("cache-control", "Cache-Control of a route as /route=value (repeatable)", cfg.setCacheControl)
+
+	fs.BoolVar(&cfg.Cors.Enabled, "cors", cfg.Cors.Enabled, "answer the CORS requests of the browser clients")
+	fs.Func("cors-allowed-origins", "comma separated list of origins, like https://*.example.com", func(value string) error {
+		cfg.Cors.AllowedOrigins = splitList(value)
+		return nil
+	})
+	fs.Func("cors-allowed-methods", "comma separated list of methods allowed by the preflights", func(value string) error {
+		cfg.Cors.AllowedMethods = splitList(value)
+		return nil
+	})
+	fs.Func("cors-allowed-headers", "comma separated list of request headers allowed by the preflights", func(value string) error {
+		cfg.Cors.AllowedHeaders = splitList(value)
+		return nil
+	})
+	fs.Func("cors-exposed-headers", "comma separated list of response headers readable by the browser clients", func(value string) error {
+		cfg.Cors.ExposedHeaders = splitList(value)
+		return nil
+	})
+	fs.BoolVar(&cfg.Cors.AllowCredentials, "cors-allow-credentials", cfg.Cors.AllowCredentials, "allow the browser clients to send credentials")
+	fs.DurationVar(&cfg.Cors.MaxAge, "cors-max-age", cfg.Cors.MaxAge, "how long the browsers cache the preflights")
+
+	fs.BoolVar(&cfg.Audit.Enabled, "audit", cfg.Audit.Enabled, "record the tool calls in the audit log")
+	fs.StringVar(&cfg.Audit.Sink, "audit-sink", cfg.Audit.Sink, "audit sink: file, stdout or memory")
+	fs.StringVar(&cfg.Audit.File, "audit-file", cfg.Audit.File, "JSON lines file of the file audit sink")
+	fs.IntVar(&cfg.Audit.BufferSize, "audit-buffer-size", cfg.Audit.BufferSize, "records kept by the memory audit sink")
+	fs.StringVar(&cfg.Audit.Redaction, "audit-redaction", cfg.Audit.Redaction, "mode of the audited inputs without rule: hash, redact or keep")
+	fs.Func("audit-rule", "mode of an audited input as /route=input:mode (repeatable)", cfg.setAuditRule)
+
+	fs.BoolVar(&cfg.Admin.Enabled, "admin", cfg.Admin.Enabled, "expose the admin endpoints changing the api at runtime")
+	fs.BoolVar(&cfg.Admin.Pprof, "admin-pprof", cfg.Admin.Pprof, "expose the pprof profiles on the admin endpoints")
+	fs.Func("admin-disabled-routes", "comma separated list of tool routes answered with 503, like /v1/internet/medium-to-md", func(value string) error {
+		cfg.Admin.DisabledRoutes = splitList(value)
+		return nil
+	})
+
+	fs.DurationVar(&cfg.Health.CheckTimeout, "health-check-timeout", cfg.Health.CheckTimeout, "maximum duration of each health check")
+	fs.DurationVar(&cfg.Health.CacheTtl, "health-cache-ttl", cfg.Health.CacheTtl, "how long the result of a health check is reused, 0 to disable")
+
+	fs.BoolVar(&cfg.Metrics.Enabled, "metrics", cfg.Metrics.Enabled, "expose the Prometheus metrics")
+	fs.StringVar(&cfg.Metrics.Path, "metrics-path", cfg.Metrics.Path, "path of the Prometheus metrics endpoint")
+
+	fs.BoolVar(&cfg.Tracing.Enabled, "tracing", cfg.Tracing.Enabled, "enable the OpenTelemetry tracing")
+	fs.StringVar(&cfg.Tracing.Exporter, "tracing-exporter", cfg.Tracing.Exporter, "tracing exporter: otlp, stdout or none")
+	fs.StringVar(&cfg.Tracing.Protocol, "tracing-protocol", cfg.Tracing.Protocol, "OTLP protocol: grpc or http")
+	fs.StringVar(&cfg.Tracing.Endpoint, "tracing-endpoint", cfg.Tracing.Endpoint, "host:port of the OTLP collector")
+	fs.BoolVar(&cfg.Tracing.Insecure, "tracing-insecure", cfg.Tracing.Insecure, "connect to the OTLP collector without TLS")
+	fs.Float64Var(&cfg.Tracing.SampleRatio, "tracing-sample-ratio", cfg.Tracing.SampleRatio, "ratio of the traces sampled, from 0 to 1")
+	fs.StringVar(&cfg.Tracing.ServiceName, "tracing-service-name", cfg.Tracing.ServiceName, "service name reported in the traces")
+
+	fs.Func("enable-group", "service group to enable (repeatable)", func(name string) error {
+		cfg.setGroupEnabled(name, true)
+		return nil
+	})
+	fs.Func("disable-group", "service group to disable (repeatable)", func(name string) error {
+		cfg.setGroupEnabled(name, false)
+		return nil
+	})
+	fs.Func("group-option", "service group option as group.key=value (repeatable)", func(option string) error {
+		key, value, err := splitKeyValue(option)
+		if err != nil {
+			return err
+		}
+
+		i := strings.Index(key, ".")
+		if i <= 0 || i == len(key)-1 {
+			return fmt.Errorf("%q must be group.key=value", option)
+		}
+		cfg.setGroupOption(key[:i], key[i+1:], value)
+
+		return nil
+	})
+
+	return fs
+}
+
+func lookupEnvDuration(name string, duration *time.Duration) error {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %s", name, err.Error())
+	}
+	*duration = parsed
+
+	return nil
+}
+
+func lookupEnvBool(name string, value *bool) error {
+	envValue, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+
+	parsed, err := strconv.ParseBool(envValue)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %s", name, err.Error())
+	}
+	*value = parsed
+
+	return nil
+}
+
+func lookupEnvInt(name string, value *int) error {
+	envValue, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+
+	parsed, err := strconv.Atoi(envValue)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %s", name, err.Error())
+	}
+	*value = parsed
+
+	return nil
+}
+
+func lookupEnvFloat(name string, value *float64) error {
+	envValue, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+
+	parsed, err := strconv.ParseFloat(envValue, 64)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %s", name, err.Error())
+	}
+	*value = parsed
+
+	return nil
+}
+
+// parseApiKeys reads api keys written as name:hash:scope|scope, separated
+// by commas.
+func parseApiKeys(value string) ([]ApiKeyConfig, error) {
+	keys := []ApiKeyConfig{}
+	for _, item := range splitList(value) {
+		parts := strings.Split(item, ":")
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("%q must be name:hash:scope|scope", item)
+		}
+
+		keys = append(keys, ApiKeyConfig{
+			Name:   parts[0],
+			Hash:   parts[1],
+			Scopes: strings.Split(parts[2], "|"),
+		})
+	}
+
+	return keys, nil
+}
+
+func splitList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func splitKeyValue(value string) (string, string, error) {
+	i := strings.Index(value, "=")
+	if i <= 0 {
+		return "", "", fmt.Errorf("%q must be key=value", value)
+	}
+
+	return value[:i], value[i+1:], nil
+}
@@ -0,0 +1,155 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cors
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+)
+
+// Middleware answers the CORS requests of the browser clients with the
+// policy of the route. The preflights of the allowed origins are answered
+// with 204 (No Content) and the others with 403 (Forbidden). It must be
+// used by the engine, so the preflights are answered before the
+// authentication, and the routes need the OPTIONS routes added by
+// SetPreflightRoutes.
+func Middleware(cfg config.CorsConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// the shared caches must not serve the responses to other origins
+		c.Writer.Header().Add("Vary", "Origin")
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		route := c.FullPath()
+		policy := cfg.For(route)
+		requestedMethod := c.GetHeader("Access-Control-Request-Method")
+		preflight := c.Request.Method == http.MethodOptions && requestedMethod != "" && route != ""
+
+		if !allowedOrigin(policy.AllowedOrigins, origin) {
+			if preflight {
+				apierrors.Abort(c, apierrors.New(http.StatusForbidden, apierrors.CodeForbidden, "the origin is not allowed"))
+				return
+			}
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		if contains(policy.AllowedOrigins, "*") && !policy.AllowCredentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if policy.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if len(policy.ExposedHeaders) > 0 {
+				header.Set("Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ", "))
+			}
+			c.Next()
+			return
+		}
+
+		if !contains(policy.AllowedMethods, strings.ToUpper(requestedMethod)) {
+			apierrors.Abort(c, apierrors.New(http.StatusForbidden, apierrors.CodeForbidden, "the method is not allowed for the origin"))
+			return
+		}
+		header.Set("Access-Control-Allow-Methods", strings.Join(policy.AllowedMethods, ", "))
+		if len(policy.AllowedHeaders) > 0 {
+			header.Set("Access-Control-Allow-Headers", strings.Join(policy.AllowedHeaders, ", "))
+		}
+		if policy.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(policy.MaxAge.Seconds())))
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// SetPreflightRoutes adds an OPTIONS route to every path of the engine,
+// so the preflights of the registered routes reach Middleware. The OPTIONS
+// requests which are not preflights are answered with the Allow header.
+// It must be called once every route is added.
+func SetPreflightRoutes(r *gin.Engine) {
+	methods := map[string][]string{}
+	for _, route := range r.Routes() {
+		methods[route.Path] = append(methods[route.Path], route.Method)
+	}
+
+	for path, pathMethods := range methods {
+		if contains(pathMethods, http.MethodOptions) {
+			continue
+		}
+
+		allow := append([]string{http.MethodOptions}, pathMethods...)
+		sort.Strings(allow)
+		value := strings.Join(allow, ", ")
+		r.OPTIONS(path, func(c *gin.Context) {
+			c.Header("Allow", value)
+			c.Status(http.StatusNoContent)
+		})
+	}
+}
+
+// allowedOrigin tells if the origin matches one of the allowed origins,
+// where * matches any origin and https://*.example.com the subdomains of
+// example.com.
+func allowedOrigin(allowed []string, origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if pattern == "*" || pattern == origin {
+			return true
+		}
+
+		wildcard := strings.Index(pattern, "://*.")
+		if wildcard < 0 {
+			continue
+		}
+		prefix, suffix := pattern[:wildcard+3], pattern[wildcard+4:]
+		if !strings.HasPrefix(origin, prefix) || !strings.HasSuffix(origin, suffix) ||
+			len(origin) <= len(prefix)+len(suffix) {
+			continue
+		}
+		subdomain := origin[len(prefix) : len(origin)-len(suffix)]
+		if !strings.ContainsAny(subdomain, ":/@") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,236 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGin(cfg config.CorsConfig) *gin.Engine {
+	r := gin.New()
+	r.Use(Middleware(cfg))
+
+	v1 := r.Group("/v1")
+	v1.GET("/programming/uuid", func(c *gin.Context) {
+		c.String(http.StatusOK, "uuid")
+	})
+	authenticated := v1.Group("", func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	})
+	authenticated.POST("/finance/compound-interests", func(c *gin.Context) {
+		c.String(http.StatusOK, "interests")
+	})
+
+	SetPreflightRoutes(r)
+
+	return r
+}
+
+func testConfig() config.CorsConfig {
+	cfg := config.Default().Cors
+	cfg.Enabled = true
+	cfg.AllowedOrigins = []string{"https://portal.example.com", "https://*.internal.example.com"}
+	return cfg
+}
+
+func preflight(r *gin.Engine, path, origin, method string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodOptions, path, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	req.Header.Set("Access-Control-Request-Headers", "x-api-key")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestPreflightOfAllowedOrigin(t *testing.T) {
+	// arrange
+	r := setupGin(testConfig())
+
+	// act
+	w := preflight(r, "/v1/finance/compound-interests", "https://portal.example.com", http.MethodPost)
+
+	// assert
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://portal.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST, HEAD", w.Header().Get("Access-Control-Allow-Methods"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "X-API-Key")
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+}
+
+func TestPreflightIsRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		path   string
+		origin string
+		method string
+		status int
+	}{
+		{"unknown origin", "/v1/programming/uuid", "https://evil.example.com", http.MethodGet, http.StatusForbidden},
+		{"method not allowed", "/v1/programming/uuid", "https://portal.example.com", http.MethodDelete, http.StatusForbidden},
+		{"unknown route", "/v1/unknown", "https://portal.example.com", http.MethodGet, http.StatusNotFound},
+	}
+
+	for _, test := range tests {
+		// arrange
+		r := setupGin(testConfig())
+
+		// act
+		w := preflight(r, test.path, test.origin, test.method)
+
+		// assert
+		assert.Equal(t, test.status, w.Code, test.name)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Methods"), test.name)
+	}
+}
+
+func TestPreflightUsesThePolicyOfTheGroup(t *testing.T) {
+	// arrange
+	allowCredentials := true
+	cfg := testConfig()
+	cfg.Groups = map[string]config.CorsGroupConfig{
+		"/v1/finance": {
+			AllowedOrigins:   []string{"https://finance.example.com"},
+			AllowCredentials: &allowCredentials,
+			MaxAge:           time.Hour,
+		},
+	}
+	r := setupGin(cfg)
+
+	// act
+	finance := preflight(r, "/v1/finance/compound-interests", "https://finance.example.com", http.MethodPost)
+	portal := preflight(r, "/v1/finance/compound-interests", "https://portal.example.com", http.MethodPost)
+	programming := preflight(r, "/v1/programming/uuid", "https://finance.example.com", http.MethodGet)
+
+	// assert
+	assert.Equal(t, http.StatusNoContent, finance.Code)
+	assert.Equal(t, "https://finance.example.com", finance.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", finance.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "3600", finance.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, http.StatusForbidden, portal.Code)
+	assert.Equal(t, http.StatusForbidden, programming.Code)
+}
+
+func TestRequestOfAllowedOrigin(t *testing.T) {
+	// arrange
+	r := setupGin(testConfig())
+	req, _ := http.NewRequest(http.MethodGet, "/v1/programming/uuid", nil)
+	req.Header.Set("Origin", "https://tools.internal.example.com")
+	w := httptest.NewRecorder()
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://tools.internal.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, w.Header().Get("Access-Control-Expose-Headers"), "X-Request-ID")
+}
+
+func TestErrorsOfAllowedOriginAreReadable(t *testing.T) {
+	// arrange
+	r := setupGin(testConfig())
+	req, _ := http.NewRequest(http.MethodPost, "/v1/finance/compound-interests", nil)
+	req.Header.Set("Origin", "https://portal.example.com")
+	w := httptest.NewRecorder()
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, "https://portal.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestRequestOfUnknownOrigin(t *testing.T) {
+	// arrange
+	r := setupGin(testConfig())
+	req, _ := http.NewRequest(http.MethodGet, "/v1/programming/uuid", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	w := httptest.NewRecorder()
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+}
+
+func TestRequestOfAnyOrigin(t *testing.T) {
+	// arrange
+	cfg := testConfig()
+	cfg.AllowedOrigins = []string{"*"}
+	r := setupGin(cfg)
+	req, _ := http.NewRequest(http.MethodGet, "/v1/programming/uuid", nil)
+	req.Header.Set("Origin", "https://anyone.example.org")
+	w := httptest.NewRecorder()
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestOptionsWithoutPreflightAnswersTheMethods(t *testing.T) {
+	// arrange
+	r := setupGin(testConfig())
+	req, _ := http.NewRequest(http.MethodOptions, "/v1/programming/uuid", nil)
+	w := httptest.NewRecorder()
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "GET, OPTIONS", w.Header().Get("Allow"))
+}
+
+func TestAllowedOrigin(t *testing.T) {
+	allowed := []string{"https://portal.example.com", "https://*.example.org", "http://*.localhost:3000"}
+	tests := []struct {
+		origin   string
+		expected bool
+	}{
+		{"https://portal.example.com", true},
+		{"https://PORTAL.example.com", true},
+		{"http://portal.example.com", false},
+		{"https://portal.example.com:8443", false},
+		{"https://a.example.org", true},
+		{"https://a.b.example.org", true},
+		{"https://example.org", false},
+		{"https://evil.com/.example.org", false},
+		{"https://evilexample.org", false},
+		{"http://app.localhost:3000", true},
+		{"http://app.localhost:3001", false},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, allowedOrigin(allowed, test.origin), test.origin)
+	}
+}
@@ -24,7 +24,13 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/cache"
+	"github.com/renato0307/canivete-api/pkg/limits"
 	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-api/pkg/openapi"
+	"github.com/renato0307/canivete-api/pkg/render"
+	"github.com/renato0307/canivete-api/pkg/tracing"
 	"github.com/renato0307/canivete-core/interface/datetime"
 	"go.uber.org/zap"
 )
@@ -37,6 +43,23 @@ func SetRouterGroup(p datetime.Interface, base *gin.RouterGroup) *gin.RouterGrou
 		programmingGroup.POST("/fromunix", postFromUnix(p))
 	}
 
+	openapi.Describe(programmingGroup, http.MethodPost, "/fromunix", openapi.Operation{
+		Summary: "Converts a unix timestamp to a UTC date",
+		Request: &openapi.Body{
+			ContentType: openapi.ContentTypeText,
+			Type:        int64(0),
+			Description: "The unix timestamp, in seconds, as a raw integer.",
+			Example:     1638964800,
+		},
+		Response: openapi.Body{Type: datetime.FromUnixTimestampOutput{}},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	})
+
+	cache.SetPolicy(programmingGroup, "/fromunix", cache.Policy{
+		CacheControl:  "public, max-age=86400",
+		Deterministic: true,
+	})
+
 	return programmingGroup
 }
 
@@ -44,19 +67,43 @@ func postFromUnix(p datetime.Interface) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		unitTimestampBody, err := ioutil.ReadAll(c.Request.Body)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, apierrors.ApiError{Message: "request body is invalid"})
+			apierrors.Abort(c, apierrors.Internal(apierrors.CodeInternal, "request body is invalid"))
 			return
 		}
 
 		unitTimestampString := strings.TrimRight(string(unitTimestampBody), "\n")
 		unixTimestamp, err := strconv.ParseInt(unitTimestampString, 10, 64)
 		if err != nil {
-			logger.Debugw("bad request for converting a unix timestamp to utc", "error", err.Error())
-			c.JSON(http.StatusBadRequest, apierrors.ApiError{Message: "unix timestamp must be an integer number"})
+			metrics.UnixTimestampsConverted.WithLabelValues(metrics.OutcomeFailure).Inc()
+			logging.FromContext(c).Debugw("bad request for converting a unix timestamp to utc", "error", err.Error())
+			apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidUnixTimestamp, "unix timestamp must be an integer number"))
 			return
 		}
 
-		output := p.FromUnitTimestamp(unixTimestamp)
-		c.JSON(http.StatusOK, output)
+		output, err := fromUnix(c, p, unixTimestamp)
+		if err != nil {
+			apierrors.Abort(c, limits.Timeout())
+			return
+		}
+		render.Render(c, http.StatusOK, output, func() string { return output.UtcTimestamp })
 	}
 }
+
+// fromUnix converts the timestamp with the service, in the time limit of
+// the request. It fails only when the limit is exceeded.
+func fromUnix(c *gin.Context, p datetime.Interface, unixTimestamp int64) (datetime.FromUnixTimestampOutput, error) {
+	span := tracing.StartSpan(c, "datetime.FromUnitTimestamp")
+	var output datetime.FromUnixTimestampOutput
+	err := limits.Call(c.Request.Context(), func() error {
+		output = p.FromUnitTimestamp(unixTimestamp)
+		return nil
+	})
+	tracing.EndSpan(span, err)
+	metrics.UnixTimestampsConverted.WithLabelValues(metrics.Outcome(err)).Inc()
+	if err != nil {
+		// the call may still be running and writing the output
+		return datetime.FromUnixTimestampOutput{}, err
+	}
+
+	return output, nil
+}
@@ -17,16 +17,20 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package datetime
 
 import (
+	"context"
 	"errors"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"net/http"
 	"net/http/httptest"
 
 	"github.com/gin-gonic/gin"
 	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/limits"
 	"github.com/renato0307/canivete-core/interface/datetime"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -100,7 +104,79 @@ func TestPostFromUnixWithInvalidTimestamp(t *testing.T) {
 	// assert
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	expectedError := apierrors.ApiError{Message: error.Error()}
-	apiError, _ := apierrors.FromResponseRecorder(w)
-	assert.Equal(t, expectedError, apiError)
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeInvalidUnixTimestamp, apiError.Code)
+	assert.Equal(t, error.Error(), apiError.Detail)
+}
+
+func TestPostFromUnixTimeout(t *testing.T) {
+	// arrange
+	release := make(chan time.Time)
+	defer close(release)
+	serviceMock := datetime.MockInterface{}
+	serviceMock.On("FromUnitTimestamp", mock.Anything).
+		WaitUntil(release).
+		Return(datetime.FromUnixTimestampOutput{}, nil)
+
+	r := gin.Default()
+	v1 := r.Group("/v1", limits.Middleware(config.LimitsConfig{MaxBodySize: 64, Timeout: 10 * time.Millisecond}))
+	SetRouterGroup(&serviceMock, v1)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v1/datetime/fromunix", strings.NewReader("1638964800"))
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeTimeout, apiError.Code)
+}
+
+func TestFromUnixReturnsNoOutputOnTimeout(t *testing.T) {
+	// arrange
+	release := make(chan time.Time)
+	written := make(chan struct{})
+	serviceMock := datetime.MockInterface{}
+	serviceMock.On("FromUnitTimestamp", mock.Anything).
+		WaitUntil(release).
+		Run(func(mock.Arguments) { defer close(written) }).
+		Return(datetime.FromUnixTimestampOutput{UnixTimestamp: 1638964800}, nil)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	c.Request, _ = http.NewRequestWithContext(ctx, "POST", "/v1/datetime/fromunix", nil)
+
+	// act
+	output, err := fromUnix(c, &serviceMock, 1638964800)
+	close(release)
+	<-written
+
+	// assert
+	assert.True(t, limits.Exceeded(err))
+	assert.Equal(t, datetime.FromUnixTimestampOutput{}, output)
+}
+
+func TestPostFromUnixAsYaml(t *testing.T) {
+	// arrange
+	output := datetime.FromUnixTimestampOutput{
+		UnixTimestamp: 1638964800,
+		UtcTimestamp:  "Wed Dec  8 12:00:00 UTC 2021",
+	}
+	serviceMock := datetime.MockInterface{}
+	serviceMock.On("FromUnitTimestamp", mock.Anything).Return(output, nil)
+
+	r := setupGin(&serviceMock)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v1/datetime/fromunix?format=yaml", strings.NewReader("1638964800"))
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "UnixTimestamp: 1638964800\nUtcTimestamp: Wed Dec  8 12:00:00 UTC 2021\n", w.Body.String())
 }
@@ -0,0 +1,62 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package datetime
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-api/pkg/limits"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-core/interface/datetime"
+)
+
+var unixTimestampType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "UnixTimestamp",
+	Fields: graphql.Fields{
+		"unixTimestamp": &graphql.Field{Type: graphql.NewNonNull(graphqlserver.Long)},
+		"utcTimestamp":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+// RegisterGraphqlFields adds the datetime tools to the GraphQL schema.
+func RegisterGraphqlFields(p datetime.Interface, g *graphqlserver.Group) {
+	g.Query("fromUnix", &graphql.Field{
+		Type:        unixTimestampType,
+		Description: "Converts a unix timestamp to an UTC date",
+		Args: graphql.FieldConfigArgument{
+			"unixTimestamp": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphqlserver.Long)},
+		},
+		Resolve: func(rp graphql.ResolveParams) (interface{}, error) {
+			unixTimestamp, _ := rp.Args["unixTimestamp"].(int64)
+
+			var output datetime.FromUnixTimestampOutput
+			err := limits.Call(rp.Context, func() error {
+				output = p.FromUnitTimestamp(unixTimestamp)
+				return nil
+			})
+			metrics.UnixTimestampsConverted.WithLabelValues(metrics.Outcome(err)).Inc()
+			if err != nil {
+				return nil, graphqlserver.Timeout()
+			}
+
+			return map[string]interface{}{
+				"unixTimestamp": output.UnixTimestamp,
+				"utcTimestamp":  output.UtcTimestamp,
+			}, nil
+		},
+	})
+}
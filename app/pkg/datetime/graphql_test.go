@@ -0,0 +1,59 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package datetime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-core/interface/datetime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphqlFromUnix(t *testing.T) {
+	// arrange
+	output := datetime.FromUnixTimestampOutput{
+		UnixTimestamp: 4102444800,
+		UtcTimestamp:  "Fri Jan  1 00:00:00 UTC 2100",
+	}
+	serviceMock := datetime.MockInterface{}
+	serviceMock.On("FromUnitTimestamp", int64(4102444800)).Return(output)
+	s := graphqlserver.New(config.Default().Graphql, false)
+	RegisterGraphqlFields(&serviceMock, s.Group(config.GroupDatetime))
+	schema, err := s.Schema()
+	assert.Nil(t, err)
+
+	// act
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  `query ($t: Long!) { fromUnix(unixTimestamp: $t) { unixTimestamp utcTimestamp } }`,
+		VariableValues: map[string]interface{}{"t": float64(4102444800)},
+		Context:        context.Background(),
+	})
+
+	// assert
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"fromUnix": map[string]interface{}{
+			"unixTimestamp": int64(4102444800),
+			"utcTimestamp":  "Fri Jan  1 00:00:00 UTC 2100",
+		},
+	}, result.Data)
+}
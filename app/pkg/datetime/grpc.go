@@ -0,0 +1,56 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package datetime
+
+import (
+	"context"
+
+	"github.com/renato0307/canivete-api/pkg/grpcserver"
+	"github.com/renato0307/canivete-api/pkg/limits"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	canivetev1 "github.com/renato0307/canivete-api/pkg/pb/canivete/v1"
+	"github.com/renato0307/canivete-core/interface/datetime"
+	"google.golang.org/grpc"
+)
+
+// grpcService serves the datetime tools over gRPC.
+type grpcService struct {
+	canivetev1.UnimplementedDatetimeServiceServer
+	p datetime.Interface
+}
+
+// RegisterGrpcService registers the gRPC service of the datetime tools.
+func RegisterGrpcService(p datetime.Interface, registrar grpc.ServiceRegistrar) {
+	canivetev1.RegisterDatetimeServiceServer(registrar, &grpcService{p: p})
+}
+
+func (s *grpcService) FromUnixTimestamp(ctx context.Context, req *canivetev1.FromUnixTimestampRequest) (*canivetev1.FromUnixTimestampResponse, error) {
+	var output datetime.FromUnixTimestampOutput
+	err := limits.Call(ctx, func() error {
+		output = s.p.FromUnitTimestamp(req.UnixTimestamp)
+		return nil
+	})
+	metrics.UnixTimestampsConverted.WithLabelValues(metrics.Outcome(err)).Inc()
+	if err != nil {
+		return nil, grpcserver.Timeout()
+	}
+
+	return &canivetev1.FromUnixTimestampResponse{
+		UnixTimestamp: output.UnixTimestamp,
+		UtcTimestamp:  output.UtcTimestamp,
+	}, nil
+}
@@ -0,0 +1,45 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package datetime
+
+import (
+	"context"
+	"testing"
+
+	canivetev1 "github.com/renato0307/canivete-api/pkg/pb/canivete/v1"
+	"github.com/renato0307/canivete-core/interface/datetime"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrpcFromUnixTimestamp(t *testing.T) {
+	// arrange
+	output := datetime.FromUnixTimestampOutput{
+		UnixTimestamp: 1638964800,
+		UtcTimestamp:  "Wed Dec  8 12:00:00 UTC 2021",
+	}
+	serviceMock := datetime.MockInterface{}
+	serviceMock.On("FromUnitTimestamp", int64(1638964800)).Return(output)
+	s := grpcService{p: &serviceMock}
+
+	// act
+	resp, err := s.FromUnixTimestamp(context.Background(), &canivetev1.FromUnixTimestampRequest{UnixTimestamp: 1638964800})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, output.UnixTimestamp, resp.UnixTimestamp)
+	assert.Equal(t, output.UtcTimestamp, resp.UtcTimestamp)
+}
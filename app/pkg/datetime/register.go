@@ -0,0 +1,57 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package datetime
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-api/pkg/tools"
+	"github.com/renato0307/canivete-api/pkg/versioning"
+	datetimecore "github.com/renato0307/canivete-core/pkg/datetime"
+	"google.golang.org/grpc"
+)
+
+func init() {
+	service := &datetimecore.Service{}
+	tools.Register(tools.Group{
+		Name:        config.GroupDatetime,
+		Description: "Conversions of dates and times",
+		Tools: []tools.Tool{
+			{
+				Name: "fromunix", Method: http.MethodPost, Path: "/fromunix", Tags: []string{"converters", "unix"},
+				GraphqlField: "Query.fromUnix", GrpcMethod: "/canivete.v1.DatetimeService/FromUnixTimestamp",
+			},
+		},
+		SetRouterGroup: func(base *gin.RouterGroup) *gin.RouterGroup {
+			return SetRouterGroup(service, base)
+		},
+		Versions: map[string]func(base *gin.RouterGroup) *gin.RouterGroup{
+			versioning.V2: func(base *gin.RouterGroup) *gin.RouterGroup {
+				return SetRouterGroupV2(service, base)
+			},
+		},
+		RegisterGrpcService: func(registrar grpc.ServiceRegistrar) {
+			RegisterGrpcService(service, registrar)
+		},
+		RegisterGraphqlFields: func(g *graphqlserver.Group) {
+			RegisterGraphqlFields(service, g)
+		},
+	})
+}
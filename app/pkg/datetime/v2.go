@@ -0,0 +1,109 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package datetime
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/cache"
+	"github.com/renato0307/canivete-api/pkg/limits"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-api/pkg/openapi"
+	"github.com/renato0307/canivete-api/pkg/render"
+	"github.com/renato0307/canivete-api/pkg/versioning"
+	"github.com/renato0307/canivete-core/interface/datetime"
+)
+
+// validateV2 validates the inputs of the routes of v2, naming the invalid
+// fields by their JSON name.
+var validateV2 = versioning.NewValidator()
+
+// fromUnixInput is the body of the v2 fromunix tool, a JSON object
+// instead of the raw integer of v1.
+type fromUnixInput struct {
+	Timestamp *int64 `json:"timestamp" validate:"required"`
+}
+
+type fromUnixOutput struct {
+	UnixTimestamp int64  `json:"unixTimestamp" yaml:"unixTimestamp" xml:"unixTimestamp"`
+	UtcTimestamp  string `json:"utcTimestamp" yaml:"utcTimestamp" xml:"utcTimestamp"`
+}
+
+// SetRouterGroupV2 mounts the v2 routes of the group, taking and returning
+// JSON objects in camel case.
+func SetRouterGroupV2(p datetime.Interface, base *gin.RouterGroup) *gin.RouterGroup {
+	datetimeGroup := base.Group("/datetime")
+	{
+		datetimeGroup.POST("/fromunix", postFromUnixV2(p))
+	}
+
+	openapi.Describe(datetimeGroup, http.MethodPost, "/fromunix", openapi.Operation{
+		Summary: "Converts a unix timestamp to a UTC date",
+		Request: &openapi.Body{
+			Type:        fromUnixInput{},
+			Description: "The unix timestamp, in seconds.",
+			Example:     map[string]int64{"timestamp": 1638964800},
+		},
+		Response: openapi.Body{Type: fromUnixOutput{}},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	})
+
+	cache.SetPolicy(datetimeGroup, "/fromunix", cache.Policy{
+		CacheControl:  "public, max-age=86400",
+		Deterministic: true,
+	})
+
+	return datetimeGroup
+}
+
+func postFromUnixV2(p datetime.Interface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			apierrors.Abort(c, apierrors.Internal(apierrors.CodeInternal, "unexpected error reading the body"))
+			return
+		}
+
+		input := fromUnixInput{}
+		err = json.Unmarshal(body, &input)
+		if err != nil {
+			metrics.UnixTimestampsConverted.WithLabelValues(metrics.OutcomeFailure).Inc()
+			apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidBody, "request body is invalid: "+err.Error()))
+			return
+		}
+
+		err = validateV2.Struct(input)
+		if err != nil {
+			metrics.UnixTimestampsConverted.WithLabelValues(metrics.OutcomeFailure).Inc()
+			logging.FromContext(c).Debugw("bad request for converting a unix timestamp to utc", "error", err.Error())
+			apierrors.Abort(c, apierrors.Validation(err))
+			return
+		}
+
+		output, err := fromUnix(c, p, *input.Timestamp)
+		if err != nil {
+			apierrors.Abort(c, limits.Timeout())
+			return
+		}
+		render.Render(c, http.StatusOK, fromUnixOutput(output), func() string { return output.UtcTimestamp })
+	}
+}
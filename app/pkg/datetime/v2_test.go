@@ -0,0 +1,104 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package datetime
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-core/interface/datetime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupGinV2(serviceMock *datetime.MockInterface) *gin.Engine {
+	r := gin.Default()
+	v2 := r.Group("/v2")
+	SetRouterGroupV2(serviceMock, v2)
+
+	return r
+}
+
+func TestPostFromUnixV2(t *testing.T) {
+	output := datetime.FromUnixTimestampOutput{
+		UnixTimestamp: 1638964800,
+		UtcTimestamp:  "Wed Dec  8 12:00:00 UTC 2021",
+	}
+
+	// arrange
+	serviceMock := datetime.MockInterface{}
+	serviceMock.On("FromUnitTimestamp", int64(1638964800)).Return(output, nil)
+
+	r := setupGinV2(&serviceMock)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v2/datetime/fromunix", strings.NewReader(`{"timestamp": 1638964800}`))
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	result := map[string]interface{}{}
+	err := json.Unmarshal(w.Body.Bytes(), &result)
+	assert.Nil(t, err, "invalid body returned")
+	assert.Equal(t, map[string]interface{}{
+		"unixTimestamp": float64(output.UnixTimestamp),
+		"utcTimestamp":  output.UtcTimestamp,
+	}, result)
+}
+
+func TestPostFromUnixV2WithInvalidBody(t *testing.T) {
+	tests := []struct {
+		body  string
+		code  string
+		field string
+	}{
+		{"1638964800", apierrors.CodeInvalidBody, ""},
+		{`{"timestamp": "1638964800"}`, apierrors.CodeInvalidBody, ""},
+		{"{}", apierrors.CodeValidationFailed, "timestamp"},
+	}
+
+	for _, test := range tests {
+		// arrange
+		serviceMock := datetime.MockInterface{}
+		serviceMock.On("FromUnitTimestamp", mock.Anything).Return(datetime.FromUnixTimestampOutput{}, nil)
+
+		r := setupGinV2(&serviceMock)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v2/datetime/fromunix", strings.NewReader(test.body))
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, w.Code, test.body)
+
+		apiError, err := apierrors.FromResponse(w.Result())
+		assert.Nil(t, err)
+		assert.Equal(t, test.code, apiError.Code, test.body)
+		if test.field != "" {
+			assert.Equal(t, test.field, apiError.Errors[0].Field)
+		}
+		serviceMock.AssertNotCalled(t, "FromUnitTimestamp", mock.Anything)
+	}
+}
@@ -17,18 +17,31 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package finance
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/cache"
+	"github.com/renato0307/canivete-api/pkg/limits"
 	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-api/pkg/openapi"
+	"github.com/renato0307/canivete-api/pkg/render"
+	"github.com/renato0307/canivete-api/pkg/tracing"
 	"github.com/renato0307/canivete-core/interface/finance"
 	"go.uber.org/zap"
 )
 
+// validate validates the inputs of the routes of v1, GraphQL and gRPC. It is
+// shared, as a validator caches the structs it validates.
+var validate = validator.New()
+
 var logger *zap.SugaredLogger = logging.GetLogger()
 
 type calculateCompoundInterestsInput struct {
@@ -46,6 +59,28 @@ func SetRouterGroup(f finance.Interface, base *gin.RouterGroup) *gin.RouterGroup
 		programmingGroup.POST("/calculate-compound-interests", postCalculateCompoundInterests(f))
 	}
 
+	openapi.Describe(programmingGroup, http.MethodPost, "/calculate-compound-interests", openapi.Operation{
+		Summary: "Calculates compound interests with regular contributions",
+		Request: &openapi.Body{
+			Type: calculateCompoundInterestsInput{},
+			Example: calculateCompoundInterestsInput{
+				InterestRate:               8,
+				CompoundPeriods:            12,
+				InvestAmount:               5000,
+				RegularContributions:       100,
+				RegularContributionsPeriod: 12,
+				Time:                       2,
+			},
+		},
+		Response: openapi.Body{Type: finance.CompoundInterestsOutput{}},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	})
+
+	cache.SetPolicy(programmingGroup, "/calculate-compound-interests", cache.Policy{
+		CacheControl:  "public, max-age=86400",
+		Deterministic: true,
+	})
+
 	return programmingGroup
 }
 
@@ -53,26 +88,41 @@ func postCalculateCompoundInterests(f finance.Interface) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		body, err := ioutil.ReadAll(c.Request.Body)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, apierrors.ApiError{Message: "unexpected error reading the body"})
+			apierrors.Abort(c, apierrors.Internal(apierrors.CodeInternal, "unexpected error reading the body"))
 			return
 		}
 
 		input := calculateCompoundInterestsInput{}
 		err = json.Unmarshal(body, &input)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, apierrors.ApiError{Message: "request body is invalid: " + err.Error()})
+			apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidBody, "request body is invalid: "+err.Error()))
 			return
 		}
 
-		validate := validator.New()
 		err = validate.Struct(input)
 		if err != nil {
-			logger.Debugw("bad request received for compound interests calculation", "error", err.Error())
-			c.JSON(http.StatusBadRequest, apierrors.ApiError{Message: err.Error()})
+			logging.FromContext(c).Debugw("bad request received for compound interests calculation", "error", err.Error())
+			apierrors.Abort(c, apierrors.Validation(err))
 			return
 		}
 
-		output, err := f.CalculateCompoundInterests(
+		output, ok := calculate(c, f, input)
+		if !ok {
+			return
+		}
+
+		render.RenderCsv(c, http.StatusOK, output, func() string { return historyCsv(output) })
+	}
+}
+
+// calculate calculates the compound interests with the service, in the
+// time limit of the request. It answers with the error and returns false
+// when the calculation fails.
+func calculate(c *gin.Context, f finance.Interface, input calculateCompoundInterestsInput) (finance.CompoundInterestsOutput, bool) {
+	span := tracing.StartSpan(c, "finance.CalculateCompoundInterests")
+	var output finance.CompoundInterestsOutput
+	err := limits.Call(c.Request.Context(), func() (err error) {
+		output, err = f.CalculateCompoundInterests(
 			input.InvestAmount,
 			input.CompoundPeriods,
 			input.Time,
@@ -80,12 +130,37 @@ func postCalculateCompoundInterests(f finance.Interface) gin.HandlerFunc {
 			input.RegularContributionsPeriod,
 			input.InterestRate,
 		)
-		if err != nil {
-			logger.Debugw("error while calculating compound interests", "error", err.Error())
-			c.JSON(http.StatusInternalServerError, apierrors.ApiError{Message: "unexpected error calculating interests: " + err.Error()})
-			return
-		}
+		return err
+	})
+	tracing.EndSpan(span, err)
+	metrics.CompoundInterestsCalculated.WithLabelValues(metrics.Outcome(err)).Inc()
+	if limits.Exceeded(err) {
+		apierrors.Abort(c, limits.Timeout())
+		return finance.CompoundInterestsOutput{}, false
+	}
+	if err != nil {
+		logging.FromContext(c).Debugw("error while calculating compound interests", "error", err.Error())
+		apierrors.Abort(c, apierrors.Internal(apierrors.CodeCalculationFailed, "unexpected error calculating interests: "+err.Error()))
+		return finance.CompoundInterestsOutput{}, false
+	}
+
+	return output, true
+}
 
-		c.JSON(http.StatusOK, output)
+// historyCsv returns the history of a calculation as CSV, one row by period.
+func historyCsv(output finance.CompoundInterestsOutput) string {
+	var buffer bytes.Buffer
+	w := csv.NewWriter(&buffer)
+	w.Write([]string{"period", "finalAmount", "totalContributions", "interests"})
+	for _, entry := range output.History {
+		w.Write([]string{
+			entry.Period,
+			strconv.FormatFloat(entry.Totals.FinalAmount, 'f', -1, 64),
+			strconv.FormatFloat(entry.Totals.TotalContributions, 'f', -1, 64),
+			strconv.FormatFloat(entry.Totals.Interests, 'f', -1, 64),
+		})
 	}
+	w.Flush()
+
+	return buffer.String()
 }
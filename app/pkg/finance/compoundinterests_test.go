@@ -122,9 +122,10 @@ func TestCalculateCompoundBodyMissing(t *testing.T) {
 	// assert
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	expectedError := apierrors.ApiError{Message: error.Error()}
-	apiError, _ := apierrors.FromResponseRecorder(w)
-	assert.Equal(t, expectedError, apiError)
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeInvalidBody, apiError.Code)
+	assert.Equal(t, error.Error(), apiError.Detail)
 }
 
 func TestCalculateCompoundBodyMissingRequired(t *testing.T) {
@@ -140,8 +141,14 @@ func TestCalculateCompoundBodyMissingRequired(t *testing.T) {
 	// assert
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	apiError, _ := apierrors.FromResponseRecorder(w)
-	assert.Contains(t, apiError.Message, "failed on the 'required'")
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeValidationFailed, apiError.Code)
+	assert.Contains(t, apiError.Errors, apierrors.FieldError{
+		Field:   "InvestAmount",
+		Rule:    "required",
+		Message: "InvestAmount is required",
+	})
 }
 
 func TestCalculateCompoundInterestsOnCoreError(t *testing.T) {
@@ -180,8 +187,10 @@ func TestCalculateCompoundInterestsOnCoreError(t *testing.T) {
 	// assert
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 
-	apiError, _ := apierrors.FromResponseRecorder(w)
-	assert.Contains(t, apiError.Message, "unexpected error calculating interest")
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeCalculationFailed, apiError.Code)
+	assert.Contains(t, apiError.Detail, "unexpected error calculating interest")
 }
 
 // func TestPostFromUnixWithCarriageReturn(t *testing.T) {
@@ -222,7 +231,54 @@ func TestCalculateCompoundInterestsOnCoreError(t *testing.T) {
 // 	// assert
 // 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-// 	expectedError := apierrors.ApiError{Message: error.Error()}
-// 	apiError := apierrors.FromResponseRecorder(w)
-// 	assert.Equal(t, expectedError, apiError)
+// 	apiError, _ := apierrors.FromResponse(w.Result())
+// 	assert.Equal(t, error.Error(), apiError.Detail)
 // }
+
+func TestHistoryCsv(t *testing.T) {
+	// arrange
+	output := finance.CompoundInterestsOutput{
+		History: []finance.CompoundInterestsHistoryEntryOutput{
+			{Period: "1", Totals: finance.CompoundInterestsDetailOutput{FinalAmount: 6660, TotalContributions: 6200, Interests: 460}},
+			{Period: "2", Totals: finance.CompoundInterestsDetailOutput{FinalAmount: 8457.76, TotalContributions: 7400, Interests: 1057.77}},
+		},
+	}
+
+	// act
+	csv := historyCsv(output)
+
+	// assert
+	assert.Equal(t, "period,finalAmount,totalContributions,interests\n1,6660,6200,460\n2,8457.76,7400,1057.77\n", csv)
+}
+
+func TestCalculateCompoundInterestsAsCsv(t *testing.T) {
+	// arrange
+	output := finance.CompoundInterestsOutput{
+		History: []finance.CompoundInterestsHistoryEntryOutput{
+			{Period: "1", Totals: finance.CompoundInterestsDetailOutput{FinalAmount: 6660, TotalContributions: 6200, Interests: 460}},
+		},
+	}
+	serviceMock := finance.MockInterface{}
+	serviceMock.On("CalculateCompoundInterests", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return(output, nil)
+
+	r := setupGin(&serviceMock)
+	body := `{"interestRate": 8, "compoundPeriods": 12, "investAmount": 5000, "time": 1, "regularContributionsPeriod": 12}`
+	serve := func(accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/finance/calculate-compound-interests", strings.NewReader(body))
+		req.Header.Set("Accept", accept)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// act
+	csv := serve("text/csv")
+	text := serve("text/plain")
+
+	// assert
+	assert.Equal(t, http.StatusOK, csv.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", csv.Header().Get("Content-Type"))
+	assert.Equal(t, historyCsv(output), csv.Body.String())
+	assert.Equal(t, http.StatusNotAcceptable, text.Code)
+}
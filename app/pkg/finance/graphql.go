@@ -0,0 +1,136 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-api/pkg/limits"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-core/interface/finance"
+)
+
+var compoundInterestsInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CompoundInterestsInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"interestRate":               &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+		"compoundPeriods":            &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+		"investAmount":               &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+		"regularContributions":       &graphql.InputObjectFieldConfig{Type: graphql.Float, DefaultValue: 0.0},
+		"regularContributionsPeriod": &graphql.InputObjectFieldConfig{Type: graphql.Float, DefaultValue: 0.0},
+		"time":                       &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.Float)},
+	},
+})
+
+var compoundInterestsDetailType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CompoundInterestsDetail",
+	Fields: graphql.Fields{
+		"finalAmount":        &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"totalContributions": &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+		"interests":          &graphql.Field{Type: graphql.NewNonNull(graphql.Float)},
+	},
+})
+
+var compoundInterestsPeriodType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CompoundInterestsPeriod",
+	Fields: graphql.Fields{
+		"period": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"totals": &graphql.Field{Type: graphql.NewNonNull(compoundInterestsDetailType)},
+	},
+})
+
+var compoundInterestsType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CompoundInterests",
+	Fields: graphql.Fields{
+		"total":   &graphql.Field{Type: graphql.NewNonNull(compoundInterestsDetailType)},
+		"history": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(compoundInterestsPeriodType)))},
+	},
+})
+
+// RegisterGraphqlFields adds the finance tools to the GraphQL schema.
+func RegisterGraphqlFields(f finance.Interface, g *graphqlserver.Group) {
+	g.Query("compoundInterests", &graphql.Field{
+		Type:        compoundInterestsType,
+		Description: "Calculates compound interests with regular contributions",
+		Args: graphql.FieldConfigArgument{
+			"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(compoundInterestsInputType)},
+		},
+		Resolve: func(rp graphql.ResolveParams) (interface{}, error) {
+			args, _ := rp.Args["input"].(map[string]interface{})
+			number := func(name string) float64 {
+				value, _ := args[name].(float64)
+				return value
+			}
+
+			// the same rules as the http requests
+			input := calculateCompoundInterestsInput{
+				InterestRate:               number("interestRate"),
+				CompoundPeriods:            number("compoundPeriods"),
+				InvestAmount:               number("investAmount"),
+				RegularContributions:       number("regularContributions"),
+				RegularContributionsPeriod: number("regularContributionsPeriod"),
+				Time:                       number("time"),
+			}
+			err := validate.Struct(input)
+			if err != nil {
+				logging.FromContext(rp.Context).Debugw("bad request received for compound interests calculation", "error", err.Error())
+				return nil, graphqlserver.Error(apierrors.Validation(err))
+			}
+
+			var output finance.CompoundInterestsOutput
+			err = limits.Call(rp.Context, func() (err error) {
+				output, err = f.CalculateCompoundInterests(
+					input.InvestAmount,
+					input.CompoundPeriods,
+					input.Time,
+					input.RegularContributions,
+					input.RegularContributionsPeriod,
+					input.InterestRate,
+				)
+				return err
+			})
+			metrics.CompoundInterestsCalculated.WithLabelValues(metrics.Outcome(err)).Inc()
+			if limits.Exceeded(err) {
+				return nil, graphqlserver.Timeout()
+			}
+			if err != nil {
+				logging.FromContext(rp.Context).Debugw("error calculating compound interests", "error", err.Error())
+				return nil, graphqlserver.Error(apierrors.Internal(apierrors.CodeCalculationFailed, err.Error()))
+			}
+
+			history := []interface{}{}
+			for _, entry := range output.History {
+				history = append(history, map[string]interface{}{
+					"period": entry.Period,
+					"totals": detailObject(entry.Totals),
+				})
+			}
+
+			return map[string]interface{}{"total": detailObject(output.Total), "history": history}, nil
+		},
+	})
+}
+
+func detailObject(detail finance.CompoundInterestsDetailOutput) map[string]interface{} {
+	return map[string]interface{}{
+		"finalAmount":        detail.FinalAmount,
+		"totalContributions": detail.TotalContributions,
+		"interests":          detail.Interests,
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-core/interface/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func doGraphql(t *testing.T, f finance.Interface, query string) *graphql.Result {
+	s := graphqlserver.New(config.Default().Graphql, false)
+	RegisterGraphqlFields(f, s.Group(config.GroupFinance))
+	schema, err := s.Schema()
+	assert.Nil(t, err)
+
+	return graphql.Do(graphql.Params{Schema: schema, RequestString: query, Context: context.Background()})
+}
+
+func TestGraphqlCompoundInterests(t *testing.T) {
+	// arrange
+	detail := finance.CompoundInterestsDetailOutput{FinalAmount: 5416, TotalContributions: 5000, Interests: 416}
+	output := finance.CompoundInterestsOutput{
+		Total:   detail,
+		History: []finance.CompoundInterestsHistoryEntryOutput{{Period: "1", Totals: detail}},
+	}
+	serviceMock := finance.MockInterface{}
+	serviceMock.On("CalculateCompoundInterests", 5000.0, 12.0, 1.0, 0.0, 12.0, 8.0).Return(output, nil)
+	query := `{
+		compoundInterests(input: {investAmount: 5000, compoundPeriods: 12, time: 1, interestRate: 8, regularContributionsPeriod: 12}) {
+			total { finalAmount }
+			history { period totals { interests } }
+		}
+	}`
+
+	// act
+	result := doGraphql(t, &serviceMock, query)
+
+	// assert
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"compoundInterests": map[string]interface{}{
+			"total": map[string]interface{}{"finalAmount": 5416.0},
+			"history": []interface{}{
+				map[string]interface{}{"period": "1", "totals": map[string]interface{}{"interests": 416.0}},
+			},
+		},
+	}, result.Data)
+}
+
+func TestGraphqlCompoundInterestsWithInvalidInput(t *testing.T) {
+	// arrange
+	serviceMock := finance.MockInterface{}
+	query := `{ compoundInterests(input: {investAmount: 5000, compoundPeriods: 12, time: 1, interestRate: 0}) { total { finalAmount } } }`
+
+	// act
+	result := doGraphql(t, &serviceMock, query)
+
+	// assert
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, apierrors.CodeValidationFailed, result.Errors[0].Extensions["code"])
+		fieldErrors := result.Errors[0].Extensions["errors"].([]apierrors.FieldError)
+		assert.Equal(t, "InterestRate", fieldErrors[0].Field)
+	}
+	serviceMock.AssertNotCalled(t, "CalculateCompoundInterests", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"context"
+
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/grpcserver"
+	"github.com/renato0307/canivete-api/pkg/limits"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	canivetev1 "github.com/renato0307/canivete-api/pkg/pb/canivete/v1"
+	"github.com/renato0307/canivete-core/interface/finance"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// grpcService serves the finance tools over gRPC.
+type grpcService struct {
+	canivetev1.UnimplementedFinanceServiceServer
+	f finance.Interface
+}
+
+// RegisterGrpcService registers the gRPC service of the finance tools.
+func RegisterGrpcService(f finance.Interface, registrar grpc.ServiceRegistrar) {
+	canivetev1.RegisterFinanceServiceServer(registrar, &grpcService{f: f})
+}
+
+func (s *grpcService) CalculateCompoundInterests(ctx context.Context, req *canivetev1.CalculateCompoundInterestsRequest) (*canivetev1.CalculateCompoundInterestsResponse, error) {
+	// the same rules as the http requests
+	input := calculateCompoundInterestsInput{
+		InterestRate:               req.InterestRate,
+		CompoundPeriods:            req.CompoundPeriods,
+		InvestAmount:               req.InvestAmount,
+		RegularContributions:       req.RegularContributions,
+		RegularContributionsPeriod: req.RegularContributionsPeriod,
+		Time:                       req.Time,
+	}
+	err := validate.Struct(input)
+	if err != nil {
+		logging.FromContext(ctx).Debugw("bad request received for compound interests calculation", "error", err.Error())
+		return nil, grpcserver.Error(codes.InvalidArgument, apierrors.CodeValidationFailed, err.Error())
+	}
+
+	var output finance.CompoundInterestsOutput
+	err = limits.Call(ctx, func() (err error) {
+		output, err = s.f.CalculateCompoundInterests(
+			input.InvestAmount,
+			input.CompoundPeriods,
+			input.Time,
+			input.RegularContributions,
+			input.RegularContributionsPeriod,
+			input.InterestRate,
+		)
+		return err
+	})
+	metrics.CompoundInterestsCalculated.WithLabelValues(metrics.Outcome(err)).Inc()
+	if limits.Exceeded(err) {
+		return nil, grpcserver.Timeout()
+	}
+	if err != nil {
+		logging.FromContext(ctx).Debugw("error calculating compound interests", "error", err.Error())
+		return nil, grpcserver.Error(codes.Internal, apierrors.CodeCalculationFailed, err.Error())
+	}
+
+	resp := &canivetev1.CalculateCompoundInterestsResponse{Total: detailResponse(output.Total)}
+	for _, entry := range output.History {
+		resp.History = append(resp.History, &canivetev1.CompoundInterestsHistoryEntry{
+			Period: entry.Period,
+			Totals: detailResponse(entry.Totals),
+		})
+	}
+
+	return resp, nil
+}
+
+func detailResponse(detail finance.CompoundInterestsDetailOutput) *canivetev1.CompoundInterestsDetail {
+	return &canivetev1.CompoundInterestsDetail{
+		FinalAmount:        detail.FinalAmount,
+		TotalContributions: detail.TotalContributions,
+		Interests:          detail.Interests,
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"context"
+	"testing"
+
+	canivetev1 "github.com/renato0307/canivete-api/pkg/pb/canivete/v1"
+	"github.com/renato0307/canivete-core/interface/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcCalculateCompoundInterests(t *testing.T) {
+	// arrange
+	output := finance.CompoundInterestsOutput{
+		Total: finance.CompoundInterestsDetailOutput{FinalAmount: 5416, TotalContributions: 5000, Interests: 416},
+		History: []finance.CompoundInterestsHistoryEntryOutput{
+			{Period: "1", Totals: finance.CompoundInterestsDetailOutput{FinalAmount: 5416, TotalContributions: 5000, Interests: 416}},
+		},
+	}
+	serviceMock := finance.MockInterface{}
+	serviceMock.On("CalculateCompoundInterests", 5000.0, 12.0, 1.0, 0.0, 12.0, 8.0).Return(output, nil)
+	s := grpcService{f: &serviceMock}
+	req := &canivetev1.CalculateCompoundInterestsRequest{
+		InvestAmount:               5000,
+		CompoundPeriods:            12,
+		Time:                       1,
+		InterestRate:               8,
+		RegularContributionsPeriod: 12,
+	}
+
+	// act
+	resp, err := s.CalculateCompoundInterests(context.Background(), req)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, 5416.0, resp.Total.FinalAmount)
+	assert.Len(t, resp.History, 1)
+	assert.Equal(t, "1", resp.History[0].Period)
+	serviceMock.AssertExpectations(t)
+}
+
+func TestGrpcCalculateCompoundInterestsWithInvalidRequest(t *testing.T) {
+	// arrange
+	serviceMock := finance.MockInterface{}
+	serviceMock.On("CalculateCompoundInterests", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	s := grpcService{f: &serviceMock}
+
+	// act
+	_, err := s.CalculateCompoundInterests(context.Background(), &canivetev1.CalculateCompoundInterestsRequest{InvestAmount: 5000})
+
+	// assert
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	serviceMock.AssertNotCalled(t, "CalculateCompoundInterests", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/cache"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/openapi"
+	"github.com/renato0307/canivete-api/pkg/render"
+	"github.com/renato0307/canivete-api/pkg/versioning"
+	"github.com/renato0307/canivete-core/interface/finance"
+)
+
+// validateV2 validates the inputs of the routes of v2, naming the invalid
+// fields by their JSON name.
+var validateV2 = versioning.NewValidator()
+
+// compoundInterestsInput is the body of the v2 compound interests tool,
+// the fields of v1 in camel case.
+type compoundInterestsInput struct {
+	InterestRate               float64 `json:"interestRate" validate:"required"`
+	CompoundPeriods            float64 `json:"compoundPeriods" validate:"required"`
+	InvestAmount               float64 `json:"investAmount" validate:"required"`
+	RegularContributions       float64 `json:"regularContributions"`
+	RegularContributionsPeriod float64 `json:"regularContributionsPeriod" validate:"gt=0,required_with=RegularContributions"`
+	Time                       float64 `json:"time" validate:"required"`
+}
+
+type compoundInterestsDetail struct {
+	FinalAmount        float64 `json:"finalAmount" yaml:"finalAmount" xml:"finalAmount"`
+	TotalContributions float64 `json:"totalContributions" yaml:"totalContributions" xml:"totalContributions"`
+	Interests          float64 `json:"interests" yaml:"interests" xml:"interests"`
+}
+
+type compoundInterestsHistoryEntry struct {
+	Period string                  `json:"period" yaml:"period" xml:"period"`
+	Totals compoundInterestsDetail `json:"totals" yaml:"totals" xml:"totals"`
+}
+
+type compoundInterestsOutput struct {
+	Total   compoundInterestsDetail         `json:"total" yaml:"total" xml:"total"`
+	History []compoundInterestsHistoryEntry `json:"history" yaml:"history" xml:"history"`
+}
+
+// newCompoundInterestsOutput returns the v2 answer of a calculation.
+func newCompoundInterestsOutput(output finance.CompoundInterestsOutput) compoundInterestsOutput {
+	v2 := compoundInterestsOutput{
+		Total:   compoundInterestsDetail(output.Total),
+		History: []compoundInterestsHistoryEntry{},
+	}
+	for _, entry := range output.History {
+		v2.History = append(v2.History, compoundInterestsHistoryEntry{
+			Period: entry.Period,
+			Totals: compoundInterestsDetail(entry.Totals),
+		})
+	}
+
+	return v2
+}
+
+// SetRouterGroupV2 mounts the v2 routes of the group, taking and returning
+// JSON objects in camel case.
+func SetRouterGroupV2(f finance.Interface, base *gin.RouterGroup) *gin.RouterGroup {
+	financeGroup := base.Group("/finance")
+	{
+		financeGroup.POST("/calculate-compound-interests", postCalculateCompoundInterestsV2(f))
+	}
+
+	openapi.Describe(financeGroup, http.MethodPost, "/calculate-compound-interests", openapi.Operation{
+		Summary: "Calculates compound interests with regular contributions",
+		Request: &openapi.Body{
+			Type: compoundInterestsInput{},
+			Example: compoundInterestsInput{
+				InterestRate:               8,
+				CompoundPeriods:            12,
+				InvestAmount:               5000,
+				RegularContributions:       100,
+				RegularContributionsPeriod: 12,
+				Time:                       2,
+			},
+		},
+		Response: openapi.Body{Type: compoundInterestsOutput{}},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	})
+
+	cache.SetPolicy(financeGroup, "/calculate-compound-interests", cache.Policy{
+		CacheControl:  "public, max-age=86400",
+		Deterministic: true,
+	})
+
+	return financeGroup
+}
+
+func postCalculateCompoundInterestsV2(f finance.Interface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			apierrors.Abort(c, apierrors.Internal(apierrors.CodeInternal, "unexpected error reading the body"))
+			return
+		}
+
+		input := compoundInterestsInput{}
+		err = json.Unmarshal(body, &input)
+		if err != nil {
+			apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidBody, "request body is invalid: "+err.Error()))
+			return
+		}
+
+		err = validateV2.Struct(input)
+		if err != nil {
+			logging.FromContext(c).Debugw("bad request received for compound interests calculation", "error", err.Error())
+			apierrors.Abort(c, apierrors.Validation(err))
+			return
+		}
+
+		output, ok := calculate(c, f, calculateCompoundInterestsInput(input))
+		if !ok {
+			return
+		}
+
+		render.RenderCsv(c, http.StatusOK, newCompoundInterestsOutput(output), func() string { return historyCsv(output) })
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package finance
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-core/interface/finance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func setupGinV2(serviceMock *finance.MockInterface) *gin.Engine {
+	r := gin.Default()
+	v2 := r.Group("/v2")
+	SetRouterGroupV2(serviceMock, v2)
+
+	return r
+}
+
+func TestCalculateCompoundInterestsV2(t *testing.T) {
+	output := finance.CompoundInterestsOutput{
+		Total: finance.CompoundInterestsDetailOutput{
+			FinalAmount:        6660,
+			TotalContributions: 6200,
+			Interests:          460,
+		},
+		History: []finance.CompoundInterestsHistoryEntryOutput{
+			{
+				Period: "1",
+				Totals: finance.CompoundInterestsDetailOutput{
+					FinalAmount:        6660,
+					TotalContributions: 6200,
+					Interests:          460,
+				},
+			},
+		},
+	}
+
+	// arrange
+	serviceMock := finance.MockInterface{}
+	mockCall := serviceMock.On(
+		"CalculateCompoundInterests",
+		float64(5000),
+		float64(12),
+		float64(1),
+		float64(100),
+		float64(12),
+		float64(8),
+	)
+	mockCall.Return(output, nil)
+
+	r := setupGinV2(&serviceMock)
+	w := httptest.NewRecorder()
+	body := `{"interestRate": 8, "compoundPeriods": 12, "investAmount": 5000, "regularContributions": 100, "regularContributionsPeriod": 12, "time": 1}`
+	req, _ := http.NewRequest("POST", "/v2/finance/calculate-compound-interests", strings.NewReader(body))
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"total": {"finalAmount": 6660, "totalContributions": 6200, "interests": 460},
+		"history": [{"period": "1", "totals": {"finalAmount": 6660, "totalContributions": 6200, "interests": 460}}]
+	}`, w.Body.String())
+}
+
+func TestCalculateCompoundInterestsV2MissingRequired(t *testing.T) {
+	// arrange
+	serviceMock := finance.MockInterface{}
+	r := setupGinV2(&serviceMock)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v2/finance/calculate-compound-interests", strings.NewReader(`{"regularContributions": 100}`))
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeValidationFailed, apiError.Code)
+	assert.Contains(t, apiError.Errors, apierrors.FieldError{
+		Field:   "investAmount",
+		Rule:    "required",
+		Message: "investAmount is required",
+	})
+	assert.Contains(t, apiError.Errors, apierrors.FieldError{
+		Field:   "regularContributionsPeriod",
+		Rule:    "gt",
+		Param:   "0",
+		Message: "regularContributionsPeriod must be greater than 0",
+	})
+	serviceMock.AssertNotCalled(t, "CalculateCompoundInterests", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNewCompoundInterestsOutputWithoutHistory(t *testing.T) {
+	// act
+	output := newCompoundInterestsOutput(finance.CompoundInterestsOutput{})
+
+	// assert
+	encoded, err := json.Marshal(output)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"total": {"finalAmount": 0, "totalContributions": 0, "interests": 0}, "history": []}`, string(encoded))
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package graphqlserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/certs"
+	"github.com/renato0307/canivete-api/pkg/requestid"
+)
+
+// auditKey is the key of the record of the request in the context of the
+// resolvers.
+type auditKey struct{}
+
+// withAuditRecord returns a context carrying the fields of the records of
+// the request, when the calls are recorded.
+func withAuditRecord(ctx context.Context, c *gin.Context) context.Context {
+	if audit.Default() == nil {
+		return ctx
+	}
+
+	record := audit.Record{
+		RequestId: requestid.Get(c),
+		Client:    c.ClientIP(),
+		Transport: audit.TransportGraphql,
+		Method:    c.Request.Method,
+	}
+	if identity, ok := auth.GetIdentity(c); ok {
+		record.Caller = identity.Name
+		record.AuthMethod = identity.Method
+	}
+	if identity, ok := certs.GetClientIdentity(c); ok {
+		record.ClientCert = identity.Name()
+	}
+
+	return context.WithValue(ctx, auditKey{}, record)
+}
+
+// audited records the calls of the field with the default auditor, with
+// its arguments as inputs. The route of the records is the coordinate of
+// the field, like Query.jwt.
+func audited(coordinate string, field *graphql.Field) *graphql.Field {
+	resolve := field.Resolve
+	field.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+		record, ok := p.Context.Value(auditKey{}).(audit.Record)
+		auditor := audit.Default()
+		if !ok || auditor == nil {
+			return resolve(p)
+		}
+
+		start := time.Now()
+		result, err := resolve(p)
+
+		status := http.StatusOK
+		if err != nil {
+			status = http.StatusInternalServerError
+			var apiError fieldError
+			if errors.As(err, &apiError) {
+				status = apiError.apiError.Status
+			}
+		}
+		record.Time = start.UTC()
+		record.Route = coordinate
+		record.Status = strconv.Itoa(status)
+		record.Outcome = audit.Outcome(status)
+		record.DurationMs = time.Since(start).Milliseconds()
+		auditor.Record(record, arguments(p.Args))
+
+		return result, err
+	}
+
+	return field
+}
+
+// arguments returns the arguments of a field as strings, the input objects
+// encoded in JSON.
+func arguments(args map[string]interface{}) map[string]string {
+	inputs := map[string]string{}
+	for name, value := range args {
+		if text, ok := value.(string); ok {
+			inputs[name] = text
+			continue
+		}
+		encoded, err := json.Marshal(value)
+		if err == nil {
+			inputs[name] = string(encoded)
+		}
+	}
+
+	return inputs
+}
@@ -0,0 +1,94 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package graphqlserver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+)
+
+// checkLimits rejects the operations nesting their fields deeper than
+// maxDepth or selecting more than maxComplexity fields, fragments
+// included. The introspection fields are not counted, so GraphiQL can
+// always read the schema.
+func checkLimits(document *ast.Document, operation *ast.OperationDefinition, maxDepth, maxComplexity int) error {
+	fragments := map[string]*ast.FragmentDefinition{}
+	for _, definition := range document.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	depth, complexity := measure(operation.SelectionSet, fragments, map[string]bool{})
+	if depth > maxDepth {
+		detail := fmt.Sprintf("query depth %d exceeds the maximum of %d", depth, maxDepth)
+		return Error(apierrors.BadRequest(apierrors.CodeQueryTooComplex, detail))
+	}
+	if complexity > maxComplexity {
+		detail := fmt.Sprintf("query complexity %d exceeds the maximum of %d", complexity, maxComplexity)
+		return Error(apierrors.BadRequest(apierrors.CodeQueryTooComplex, detail))
+	}
+
+	return nil
+}
+
+// measure returns the depth and the number of fields of the selection set.
+// visiting holds the fragments being expanded, to stop on cycles.
+func measure(selectionSet *ast.SelectionSet, fragments map[string]*ast.FragmentDefinition, visiting map[string]bool) (depth, complexity int) {
+	if selectionSet == nil {
+		return 0, 0
+	}
+
+	for _, selection := range selectionSet.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name.Value, "__") {
+				continue
+			}
+			childDepth, childComplexity := measure(selection.SelectionSet, fragments, visiting)
+			depth = max(depth, childDepth+1)
+			complexity += childComplexity + 1
+		case *ast.InlineFragment:
+			childDepth, childComplexity := measure(selection.SelectionSet, fragments, visiting)
+			depth = max(depth, childDepth)
+			complexity += childComplexity
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := fragments[name]
+			if !ok || visiting[name] {
+				continue
+			}
+			visiting[name] = true
+			childDepth, childComplexity := measure(fragment.SelectionSet, fragments, visiting)
+			delete(visiting, name)
+			depth = max(depth, childDepth)
+			complexity += childComplexity
+		}
+	}
+
+	return depth, complexity
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
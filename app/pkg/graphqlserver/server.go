@@ -0,0 +1,368 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package graphqlserver
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-api/pkg/openapi"
+	"github.com/renato0307/canivete-api/pkg/ratelimit"
+)
+
+//go:embed graphiql.html
+var graphiqlHtml string
+
+var graphiqlTemplate = template.Must(template.New("graphiql").Parse(graphiqlHtml))
+
+// Request is a GraphQL request, as sent in the body of a POST.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response is the result of a GraphQL request.
+type Response struct {
+	Data   interface{}                `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+// Server serves the tools of the service groups over GraphQL. The groups
+// add their fields to the schema before the endpoint is mounted.
+type Server struct {
+	cfg         config.GraphqlConfig
+	authEnabled bool
+	toggles     Toggles
+	limiter     *ratelimit.Limiter
+	// routes are the routes whose cost the fields take, by coordinate.
+	routes    map[string]string
+	groups    []string
+	queries   graphql.Fields
+	mutations graphql.Fields
+}
+
+// Toggles tells if the fields, by coordinate like Query.uuid, can be
+// called, like admin.Toggles.
+type Toggles interface {
+	Enabled(name string) bool
+}
+
+// Group is where a service group adds its fields to the schema.
+type Group struct {
+	s     *Server
+	scope string
+}
+
+// New creates a server with an empty schema. When auth is enabled, the
+// fields of each group require the scope of the group.
+func New(cfg config.GraphqlConfig, authEnabled bool) *Server {
+	return &Server{
+		cfg:         cfg,
+		authEnabled: authEnabled,
+		queries:     graphql.Fields{},
+		mutations:   graphql.Fields{},
+	}
+}
+
+// SetToggles makes the fields disabled in the toggles fail, without
+// resolving them.
+func (s *Server) SetToggles(toggles Toggles) {
+	s.toggles = toggles
+}
+
+// SetRateLimiter makes each resolved field take the cost of its route in
+// routes, by coordinate like Query.uuid, from the bucket of the caller, so
+// the aliases of a field in a single request are charged each.
+func (s *Server) SetRateLimiter(limiter *ratelimit.Limiter, routes map[string]string) {
+	s.limiter = limiter
+	s.routes = routes
+}
+
+// Group returns where the service group with the scope adds its fields.
+func (s *Server) Group(scope string) *Group {
+	s.groups = append(s.groups, scope)
+	return &Group{s: s, scope: scope}
+}
+
+// Query adds a field to the Query type.
+func (g *Group) Query(name string, field *graphql.Field) {
+	g.s.queries[name] = audited("Query."+name, g.guard("Query."+name, field))
+}
+
+// Mutation adds a field to the Mutation type.
+func (g *Group) Mutation(name string, field *graphql.Field) {
+	g.s.mutations[name] = audited("Mutation."+name, g.guard("Mutation."+name, field))
+}
+
+// guard makes the field fail when the caller lacks the scope of the group,
+// when the field is disabled in the toggles or when the bucket of the
+// caller has not enough tokens for it.
+func (g *Group) guard(coordinate string, field *graphql.Field) *graphql.Field {
+	resolve := field.Resolve
+	field.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+		if g.s.authEnabled {
+			identity, _ := auth.FromContext(p.Context)
+			if !identity.HasScope(g.scope) {
+				detail := "the credentials do not grant access to " + g.scope
+				return nil, Error(apierrors.New(http.StatusForbidden, apierrors.CodeForbidden, detail))
+			}
+		}
+		if g.s.toggles != nil && !g.s.toggles.Enabled(coordinate) {
+			return nil, Error(apierrors.New(http.StatusServiceUnavailable, apierrors.CodeRouteDisabled, "the field is disabled"))
+		}
+		if err := g.s.takeTokens(p.Context, coordinate); err != nil {
+			return nil, err
+		}
+
+		return resolve(p)
+	}
+
+	return field
+}
+
+// rateLimitKey is the key of the bucket of the caller in the context of
+// the resolvers.
+type rateLimitKey struct{}
+
+// takeTokens takes the cost of the route of the field from the bucket of
+// the caller.
+func (s *Server) takeTokens(ctx context.Context, coordinate string) error {
+	key, ok := ctx.Value(rateLimitKey{}).(string)
+	if s.limiter == nil || !ok {
+		return nil
+	}
+
+	route, ok := s.routes[coordinate]
+	if !ok {
+		route = coordinate
+	}
+	result, err := s.limiter.Take(ctx, key, route)
+	if err != nil {
+		// the store being down must not take the api down
+		logging.FromContext(ctx).Warnw("error taking rate limit tokens, allowing the field", "error", err.Error())
+		return nil
+	}
+	if !result.Allowed {
+		metrics.RateLimitedRequests.WithLabelValues(coordinate).Inc()
+		apiError, _ := ratelimit.Refused(result)
+		return Error(apiError)
+	}
+
+	return nil
+}
+
+// Schema builds the schema of the fields added by the groups. The Query
+// type always has the groups field, so it is never empty.
+func (s *Server) Schema() (graphql.Schema, error) {
+	groups := s.groups
+	queries := graphql.Fields{
+		"groups": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Description: "The service groups served",
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return groups, nil
+			},
+		},
+	}
+	for name, field := range s.queries {
+		queries[name] = field
+	}
+
+	schemaConfig := graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: queries}),
+	}
+	if len(s.mutations) > 0 {
+		schemaConfig.Mutation = graphql.NewObject(graphql.ObjectConfig{Name: "Mutation", Fields: s.mutations})
+	}
+
+	return graphql.NewSchema(schemaConfig)
+}
+
+// SetRouterGroup adds the /graphql endpoint to the base group, answering
+// queries over GET and POST and serving GraphiQL to the browsers when the
+// playground is enabled.
+func (s *Server) SetRouterGroup(base *gin.RouterGroup) (*gin.RouterGroup, error) {
+	schema, err := s.Schema()
+	if err != nil {
+		return nil, err
+	}
+
+	base.GET("/graphql", s.getGraphql(schema))
+	base.POST("/graphql", s.postGraphql(schema))
+
+	openapi.Describe(base, http.MethodGet, "/graphql", openapi.Operation{
+		Summary: "Calls the tools with a GraphQL query given in the query string",
+		Description: "The query, variables and operationName parameters hold the request. " +
+			"Mutations must be sent with POST. Browsers get GraphiQL when the playground is enabled.",
+		Response: openapi.Body{Type: Response{}},
+		Errors:   []int{http.StatusBadRequest, http.StatusMethodNotAllowed},
+	})
+	openapi.Describe(base, http.MethodPost, "/graphql", openapi.Operation{
+		Summary: "Calls the tools with a GraphQL query",
+		Request: &openapi.Body{
+			Type:    Request{},
+			Example: Request{Query: "{ uuid fromUnix(unixTimestamp: 1638964800) { utcTimestamp } }"},
+		},
+		Response: openapi.Body{Type: Response{}},
+		Errors:   []int{http.StatusBadRequest},
+	})
+
+	return base, nil
+}
+
+func (s *Server) getGraphql(schema graphql.Schema) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := c.Query("query")
+		if query == "" && s.cfg.Playground && strings.Contains(c.GetHeader("Accept"), "text/html") {
+			openapi.RenderPage(c, graphiqlTemplate)
+			return
+		}
+
+		request := Request{Query: query, OperationName: c.Query("operationName")}
+		if variables := c.Query("variables"); variables != "" {
+			err := json.Unmarshal([]byte(variables), &request.Variables)
+			if err != nil {
+				apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidBody, "variables are invalid: "+err.Error()))
+				return
+			}
+		}
+
+		s.serve(c, schema, request, false)
+	}
+}
+
+func (s *Server) postGraphql(schema graphql.Schema) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			apierrors.Abort(c, apierrors.Internal(apierrors.CodeInternal, "unexpected error reading the body"))
+			return
+		}
+
+		request := Request{}
+		err = json.Unmarshal(body, &request)
+		if err != nil {
+			apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidBody, "request body is invalid: "+err.Error()))
+			return
+		}
+
+		s.serve(c, schema, request, true)
+	}
+}
+
+// serve runs the request. The requests that cannot run are answered with
+// 400 (BadRequest) and the others with 200 (OK), the errors of the fields
+// going along with the data.
+func (s *Server) serve(c *gin.Context, schema graphql.Schema, request Request, mutationsAllowed bool) {
+	if strings.TrimSpace(request.Query) == "" {
+		apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidBody, "query is required"))
+		return
+	}
+
+	document, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(request.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, Response{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+
+	validation := graphql.ValidateDocument(&schema, document, nil)
+	if !validation.IsValid {
+		c.JSON(http.StatusBadRequest, Response{Errors: validation.Errors})
+		return
+	}
+
+	operation := findOperation(document, request.OperationName)
+	if operation == nil {
+		c.JSON(http.StatusBadRequest, Response{Errors: gqlerrors.FormatErrors(
+			gqlerrors.NewFormattedError("operation " + request.OperationName + " not found"),
+		)})
+		return
+	}
+	if operation.Operation == ast.OperationTypeMutation && !mutationsAllowed {
+		c.Header("Allow", http.MethodPost)
+		apierrors.Abort(c, apierrors.New(http.StatusMethodNotAllowed, apierrors.CodeMethodNotAllowed, "mutations must be sent with POST"))
+		return
+	}
+
+	err = checkLimits(document, operation, s.cfg.MaxDepth, s.cfg.MaxComplexity)
+	if err != nil {
+		logging.FromContext(c).Debugw("graphql query rejected", "error", err.Error())
+		c.JSON(http.StatusBadRequest, Response{Errors: []gqlerrors.FormattedError{formatError(err)}})
+		return
+	}
+
+	ctx := logging.NewContext(c.Request.Context(), logging.FromContext(c))
+	identity, authenticated := auth.GetIdentity(c)
+	if authenticated {
+		ctx = auth.NewContext(ctx, identity)
+	}
+	ctx = context.WithValue(ctx, rateLimitKey{}, ratelimit.Key(identity, authenticated, c.ClientIP()))
+	ctx = withAuditRecord(ctx, c)
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        schema,
+		AST:           document,
+		OperationName: request.OperationName,
+		Args:          request.Variables,
+		Context:       ctx,
+	})
+
+	c.JSON(http.StatusOK, Response{Data: result.Data, Errors: result.Errors})
+}
+
+// findOperation returns the operation with the name, or the only operation
+// of the document when the name is empty.
+func findOperation(document *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = operation
+			continue
+		}
+		if operation.Name != nil && operation.Name.Value == name {
+			return operation
+		}
+	}
+
+	return found
+}
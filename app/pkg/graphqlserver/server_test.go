@@ -0,0 +1,427 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package graphqlserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+var testConfig = config.GraphqlConfig{Enabled: true, MaxDepth: 3, MaxComplexity: 5, Playground: true}
+
+type testResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Path       []interface{}          `json:"path"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+var pairType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Pair",
+	Fields: graphql.Fields{
+		"left":  &graphql.Field{Type: graphql.String},
+		"right": &graphql.Field{Type: graphql.String},
+	},
+})
+
+func setupGin(t *testing.T, cfg config.GraphqlConfig, identity *auth.Identity) *gin.Engine {
+	s := New(cfg, identity != nil)
+	g := s.Group("tests")
+	g.Query("echo", &graphql.Field{
+		Type: graphql.String,
+		Args: graphql.FieldConfigArgument{"value": &graphql.ArgumentConfig{Type: graphql.String}},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return p.Args["value"], nil
+		},
+	})
+	g.Query("fail", &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return nil, Error(apierrors.BadRequest(apierrors.CodeInvalidToken, "token is invalid"))
+		},
+	})
+	g.Query("pair", &graphql.Field{
+		Type: pairType,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"left": "l", "right": "r"}, nil
+		},
+	})
+	g.Mutation("touch", &graphql.Field{
+		Type: graphql.Boolean,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return true, nil
+		},
+	})
+
+	r := gin.New()
+	v1 := r.Group("/v1")
+	if identity != nil {
+		v1.Use(func(c *gin.Context) {
+			auth.SetIdentity(c, *identity)
+		})
+	}
+	_, err := s.SetRouterGroup(v1)
+	assert.Nil(t, err)
+
+	return r
+}
+
+func postGraphql(r *gin.Engine, query string, variables map[string]interface{}) (*httptest.ResponseRecorder, testResponse) {
+	body, _ := json.Marshal(Request{Query: query, Variables: variables})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/v1/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	response := testResponse{}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	return w, response
+}
+
+func TestPostGraphql(t *testing.T) {
+	// arrange
+	r := setupGin(t, testConfig, nil)
+
+	// act
+	w, response := postGraphql(r, `query ($v: String) { echo(value: $v) groups }`, map[string]interface{}{"v": "hello"})
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, response.Errors)
+	assert.Equal(t, "hello", response.Data["echo"])
+	assert.Equal(t, []interface{}{"tests"}, response.Data["groups"])
+}
+
+func TestPostGraphqlReportsTheErrorsOfTheFields(t *testing.T) {
+	// arrange
+	r := setupGin(t, testConfig, nil)
+
+	// act
+	w, response := postGraphql(r, `{ echo(value: "ok") fail }`, nil)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", response.Data["echo"])
+	assert.Nil(t, response.Data["fail"])
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, "token is invalid", response.Errors[0].Message)
+		assert.Equal(t, []interface{}{"fail"}, response.Errors[0].Path)
+		assert.Equal(t, apierrors.CodeInvalidToken, response.Errors[0].Extensions["code"])
+		assert.Equal(t, float64(http.StatusBadRequest), response.Errors[0].Extensions["status"])
+	}
+}
+
+func TestPostGraphqlWithInvalidQueries(t *testing.T) {
+	tests := []string{
+		`{ echo(`,
+		`{ unknown }`,
+		`{ pair }`,
+	}
+
+	for _, query := range tests {
+		// arrange
+		r := setupGin(t, testConfig, nil)
+
+		// act
+		w, response := postGraphql(r, query, nil)
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, w.Code, "expected 400 for %s", query)
+		assert.NotEmpty(t, response.Errors, "expected errors for %s", query)
+	}
+}
+
+func TestPostGraphqlBoundsTheQueries(t *testing.T) {
+	tests := []string{
+		`{ a: echo b: echo c: echo d: echo e: echo f: echo }`,
+		`{ pair { ...sides } p2: pair { ...sides } } fragment sides on Pair { left right }`,
+	}
+
+	for _, query := range tests {
+		// arrange
+		r := setupGin(t, testConfig, nil)
+
+		// act
+		w, response := postGraphql(r, query, nil)
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, w.Code, "expected 400 for %s", query)
+		if assert.Len(t, response.Errors, 1, "expected an error for %s", query) {
+			assert.Equal(t, apierrors.CodeQueryTooComplex, response.Errors[0].Extensions["code"])
+		}
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	// arrange
+	r := setupGin(t, config.GraphqlConfig{MaxDepth: 1, MaxComplexity: 100}, nil)
+
+	// act
+	deep, _ := postGraphql(r, `{ pair { left } }`, nil)
+	introspection, _ := postGraphql(r, `{ __schema { types { name fields { name type { name ofType { name } } } } } }`, nil)
+
+	// assert
+	assert.Equal(t, http.StatusBadRequest, deep.Code)
+	assert.Equal(t, http.StatusOK, introspection.Code)
+}
+
+func TestGetGraphql(t *testing.T) {
+	// arrange
+	r := setupGin(t, testConfig, nil)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/graphql?query="+url.QueryEscape(`{ echo(value: "hi") }`), nil)
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"echo":"hi"}}`, w.Body.String())
+}
+
+func TestGetGraphqlRejectsMutations(t *testing.T) {
+	// arrange
+	r := setupGin(t, testConfig, nil)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/graphql?query="+url.QueryEscape(`mutation { touch }`), nil)
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, http.MethodPost, w.Header().Get("Allow"))
+}
+
+func TestGetGraphqlServesThePlayground(t *testing.T) {
+	// arrange
+	enabled := setupGin(t, testConfig, nil)
+	disabledConfig := testConfig
+	disabledConfig.Playground = false
+	disabled := setupGin(t, disabledConfig, nil)
+
+	for _, r := range []*gin.Engine{enabled, disabled} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/v1/graphql", nil)
+		req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		if r == enabled {
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), "GraphiQL")
+		} else {
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+func TestPostGraphqlChecksTheScopes(t *testing.T) {
+	tests := []struct {
+		identity auth.Identity
+		allowed  bool
+	}{
+		{auth.Identity{Name: "ci", Scopes: []string{"tests"}}, true},
+		{auth.Identity{Name: "ci", Scopes: []string{config.ScopeAll}}, true},
+		{auth.Identity{Name: "ci", Scopes: []string{"other"}}, false},
+	}
+
+	for _, test := range tests {
+		// arrange
+		r := setupGin(t, testConfig, &test.identity)
+
+		// act
+		w, response := postGraphql(r, `{ echo(value: "hi") groups }`, nil)
+
+		// assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, []interface{}{"tests"}, response.Data["groups"])
+		if test.allowed {
+			assert.Empty(t, response.Errors)
+			assert.Equal(t, "hi", response.Data["echo"])
+		} else if assert.Len(t, response.Errors, 1) {
+			assert.Equal(t, apierrors.CodeForbidden, response.Errors[0].Extensions["code"])
+		}
+	}
+}
+
+// disabledFields are toggles disabling the fields they list.
+type disabledFields []string
+
+func (d disabledFields) Enabled(name string) bool {
+	for _, disabled := range d {
+		if disabled == name {
+			return false
+		}
+	}
+	return true
+}
+
+func TestPostGraphqlChecksTheToggles(t *testing.T) {
+	// arrange
+	ring := audit.NewRing(10)
+	audit.SetDefault(audit.New(config.Default().Audit, ring, audit.NewPolicies()))
+	defer audit.SetDefault(nil)
+	s := New(testConfig, false)
+	s.SetToggles(disabledFields{"Query.echo"})
+	g := s.Group("tests")
+	g.Query("echo", &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			t.Error("the disabled field was resolved")
+			return "called", nil
+		},
+	})
+	g.Query("other", &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return "called", nil
+		},
+	})
+	r := gin.New()
+	_, err := s.SetRouterGroup(r.Group("/v1"))
+	assert.Nil(t, err)
+
+	// act
+	w, response := postGraphql(r, `{ echo other }`, nil)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, response.Data["echo"])
+	assert.Equal(t, "called", response.Data["other"])
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, apierrors.CodeRouteDisabled, response.Errors[0].Extensions["code"])
+	}
+	records := ring.Records(func(record audit.Record) bool { return record.Route == "Query.echo" })
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "503", records[0].Status)
+	}
+}
+
+func TestPostGraphqlChargesEachResolvedField(t *testing.T) {
+	// arrange
+	costs := ratelimit.NewCosts()
+	costs.Set(gin.New().Group("/v1/tests"), "/echo", 2)
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{Rate: 0.1, Burst: 5}, costs, nil)
+	s := New(testConfig, false)
+	s.SetRateLimiter(limiter, map[string]string{"Query.echo": "/v1/tests/echo"})
+	g := s.Group("tests")
+	g.Query("echo", &graphql.Field{
+		Type:    graphql.String,
+		Args:    graphql.FieldConfigArgument{"value": &graphql.ArgumentConfig{Type: graphql.String}},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) { return p.Args["value"], nil },
+	})
+	r := gin.New()
+	_, err := s.SetRouterGroup(r.Group("/v1"))
+	assert.Nil(t, err)
+
+	// act
+	w, response := postGraphql(r, `{ a: echo(value: "a") b: echo(value: "b") c: echo(value: "c") groups }`, nil)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []interface{}{"tests"}, response.Data["groups"])
+	if assert.Len(t, response.Errors, 1) {
+		assert.Equal(t, apierrors.CodeRateLimited, response.Errors[0].Extensions["code"])
+	}
+}
+
+func TestPostGraphqlRecordsTheFields(t *testing.T) {
+	// arrange
+	ring := audit.NewRing(10)
+	cfg := config.Default().Audit
+	cfg.Routes = map[string]map[string]string{"Query.echo": {"value": audit.Keep}}
+	audit.SetDefault(audit.New(cfg, ring, audit.NewPolicies()))
+	defer audit.SetDefault(nil)
+	r := setupGin(t, testConfig, &auth.Identity{Name: "ci", Method: "api-key", Scopes: []string{"tests"}})
+
+	// act
+	postGraphql(r, `{ echo(value: "hi") fail groups }`, nil)
+
+	// assert
+	records := ring.Records(func(audit.Record) bool { return true })
+	assert.Len(t, records, 2)
+	routes := map[string]audit.Record{}
+	for _, record := range records {
+		routes[record.Route] = record
+	}
+	assert.Equal(t, "ci", routes["Query.echo"].Caller)
+	assert.Equal(t, audit.TransportGraphql, routes["Query.echo"].Transport)
+	assert.Equal(t, "200", routes["Query.echo"].Status)
+	assert.Equal(t, map[string]string{"value": "hi"}, routes["Query.echo"].Inputs)
+	assert.Equal(t, "400", routes["Query.fail"].Status)
+	assert.Equal(t, audit.OutcomeRejected, routes["Query.fail"].Outcome)
+}
+
+func TestPostGraphqlRecordsTheFieldsOutsideTheScopes(t *testing.T) {
+	// arrange
+	ring := audit.NewRing(10)
+	audit.SetDefault(audit.New(config.Default().Audit, ring, audit.NewPolicies()))
+	defer audit.SetDefault(nil)
+	r := setupGin(t, testConfig, &auth.Identity{Name: "ci", Method: "api-key", Scopes: []string{"other"}})
+
+	// act
+	postGraphql(r, `{ echo(value: "hi") }`, nil)
+
+	// assert
+	records := ring.Records(func(audit.Record) bool { return true })
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "ci", records[0].Caller)
+		assert.Equal(t, "Query.echo", records[0].Route)
+		assert.Equal(t, "403", records[0].Status)
+		assert.Equal(t, audit.OutcomeRejected, records[0].Outcome)
+	}
+}
+
+func TestLongScalar(t *testing.T) {
+	// arrange
+	values := []interface{}{int64(4102444800), 4102444800, float64(4102444800), 1.5, "1"}
+	expected := []interface{}{int64(4102444800), int64(4102444800), int64(4102444800), nil, nil}
+
+	for i, value := range values {
+		// act
+		coerced := coerceLong(value)
+
+		// assert
+		assert.Equal(t, expected[i], coerced)
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	// act
+	err := Timeout()
+
+	// assert
+	assert.Equal(t, apierrors.CodeTimeout, err.(fieldError).Extensions()["code"])
+}
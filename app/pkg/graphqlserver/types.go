@@ -0,0 +1,148 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package graphqlserver
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/limits"
+)
+
+// fieldError is an api error reported on the field that failed.
+type fieldError struct {
+	apiError apierrors.ApiError
+}
+
+func (e fieldError) Error() string {
+	return e.apiError.Detail
+}
+
+// Extensions gives the code of the error, like the problem details of the
+// REST endpoints.
+func (e fieldError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{
+		"code":   e.apiError.Code,
+		"status": e.apiError.Status,
+	}
+	if len(e.apiError.Errors) > 0 {
+		extensions["errors"] = e.apiError.Errors
+	}
+
+	return extensions
+}
+
+// Error makes the api error the error of a field, with its code in the
+// extensions of the GraphQL error.
+func Error(apiError apierrors.ApiError) error {
+	return fieldError{apiError: apiError}
+}
+
+// formatError formats an error raised outside the resolvers, keeping its
+// extensions.
+func formatError(err error) gqlerrors.FormattedError {
+	formatted := gqlerrors.FormatError(err)
+	if extended, ok := err.(gqlerrors.ExtendedError); ok {
+		formatted.Extensions = extended.Extensions()
+	}
+
+	return formatted
+}
+
+// Timeout is the error of the fields taking longer than the request.
+func Timeout() error {
+	return Error(limits.Timeout())
+}
+
+// Long is a 64-bit integer, for the values outside the 32 bits of Int,
+// like the unix timestamps.
+var Long = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Long",
+	Description: "A 64-bit integer",
+	Serialize:   coerceLong,
+	ParseValue:  coerceLong,
+	ParseLiteral: func(value ast.Value) interface{} {
+		if value, ok := value.(*ast.IntValue); ok {
+			if i, err := strconv.ParseInt(value.Value, 10, 64); err == nil {
+				return i
+			}
+		}
+		return nil
+	},
+})
+
+func coerceLong(value interface{}) interface{} {
+	switch value := value.(type) {
+	case int64:
+		return value
+	case int:
+		return int64(value)
+	case int32:
+		return int64(value)
+	case float64:
+		if value != math.Trunc(value) || math.Abs(value) > 1<<53 {
+			return nil
+		}
+		return int64(value)
+	}
+
+	return nil
+}
+
+// JSON is any JSON value, like the claims of a JWT.
+var JSON = graphql.NewScalar(graphql.ScalarConfig{
+	Name:         "JSON",
+	Description:  "Any JSON value",
+	Serialize:    func(value interface{}) interface{} { return value },
+	ParseValue:   func(value interface{}) interface{} { return value },
+	ParseLiteral: parseJSONLiteral,
+})
+
+func parseJSONLiteral(value ast.Value) interface{} {
+	switch value := value.(type) {
+	case *ast.ObjectValue:
+		object := map[string]interface{}{}
+		for _, field := range value.Fields {
+			object[field.Name.Value] = parseJSONLiteral(field.Value)
+		}
+		return object
+	case *ast.ListValue:
+		list := []interface{}{}
+		for _, item := range value.Values {
+			list = append(list, parseJSONLiteral(item))
+		}
+		return list
+	case *ast.IntValue:
+		i, _ := strconv.ParseInt(value.Value, 10, 64)
+		return i
+	case *ast.FloatValue:
+		f, _ := strconv.ParseFloat(value.Value, 64)
+		return f
+	case *ast.BooleanValue:
+		return value.Value
+	case *ast.StringValue:
+		return value.Value
+	case *ast.EnumValue:
+		return value.Value
+	}
+
+	return nil
+}
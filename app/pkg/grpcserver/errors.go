@@ -0,0 +1,46 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package grpcserver
+
+import (
+	"github.com/renato0307/canivete-api/pkg/limits"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo details of the errors.
+const ErrorDomain = "canivete-api"
+
+// Error returns a gRPC error with the code of the apierrors package, like
+// invalid-token, as the reason of its ErrorInfo details.
+func Error(c codes.Code, code string, detail string) error {
+	st := status.New(c, detail)
+	withDetails, err := st.WithDetails(&errdetails.ErrorInfo{Reason: code, Domain: ErrorDomain})
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+// Timeout returns the error of the calls whose tool did not complete in
+// time.
+func Timeout() error {
+	timeout := limits.Timeout()
+	return Error(codes.DeadlineExceeded, timeout.Code, timeout.Detail)
+}
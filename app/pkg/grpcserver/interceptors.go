@@ -0,0 +1,292 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/certs"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-api/pkg/ratelimit"
+	"github.com/renato0307/canivete-api/pkg/requestid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// requestIdInterceptor reads the request id from the metadata, or
+// generates one, and adds to the context a request logger carrying it and
+// the client certificate, like requestid.Middleware, logging.Middleware
+// and certs.Middleware.
+func requestIdInterceptor(header string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		received := ""
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(header); len(values) > 0 {
+				received = values[0]
+			}
+		}
+
+		ctx, id := requestid.NewContext(ctx, header, received)
+		grpc.SetHeader(ctx, metadata.Pairs(header, id))
+
+		fields := []interface{}{"route", info.FullMethod, "requestId", id}
+		if p, ok := peer.FromContext(ctx); ok {
+			fields = append(fields, "client", p.Addr.String())
+			if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+				if identity, ok := certs.IdentityFromState(&info.State); ok {
+					fields = append(fields, "clientCert", identity.Name(), "clientCertFingerprint", identity.Fingerprint)
+				}
+			}
+		}
+		ctx = logging.NewContext(ctx, logging.GetLogger().With(fields...))
+
+		return handler(ctx, req)
+	}
+}
+
+// recoveryInterceptor answers the calls which panic with an Internal error
+// instead of crashing the server.
+func recoveryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logging.FromContext(ctx).Errorw("recovered from a panic", "panic", recovered)
+			err = Error(codes.Internal, apierrors.CodeInternal, "unexpected error")
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// accessLogInterceptor logs an entry for each completed call, like
+// logging.AccessLog.
+func accessLogInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err)
+	fields := []interface{}{
+		"method", info.FullMethod,
+		"code", code.String(),
+		"duration", time.Since(start),
+	}
+
+	// the stack of the interceptor says nothing about the call
+	logger := logging.FromContext(ctx).Desugar().WithOptions(zap.AddStacktrace(zapcore.FatalLevel)).Sugar()
+	switch code {
+	case codes.OK:
+		logger.Infow("call completed", fields...)
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.DeadlineExceeded:
+		logger.Errorw("call completed", fields...)
+	default:
+		logger.Warnw("call completed", fields...)
+	}
+
+	return resp, err
+}
+
+// authInterceptor authenticates the calls to the service groups with the
+// credentials of the metadata, read like the http headers, and checks the
+// caller has the scope of the service group.
+func (s *Server) authInterceptor(authenticators []auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		scope, ok := s.scope(info.FullMethod)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		r, _ := http.NewRequestWithContext(ctx, http.MethodPost, info.FullMethod, nil)
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			for name, values := range md {
+				for _, value := range values {
+					r.Header.Add(name, value)
+				}
+			}
+		}
+
+		identity, err := auth.Authenticate(authenticators, r)
+		if errors.Is(err, auth.ErrNoCredentials) {
+			return nil, Error(codes.Unauthenticated, apierrors.CodeUnauthorized, "credentials are missing")
+		}
+		if err != nil {
+			logging.FromContext(ctx).Debugw("authentication failed", "error", err.Error())
+			return nil, Error(codes.Unauthenticated, apierrors.CodeUnauthorized, err.Error())
+		}
+
+		if caller, ok := ctx.Value(callerKey{}).(*auth.Identity); ok {
+			*caller = identity
+		}
+		ctx = logging.NewContext(ctx, logging.FromContext(ctx).With("caller", identity.Name))
+		ctx = auth.NewContext(ctx, identity)
+		if !identity.HasScope(scope) {
+			return nil, Error(codes.PermissionDenied, apierrors.CodeForbidden, "the credentials do not grant access to "+scope)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// callerKey is the key of the identity authInterceptor fills for
+// auditInterceptor, which runs before it.
+type callerKey struct{}
+
+// auditInterceptor records the calls of the service groups with the
+// default auditor, with the fields of the request as inputs. It runs
+// before authInterceptor, so the calls it refuses are recorded too, and
+// reads the caller it authenticated once the call is answered.
+func (s *Server) auditInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	auditor := audit.Default()
+	if _, ok := s.scope(info.FullMethod); !ok || auditor == nil {
+		return handler(ctx, req)
+	}
+
+	start := time.Now()
+	caller := &auth.Identity{}
+	resp, err := handler(context.WithValue(ctx, callerKey{}, caller), req)
+
+	code := status.Code(err)
+	record := audit.Record{
+		Time:       start.UTC(),
+		RequestId:  requestid.FromContext(ctx),
+		Transport:  audit.TransportGrpc,
+		Route:      info.FullMethod,
+		Status:     code.String(),
+		Outcome:    outcome(code),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if identity, ok := auth.FromContext(ctx); ok {
+		caller = &identity
+	}
+	record.Caller = caller.Name
+	record.AuthMethod = caller.Method
+	if p, ok := peer.FromContext(ctx); ok {
+		record.Client = p.Addr.String()
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if identity, ok := certs.IdentityFromState(&info.State); ok {
+				record.ClientCert = identity.Name()
+			}
+		}
+	}
+
+	inputs := map[string]string{}
+	if message, ok := req.(proto.Message); ok {
+		message.ProtoReflect().Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+			inputs[string(field.Name())] = value.String()
+			return true
+		})
+	}
+	auditor.Record(record, inputs)
+
+	return resp, err
+}
+
+// outcome returns the outcome of a call answered with the code, like
+// audit.Outcome for the http statuses.
+func outcome(code codes.Code) string {
+	switch code {
+	case codes.OK:
+		return audit.OutcomeSuccess
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.DeadlineExceeded, codes.Unimplemented:
+		return audit.OutcomeFailed
+	}
+
+	return audit.OutcomeRejected
+}
+
+// rateLimitInterceptor takes the cost of the route of the method from the
+// bucket of the caller, keyed like ratelimit.Middleware by its identity or
+// its ip, and answers ResourceExhausted when there are not enough tokens.
+func (s *Server) rateLimitInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	s.mu.Lock()
+	limiter := s.limiter
+	route, ok := s.routes[info.FullMethod]
+	s.mu.Unlock()
+
+	if _, grouped := s.scope(info.FullMethod); limiter == nil || !grouped {
+		return handler(ctx, req)
+	}
+	if !ok {
+		route = info.FullMethod
+	}
+
+	ip := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		ip = p.Addr.String()
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+	}
+	identity, authenticated := auth.FromContext(ctx)
+
+	result, err := limiter.Take(ctx, ratelimit.Key(identity, authenticated, ip), route)
+	if err != nil {
+		// the store being down must not take the api down
+		logging.FromContext(ctx).Warnw("error taking rate limit tokens, allowing the call", "error", err.Error())
+		return handler(ctx, req)
+	}
+	if !result.Allowed {
+		apiError, retryAfter := ratelimit.Refused(result)
+		grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(retryAfter)))
+		metrics.RateLimitedRequests.WithLabelValues(info.FullMethod).Inc()
+		return nil, Error(codes.ResourceExhausted, apiError.Code, apiError.Detail)
+	}
+
+	return handler(ctx, req)
+}
+
+// togglesInterceptor answers the calls of the disabled methods with
+// Unavailable, like the disabled routes.
+func (s *Server) togglesInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	s.mu.Lock()
+	toggles := s.toggles
+	s.mu.Unlock()
+
+	if toggles != nil && !toggles.Enabled(info.FullMethod) {
+		return nil, Error(codes.Unavailable, apierrors.CodeRouteDisabled, "the method is disabled")
+	}
+
+	return handler(ctx, req)
+}
+
+// limitsInterceptor gives the calls the timeout of their method, which can
+// be set like the routes, as /canivete.v1.ProgrammingService/NewUuid.
+func limitsInterceptor(cfg config.LimitsConfig) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		_, timeout := cfg.For(info.FullMethod)
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		return handler(ctx, req)
+	}
+}
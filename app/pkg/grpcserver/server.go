@@ -0,0 +1,184 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package grpcserver
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/ratelimit"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+var logger *zap.SugaredLogger = logging.GetLogger()
+
+// Server is a gRPC server for the service groups, with the gRPC health and
+// reflection services.
+type Server struct {
+	grpcServer *grpc.Server
+	health     *health.Server
+
+	mu sync.Mutex
+	// scopes are the scopes required by the services, by service name.
+	scopes  map[string]string
+	toggles Toggles
+	limiter *ratelimit.Limiter
+	// routes are the routes whose cost the methods take, by full name.
+	routes map[string]string
+}
+
+// Toggles tells if the methods, by full name like
+// /canivete.v1.ProgrammingService/NewUuid, can be called, like
+// admin.Toggles.
+type Toggles interface {
+	Enabled(name string) bool
+}
+
+// New creates a gRPC server using the configuration. The calls are
+// authenticated by the authenticators, when auth is enabled, recorded when
+// audit is enabled, rate limited, checked against the toggles and bounded
+// by the limits.
+// It serves TLS when tlsConfig is not nil.
+func New(cfg config.Config, authenticators []auth.Authenticator, tlsConfig *tls.Config) *Server {
+	s := &Server{
+		health: health.NewServer(),
+		scopes: map[string]string{},
+	}
+
+	interceptors := []grpc.UnaryServerInterceptor{
+		requestIdInterceptor(cfg.Server.RequestIdHeader),
+		recoveryInterceptor,
+	}
+	if cfg.Logging.AccessLog {
+		interceptors = append(interceptors, accessLogInterceptor)
+	}
+	if cfg.Audit.Enabled {
+		// before the authentication, so the refused calls are recorded
+		interceptors = append(interceptors, s.auditInterceptor)
+	}
+	if cfg.Auth.Enabled {
+		interceptors = append(interceptors, s.authInterceptor(authenticators))
+	}
+	interceptors = append(interceptors, s.rateLimitInterceptor, s.togglesInterceptor, limitsInterceptor(cfg.Limits))
+
+	options := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(interceptors...),
+		grpc.MaxRecvMsgSize(int(cfg.Limits.MaxBodySize)),
+	}
+	if tlsConfig != nil {
+		options = append(options, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	s.grpcServer = grpc.NewServer(options...)
+
+	healthpb.RegisterHealthServer(s.grpcServer, s.health)
+	if cfg.Grpc.Reflection {
+		reflection.Register(s.grpcServer)
+	}
+
+	return s
+}
+
+// SetToggles makes the methods disabled in the toggles fail with
+// Unavailable, without calling them.
+func (s *Server) SetToggles(toggles Toggles) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.toggles = toggles
+}
+
+// SetRateLimiter makes the calls of the service groups take the cost of
+// their route in routes, by method full name, from the bucket of the
+// caller, and fail with ResourceExhausted when it has not enough tokens.
+func (s *Server) SetRateLimiter(limiter *ratelimit.Limiter, routes map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.limiter = limiter
+	s.routes = routes
+}
+
+// Group returns the registrar of the services of a service group, which
+// require its scope when auth is enabled.
+func (s *Server) Group(scope string) grpc.ServiceRegistrar {
+	return &groupRegistrar{server: s, scope: scope}
+}
+
+type groupRegistrar struct {
+	server *Server
+	scope  string
+}
+
+func (g *groupRegistrar) RegisterService(desc *grpc.ServiceDesc, impl interface{}) {
+	g.server.mu.Lock()
+	g.server.scopes[desc.ServiceName] = g.scope
+	g.server.mu.Unlock()
+
+	g.server.grpcServer.RegisterService(desc, impl)
+	g.server.health.SetServingStatus(desc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+}
+
+// scope returns the scope required by the method, like
+// /canivete.v1.ProgrammingService/NewUuid, and false for the methods of
+// the services outside the service groups, like health.
+func (s *Server) scope(fullMethod string) (string, bool) {
+	service := strings.SplitN(strings.TrimPrefix(fullMethod, "/"), "/", 2)[0]
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scope, ok := s.scopes[service]
+	return scope, ok
+}
+
+// Serve accepts connections on the listener until Stop is called.
+func (s *Server) Serve(listener net.Listener) error {
+	logger.Infow("grpc server listening", "address", listener.Addr().String())
+	return s.grpcServer.Serve(listener)
+}
+
+// Stop marks the services as not serving, so the clients move to other
+// replicas, and waits for the calls in flight until ctx is done, when the
+// remaining calls are cancelled.
+func (s *Server) Stop(ctx context.Context) error {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,305 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/limits"
+	canivetev1 "github.com/renato0307/canivete-api/pkg/pb/canivete/v1"
+	"github.com/renato0307/canivete-api/pkg/ratelimit"
+	"github.com/renato0307/canivete-api/pkg/requestid"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeProgrammingService answers NewUuid with the request id of the call,
+// panics on DebugJwt with a "panic" token and blocks on the others.
+type fakeProgrammingService struct {
+	canivetev1.UnimplementedProgrammingServiceServer
+}
+
+func (fakeProgrammingService) NewUuid(ctx context.Context, req *canivetev1.NewUuidRequest) (*canivetev1.NewUuidResponse, error) {
+	return &canivetev1.NewUuidResponse{Uuid: requestid.FromContext(ctx)}, nil
+}
+
+func (fakeProgrammingService) DebugJwt(ctx context.Context, req *canivetev1.DebugJwtRequest) (*canivetev1.DebugJwtResponse, error) {
+	if req.Token == "panic" {
+		panic("fake panic")
+	}
+
+	err := limits.Call(ctx, func() error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	if err != nil {
+		return nil, Timeout()
+	}
+	return &canivetev1.DebugJwtResponse{}, nil
+}
+
+// disabledMethods are toggles disabling the methods they list.
+type disabledMethods []string
+
+func (d disabledMethods) Enabled(name string) bool {
+	for _, disabled := range d {
+		if disabled == name {
+			return false
+		}
+	}
+	return true
+}
+
+func setupServer(t *testing.T, cfg config.Config, options ...func(s *Server)) *grpc.ClientConn {
+	var authenticators []auth.Authenticator
+	if cfg.Auth.Enabled {
+		apiKeys, err := auth.NewApiKeyAuthenticator([]config.ApiKeyConfig{
+			{Name: "ci", Hash: auth.HashApiKey("ci-key"), Scopes: []string{config.GroupProgramming}},
+			{Name: "finance", Hash: auth.HashApiKey("finance-key"), Scopes: []string{config.GroupFinance}},
+		})
+		assert.Nil(t, err)
+		authenticators = append(authenticators, apiKeys)
+	}
+
+	s := New(cfg, authenticators, nil)
+	for _, option := range options {
+		option(s)
+	}
+	canivetev1.RegisterProgrammingServiceServer(s.Group(config.GroupProgramming), fakeProgrammingService{})
+
+	listener := bufconn.Listen(1 << 20)
+	go s.Serve(listener)
+	t.Cleanup(func() { s.Stop(context.Background()) })
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithInsecure(),
+	)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func testConfig() config.Config {
+	cfg := config.Default()
+	cfg.Logging.AccessLog = false
+	return cfg
+}
+
+func reason(err error) string {
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+
+	return ""
+}
+
+func TestServerPropagatesTheRequestId(t *testing.T) {
+	// arrange
+	conn := setupServer(t, testConfig())
+	client := canivetev1.NewProgrammingServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "fake-request-id")
+	var header metadata.MD
+
+	// act
+	resp, err := client.NewUuid(ctx, &canivetev1.NewUuidRequest{}, grpc.Header(&header))
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "fake-request-id", resp.Uuid)
+	assert.Equal(t, []string{"fake-request-id"}, header.Get("x-request-id"))
+}
+
+func TestServerAuthenticatesTheServiceGroups(t *testing.T) {
+	// arrange
+	cfg := testConfig()
+	cfg.Auth.Enabled = true
+	conn := setupServer(t, cfg)
+	client := canivetev1.NewProgrammingServiceClient(conn)
+	call := func(apiKey string) error {
+		ctx := context.Background()
+		if apiKey != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", apiKey)
+		}
+		_, err := client.NewUuid(ctx, &canivetev1.NewUuidRequest{})
+		return err
+	}
+
+	// act
+	missing := call("")
+	invalid := call("wrong-key")
+	forbidden := call("finance-key")
+	allowed := call("ci-key")
+	health, healthErr := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{
+		Service: canivetev1.ProgrammingService_ServiceDesc.ServiceName,
+	})
+
+	// assert
+	assert.Equal(t, codes.Unauthenticated, status.Code(missing))
+	assert.Equal(t, apierrors.CodeUnauthorized, reason(missing))
+	assert.Equal(t, codes.Unauthenticated, status.Code(invalid))
+	assert.Equal(t, codes.PermissionDenied, status.Code(forbidden))
+	assert.Equal(t, apierrors.CodeForbidden, reason(forbidden))
+	assert.Nil(t, allowed)
+	assert.Nil(t, healthErr)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, health.Status)
+}
+
+func TestServerRecordsTheCalls(t *testing.T) {
+	// arrange
+	ring := audit.NewRing(10)
+	audit.SetDefault(audit.New(config.Default().Audit, ring, audit.NewPolicies()))
+	defer audit.SetDefault(nil)
+	cfg := testConfig()
+	cfg.Auth.Enabled = true
+	cfg.Audit.Enabled = true
+	conn := setupServer(t, cfg)
+	client := canivetev1.NewProgrammingServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "ci-key")
+	forbiddenCtx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", "finance-key")
+
+	// act
+	_, err := client.NewUuid(ctx, &canivetev1.NewUuidRequest{})
+	client.NewUuid(forbiddenCtx, &canivetev1.NewUuidRequest{})
+	client.NewUuid(context.Background(), &canivetev1.NewUuidRequest{})
+	healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+	// assert
+	assert.Nil(t, err)
+	records := ring.Records(func(audit.Record) bool { return true })
+	if assert.Len(t, records, 3) {
+		unauthenticated, forbidden, allowed := records[0], records[1], records[2]
+		assert.Equal(t, "ci", allowed.Caller)
+		assert.Equal(t, "api-key", allowed.AuthMethod)
+		assert.Equal(t, audit.TransportGrpc, allowed.Transport)
+		assert.Equal(t, "/canivete.v1.ProgrammingService/NewUuid", allowed.Route)
+		assert.Equal(t, codes.OK.String(), allowed.Status)
+		assert.Equal(t, audit.OutcomeSuccess, allowed.Outcome)
+		assert.Equal(t, "finance", forbidden.Caller)
+		assert.Equal(t, codes.PermissionDenied.String(), forbidden.Status)
+		assert.Equal(t, audit.OutcomeRejected, forbidden.Outcome)
+		assert.Empty(t, unauthenticated.Caller)
+		assert.Equal(t, codes.Unauthenticated.String(), unauthenticated.Status)
+	}
+}
+
+func TestServerChecksTheToggles(t *testing.T) {
+	// arrange
+	conn := setupServer(t, testConfig(), func(s *Server) {
+		s.SetToggles(disabledMethods{"/canivete.v1.ProgrammingService/NewUuid"})
+	})
+	client := canivetev1.NewProgrammingServiceClient(conn)
+
+	// act
+	_, disabled := client.NewUuid(context.Background(), &canivetev1.NewUuidRequest{})
+	_, other := client.DebugJwt(context.Background(), &canivetev1.DebugJwtRequest{Token: "panic"})
+
+	// assert
+	assert.Equal(t, codes.Unavailable, status.Code(disabled))
+	assert.Equal(t, apierrors.CodeRouteDisabled, reason(disabled))
+	assert.Equal(t, codes.Internal, status.Code(other))
+}
+
+func TestServerRateLimitsTheCalls(t *testing.T) {
+	// arrange
+	costs := ratelimit.NewCosts()
+	costs.Set(gin.New().Group("/v1/programming"), "/uuid", 2)
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Limit{Rate: 0.1, Burst: 3}, costs, nil)
+	conn := setupServer(t, testConfig(), func(s *Server) {
+		s.SetRateLimiter(limiter, map[string]string{"/canivete.v1.ProgrammingService/NewUuid": "/v1/programming/uuid"})
+	})
+	client := canivetev1.NewProgrammingServiceClient(conn)
+
+	// act
+	_, allowed := client.NewUuid(context.Background(), &canivetev1.NewUuidRequest{})
+	var header metadata.MD
+	_, refused := client.NewUuid(context.Background(), &canivetev1.NewUuidRequest{}, grpc.Header(&header))
+	health := healthpb.NewHealthClient(conn)
+	_, unlimited := health.Check(context.Background(), &healthpb.HealthCheckRequest{})
+
+	// assert
+	assert.Nil(t, allowed)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(refused))
+	assert.Equal(t, apierrors.CodeRateLimited, reason(refused))
+	assert.Equal(t, []string{"10"}, header.Get("retry-after"))
+	assert.Nil(t, unlimited)
+}
+
+func TestServerRecoversFromPanics(t *testing.T) {
+	// arrange
+	conn := setupServer(t, testConfig())
+	client := canivetev1.NewProgrammingServiceClient(conn)
+
+	// act
+	_, err := client.DebugJwt(context.Background(), &canivetev1.DebugJwtRequest{Token: "panic"})
+
+	// assert
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, apierrors.CodeInternal, reason(err))
+}
+
+func TestServerBoundsTheCalls(t *testing.T) {
+	// arrange
+	cfg := testConfig()
+	cfg.Limits.Routes = map[string]config.RouteLimitsConfig{
+		"/canivete.v1.ProgrammingService/DebugJwt": {Timeout: 10 * time.Millisecond},
+	}
+	conn := setupServer(t, cfg)
+	client := canivetev1.NewProgrammingServiceClient(conn)
+
+	// act
+	_, err := client.DebugJwt(context.Background(), &canivetev1.DebugJwtRequest{Token: "token"})
+
+	// assert
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.Equal(t, apierrors.CodeTimeout, reason(err))
+}
+
+func TestServerStopMarksTheServicesNotServing(t *testing.T) {
+	// arrange
+	s := New(testConfig(), nil, nil)
+	canivetev1.RegisterProgrammingServiceServer(s.Group(config.GroupProgramming), fakeProgrammingService{})
+	service := canivetev1.ProgrammingService_ServiceDesc.ServiceName
+
+	// act
+	err := s.Stop(context.Background())
+	resp, checkErr := s.health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, checkErr)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+}
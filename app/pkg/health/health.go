@@ -0,0 +1,205 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOk   = "ok"
+	StatusFail = "fail"
+)
+
+// Check tells if a part of the api is healthy.
+type Check struct {
+	// Run returns an error when the check fails.
+	Run func(ctx context.Context) error
+	// Critical checks make the endpoints fail, the others are only reported.
+	Critical bool
+	// Liveness checks are run by /livez, on top of /readyz and /healthz.
+	// They must only fail when restarting the process fixes the problem.
+	Liveness bool
+}
+
+// CheckOutput is the result of a single check.
+type CheckOutput struct {
+	Status   string `json:"status"`
+	Critical bool   `json:"critical"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Output is the result of a set of checks.
+type Output struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckOutput `json:"checks"`
+}
+
+type checkResult struct {
+	name   string
+	output CheckOutput
+}
+
+// cachedOutput is the last result of a check and when it was taken.
+type cachedOutput struct {
+	output CheckOutput
+	at     time.Time
+}
+
+// Registry keeps the checks contributed by the service groups.
+type Registry struct {
+	mu       sync.Mutex
+	checks   map[string]Check
+	timeout  time.Duration
+	cacheTtl time.Duration
+	cache    map[string]cachedOutput
+}
+
+// NewRegistry returns an empty registry where each check can run up to timeout.
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{checks: map[string]Check{}, timeout: timeout, cache: map[string]cachedOutput{}}
+}
+
+// Register adds a check to the registry.
+// Registering a name twice replaces the previous check.
+func (r *Registry) Register(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks[name] = check
+	delete(r.cache, name)
+}
+
+// SetTimeout changes how long each check can run.
+func (r *Registry) SetTimeout(timeout time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.timeout = timeout
+}
+
+// SetCacheTtl changes how long the result of a check is reused, so the
+// probes of every pod do not call the dependencies on each period.
+// Zero runs the checks on every call.
+func (r *Registry) SetCacheTtl(ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cacheTtl = ttl
+	r.cache = map[string]cachedOutput{}
+}
+
+// Run runs the checks concurrently and reports their results. The results
+// younger than the cache ttl are reused instead of running the checks.
+// When livenessOnly is true only the liveness checks are run.
+func (r *Registry) Run(ctx context.Context, livenessOnly bool) Output {
+	output := Output{Status: StatusOk, Checks: map[string]CheckOutput{}}
+
+	r.mu.Lock()
+	checks := map[string]Check{}
+	for name, check := range r.checks {
+		if livenessOnly && !check.Liveness {
+			continue
+		}
+		cached, ok := r.cache[name]
+		if ok && time.Since(cached.at) < r.cacheTtl {
+			output.Checks[name] = cached.output
+			continue
+		}
+		checks[name] = check
+	}
+	timeout := r.timeout
+	r.mu.Unlock()
+
+	results := make(chan checkResult, len(checks))
+	for name, check := range checks {
+		go func(name string, check Check) {
+			results <- checkResult{name: name, output: runCheck(ctx, check, timeout)}
+		}(name, check)
+	}
+
+	for range checks {
+		result := <-results
+		output.Checks[result.name] = result.output
+		r.store(result)
+	}
+
+	for _, check := range output.Checks {
+		if check.Status == StatusFail && check.Critical {
+			output.Status = StatusFail
+		}
+	}
+
+	return output
+}
+
+// store keeps the result of a check when the results are cached.
+func (r *Registry) store(result checkResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cacheTtl > 0 {
+		r.cache[result.name] = cachedOutput{output: result.output, at: time.Now()}
+	}
+}
+
+func runCheck(ctx context.Context, check Check, timeout time.Duration) CheckOutput {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// checks ignoring the context must not block the probes
+	done := make(chan error, 1)
+	start := time.Now()
+	go func() {
+		done <- check.Run(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	output := CheckOutput{
+		Status:   StatusOk,
+		Critical: check.Critical,
+		Duration: time.Since(start).String(),
+	}
+	if err != nil {
+		output.Status = StatusFail
+		output.Error = err.Error()
+	}
+
+	return output
+}
+
+var defaultRegistry = NewRegistry(2 * time.Second)
+
+// Register adds a check to the default registry, used by the server.
+func Register(name string, check Check) {
+	defaultRegistry.Register(name, check)
+}
+
+// DefaultRegistry returns the registry where the service groups register
+// their checks.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
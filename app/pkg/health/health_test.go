@@ -0,0 +1,198 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func passing(ctx context.Context) error {
+	return nil
+}
+
+func failing(ctx context.Context) error {
+	return errors.New("fake error")
+}
+
+func setupGin(registry *Registry) *gin.Engine {
+	r := gin.Default()
+	SetRouterGroup(registry, &r.RouterGroup)
+
+	return r
+}
+
+func get(r *gin.Engine, path string) (int, Output) {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	r.ServeHTTP(w, req)
+
+	output := Output{}
+	json.Unmarshal(w.Body.Bytes(), &output)
+
+	return w.Code, output
+}
+
+func TestReadyzWithAllChecksPassing(t *testing.T) {
+	// arrange
+	registry := NewRegistry(time.Second)
+	registry.Register("a", Check{Run: passing, Critical: true})
+	registry.Register("b", Check{Run: passing})
+	r := setupGin(registry)
+
+	// act
+	code, output := get(r, "/readyz")
+
+	// assert
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOk, output.Status)
+	assert.Len(t, output.Checks, 2)
+	assert.Equal(t, StatusOk, output.Checks["a"].Status)
+}
+
+func TestReadyzWithNonCriticalCheckFailing(t *testing.T) {
+	// arrange
+	registry := NewRegistry(time.Second)
+	registry.Register("a", Check{Run: passing, Critical: true})
+	registry.Register("b", Check{Run: failing})
+	r := setupGin(registry)
+
+	// act
+	code, output := get(r, "/readyz")
+
+	// assert
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, StatusOk, output.Status)
+	assert.Equal(t, StatusFail, output.Checks["b"].Status)
+	assert.Equal(t, "fake error", output.Checks["b"].Error)
+}
+
+func TestHealthzWithCriticalCheckFailing(t *testing.T) {
+	// arrange
+	registry := NewRegistry(time.Second)
+	registry.Register("a", Check{Run: failing, Critical: true})
+	r := setupGin(registry)
+
+	// act
+	code, output := get(r, "/healthz")
+
+	// assert
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, StatusFail, output.Status)
+}
+
+func TestLivezOnlyRunsLivenessChecks(t *testing.T) {
+	// arrange
+	registry := NewRegistry(time.Second)
+	registry.Register("readiness", Check{Run: failing, Critical: true})
+	registry.Register("liveness", Check{Run: passing, Critical: true, Liveness: true})
+	r := setupGin(registry)
+
+	// act
+	code, output := get(r, "/livez")
+
+	// assert
+	assert.Equal(t, http.StatusOK, code)
+	assert.Len(t, output.Checks, 1)
+	assert.Contains(t, output.Checks, "liveness")
+}
+
+func TestCheckTimesOut(t *testing.T) {
+	// arrange
+	release := make(chan struct{})
+	defer close(release)
+
+	registry := NewRegistry(10 * time.Millisecond)
+	registry.Register("slow", Check{
+		Run: func(ctx context.Context) error {
+			<-release
+			return nil
+		},
+		Critical: true,
+	})
+	r := setupGin(registry)
+
+	// act
+	code, output := get(r, "/readyz")
+
+	// assert
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, context.DeadlineExceeded.Error(), output.Checks["slow"].Error)
+}
+
+func TestCheckResultsAreCached(t *testing.T) {
+	// arrange
+	runs := 0
+	registry := NewRegistry(time.Second)
+	registry.SetCacheTtl(time.Minute)
+	registry.Register("counted", Check{
+		Run: func(ctx context.Context) error {
+			runs++
+			return failing(ctx)
+		},
+		Critical: true,
+	})
+	r := setupGin(registry)
+
+	// act
+	code, _ := get(r, "/readyz")
+	cachedCode, cachedOutput := get(r, "/readyz")
+
+	// assert
+	assert.Equal(t, 1, runs)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, http.StatusServiceUnavailable, cachedCode)
+	assert.Equal(t, "fake error", cachedOutput.Checks["counted"].Error)
+}
+
+func TestOutputUsesCamelCaseKeys(t *testing.T) {
+	// arrange
+	registry := NewRegistry(time.Second)
+	registry.Register("a", Check{Run: failing, Critical: true})
+	r := setupGin(registry)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/readyz", nil)
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	body := map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, StatusFail, body["status"])
+	check := body["checks"].(map[string]interface{})["a"].(map[string]interface{})
+	assert.Equal(t, []string{"critical", "duration", "error", "status"}, keys(check))
+}
+
+func keys(m map[string]interface{}) []string {
+	names := []string{}
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,54 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetRouterGroup adds the health endpoints to the base group:
+//
+// /livez runs the liveness checks, used to restart the pod;
+// /readyz runs all the checks, used to route traffic to the pod;
+// /healthz is the same as /readyz, kept for the tools probing it.
+func SetRouterGroup(r *Registry, base *gin.RouterGroup) *gin.RouterGroup {
+	base.GET("/healthz", getHealth(r, false))
+	base.GET("/readyz", getHealth(r, false))
+	base.GET("/livez", getHealth(r, true))
+
+	return base
+}
+
+// getHealth handles the health requests.
+// It returns:
+//
+// 200 (OK) if all critical checks passed;
+// 503 (ServiceUnavailable) otherwise.
+func getHealth(r *Registry, livenessOnly bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		output := r.Run(c.Request.Context(), livenessOnly)
+
+		status := http.StatusOK
+		if output.Status != StatusOk {
+			status = http.StatusServiceUnavailable
+		}
+
+		c.JSON(status, output)
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-api/pkg/limits"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-core/interface/internet"
+)
+
+var mediumPostType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "MediumPost",
+	Fields: graphql.Fields{
+		"postId":   &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"markdown": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+	},
+})
+
+// RegisterGraphqlFields adds the internet tools to the GraphQL schema.
+// The conversions reach Medium, so they are mutations.
+func RegisterGraphqlFields(i internet.Interface, g *graphqlserver.Group) {
+	audit.SetRoutePolicy("Mutation.convertMediumToMd", audit.Policy{Inputs: map[string]string{"postId": audit.Keep}})
+
+	g.Mutation("convertMediumToMd", &graphql.Field{
+		Type:        mediumPostType,
+		Description: "Converts a Medium post to markdown",
+		Args: graphql.FieldConfigArgument{
+			"postId": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+		Resolve: func(rp graphql.ResolveParams) (interface{}, error) {
+			postId, _ := rp.Args["postId"].(string)
+			postId = strings.TrimSpace(postId)
+			if postId == "" {
+				return nil, graphqlserver.Error(apierrors.BadRequest(apierrors.CodeInvalidBody, "post id is required"))
+			}
+
+			output, err := convert(rp.Context, i, postId)
+			metrics.MediumConversions.WithLabelValues(metrics.Outcome(err)).Inc()
+			if limits.Exceeded(err) {
+				return nil, graphqlserver.Timeout()
+			}
+			if err != nil {
+				logging.FromContext(rp.Context).Debugw("error converting a medium post to markdown", "error", err.Error())
+				return nil, graphqlserver.Error(apierrors.Internal(apierrors.CodeConversionFailed, err.Error()))
+			}
+
+			return map[string]interface{}{"postId": output.PostId, "markdown": output.Markdown}, nil
+		},
+	})
+}
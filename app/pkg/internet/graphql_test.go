@@ -0,0 +1,69 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+)
+
+func doGraphql(t *testing.T, i internet.Interface, query string) *graphql.Result {
+	s := graphqlserver.New(config.Default().Graphql, false)
+	RegisterGraphqlFields(i, s.Group(config.GroupInternet))
+	schema, err := s.Schema()
+	assert.Nil(t, err)
+
+	return graphql.Do(graphql.Params{Schema: schema, RequestString: query, Context: context.Background()})
+}
+
+func TestGraphqlConvertMediumToMd(t *testing.T) {
+	// arrange
+	serviceMock := internet.MockInterface{}
+	serviceMock.On("ConvertMediumToMd", "a6e4a6e1a3f1").Return(internet.ConvertMediumToMdOutput{Markdown: "# title", PostId: "a6e4a6e1a3f1"}, nil)
+
+	// act
+	result := doGraphql(t, &serviceMock, `mutation { convertMediumToMd(postId: "a6e4a6e1a3f1") { markdown } }`)
+
+	// assert
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, map[string]interface{}{
+		"convertMediumToMd": map[string]interface{}{"markdown": "# title"},
+	}, result.Data)
+}
+
+func TestGraphqlConvertMediumToMdWithErrors(t *testing.T) {
+	// arrange
+	serviceMock := internet.MockInterface{}
+	serviceMock.On("ConvertMediumToMd", "missing").Return(internet.ConvertMediumToMdOutput{}, errors.New("fake error"))
+
+	// act
+	result := doGraphql(t, &serviceMock, `mutation { empty: convertMediumToMd(postId: " ") { markdown } missing: convertMediumToMd(postId: "missing") { markdown } }`)
+
+	// assert
+	if assert.Len(t, result.Errors, 2) {
+		assert.Equal(t, apierrors.CodeInvalidBody, result.Errors[0].Extensions["code"])
+		assert.Equal(t, apierrors.CodeConversionFailed, result.Errors[1].Extensions["code"])
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"strings"
+
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/grpcserver"
+	"github.com/renato0307/canivete-api/pkg/limits"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	canivetev1 "github.com/renato0307/canivete-api/pkg/pb/canivete/v1"
+	"github.com/renato0307/canivete-core/interface/internet"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// grpcService serves the internet tools over gRPC.
+type grpcService struct {
+	canivetev1.UnimplementedInternetServiceServer
+	i internet.Interface
+}
+
+// RegisterGrpcService registers the gRPC service of the internet tools.
+func RegisterGrpcService(i internet.Interface, registrar grpc.ServiceRegistrar) {
+	canivetev1.RegisterInternetServiceServer(registrar, &grpcService{i: i})
+
+	service := canivetev1.InternetService_ServiceDesc.ServiceName
+	audit.SetRoutePolicy("/"+service+"/ConvertMediumToMd", audit.Policy{Inputs: map[string]string{"post_id": audit.Keep}})
+}
+
+func (s *grpcService) ConvertMediumToMd(ctx context.Context, req *canivetev1.ConvertMediumToMdRequest) (*canivetev1.ConvertMediumToMdResponse, error) {
+	postId := strings.TrimSpace(req.PostId)
+	if postId == "" {
+		return nil, grpcserver.Error(codes.InvalidArgument, apierrors.CodeInvalidBody, "post id is required")
+	}
+
+	output, err := convert(ctx, s.i, postId)
+	metrics.MediumConversions.WithLabelValues(metrics.Outcome(err)).Inc()
+	if limits.Exceeded(err) {
+		return nil, grpcserver.Timeout()
+	}
+	if err != nil {
+		logging.FromContext(ctx).Debugw("error converting a medium post to markdown", "error", err.Error())
+		return nil, grpcserver.Error(codes.Internal, apierrors.CodeConversionFailed, err.Error())
+	}
+
+	return &canivetev1.ConvertMediumToMdResponse{Markdown: output.Markdown, PostId: output.PostId}, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	canivetev1 "github.com/renato0307/canivete-api/pkg/pb/canivete/v1"
+	"github.com/renato0307/canivete-core/interface/internet"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcConvertMediumToMd(t *testing.T) {
+	// arrange
+	serviceMock := internet.MockInterface{}
+	serviceMock.On("ConvertMediumToMd", "a6e4a6e1a3f1").Return(internet.ConvertMediumToMdOutput{Markdown: "# title", PostId: "a6e4a6e1a3f1"}, nil)
+	s := grpcService{i: &serviceMock}
+
+	// act
+	resp, err := s.ConvertMediumToMd(context.Background(), &canivetev1.ConvertMediumToMdRequest{PostId: "a6e4a6e1a3f1"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "# title", resp.Markdown)
+}
+
+func TestGrpcConvertMediumToMdWithErrors(t *testing.T) {
+	// arrange
+	serviceMock := internet.MockInterface{}
+	serviceMock.On("ConvertMediumToMd", "missing").Return(internet.ConvertMediumToMdOutput{}, errors.New("fake error"))
+	s := grpcService{i: &serviceMock}
+
+	// act
+	_, emptyErr := s.ConvertMediumToMd(context.Background(), &canivetev1.ConvertMediumToMdRequest{})
+	_, coreErr := s.ConvertMediumToMd(context.Background(), &canivetev1.ConvertMediumToMdRequest{PostId: "missing"})
+
+	// assert
+	assert.Equal(t, codes.InvalidArgument, status.Code(emptyErr))
+	assert.Equal(t, codes.Internal, status.Code(coreErr))
+}
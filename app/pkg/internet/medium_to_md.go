@@ -17,25 +17,73 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package internet
 
 import (
+	"context"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/health"
+	"github.com/renato0307/canivete-api/pkg/limits"
 	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-api/pkg/openapi"
+	"github.com/renato0307/canivete-api/pkg/ratelimit"
+	"github.com/renato0307/canivete-api/pkg/render"
+	"github.com/renato0307/canivete-api/pkg/requestid"
+	"github.com/renato0307/canivete-api/pkg/shutdown"
+	"github.com/renato0307/canivete-api/pkg/tracing"
 	"github.com/renato0307/canivete-core/interface/internet"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
 var logger *zap.SugaredLogger = logging.GetLogger()
 
+// mediumUrl is the address probed to know if medium is reachable.
+var mediumUrl string = "https://medium.com"
+
+// client forwards the request id to medium.
+var client = &http.Client{Transport: requestid.NewTransport(http.DefaultTransport)}
+
 func SetRouterGroup(i internet.Interface, base *gin.RouterGroup) *gin.RouterGroup {
 	programmingGroup := base.Group("/internet")
 	{
 		programmingGroup.POST("/medium-to-md", postConvertMediumToMd(i))
 	}
 
+	openapi.Describe(programmingGroup, http.MethodPost, "/medium-to-md", openapi.Operation{
+		Summary: "Converts a Medium post to markdown",
+		Request: &openapi.Body{
+			ContentType: openapi.ContentTypeText,
+			Type:        "",
+			Description: "The id of the Medium post.",
+			Example:     "a6e4a6e1a3f1",
+		},
+		Response: openapi.Body{Type: internet.ConvertMediumToMdOutput{}},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	})
+
+	// each conversion calls medium and converts the whole post
+	ratelimit.SetCost(programmingGroup, "/medium-to-md", 10)
+
+	// the posts are public, so the audit log tells which one was converted
+	audit.SetPolicy(programmingGroup, "/medium-to-md", audit.Policy{Inputs: map[string]string{"body": audit.Keep}})
+
+	// medium being down only breaks this group, so the check is not critical
+	health.Register("internet", health.Check{Run: checkMedium})
+
+	shutdown.Register("internet", func(ctx context.Context) error {
+		// the core service uses the default transport for the calls to medium
+		if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+			transport.CloseIdleConnections()
+		}
+		return nil
+	})
+
 	return programmingGroup
 }
 
@@ -48,24 +96,73 @@ func SetRouterGroup(i internet.Interface, base *gin.RouterGroup) *gin.RouterGrou
 func postConvertMediumToMd(i internet.Interface) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if c.Request.Body == nil {
-			c.JSON(http.StatusBadRequest, apierrors.ApiError{Message: "request body is invalid"})
+			apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidBody, "request body is invalid"))
 			return
 		}
 
 		postIdBytes, err := ioutil.ReadAll(c.Request.Body)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, apierrors.ApiError{Message: "error reading the body"})
+			apierrors.Abort(c, apierrors.Internal(apierrors.CodeInternal, "error reading the body"))
 			return
 		}
 
 		postId := strings.TrimRight(string(postIdBytes), "\n")
-		output, err := i.ConvertMediumToMd(postId)
+		output, err := convert(c.Request.Context(), i, postId)
+		metrics.MediumConversions.WithLabelValues(metrics.Outcome(err)).Inc()
+		if limits.Exceeded(err) {
+			apierrors.Abort(c, limits.Timeout())
+			return
+		}
 		if err != nil {
-			logger.Debugw("error converting a medium post to markdown", "error", err.Error())
-			c.JSON(http.StatusInternalServerError, apierrors.ApiError{Message: err.Error()})
+			logging.FromContext(c).Debugw("error converting a medium post to markdown", "error", err.Error())
+			apierrors.Abort(c, apierrors.Internal(apierrors.CodeConversionFailed, err.Error()))
 			return
 		}
 
-		c.JSON(200, output)
+		render.Render(c, http.StatusOK, output, func() string { return output.Markdown })
 	}
 }
+
+// convert converts the post with the service, in the time limit of ctx.
+// The service takes no context, so its calls to medium cannot carry the
+// request id; the span of the conversion correlates them instead.
+func convert(ctx context.Context, i internet.Interface, postId string) (internet.ConvertMediumToMdOutput, error) {
+	span := tracing.StartContextSpan(ctx, "internet.ConvertMediumToMd")
+	span.SetAttributes(
+		attribute.String("medium.post_id", postId),
+		attribute.String("request.id", requestid.FromContext(ctx)),
+	)
+
+	var output internet.ConvertMediumToMdOutput
+	err := limits.Call(ctx, func() (err error) {
+		output, err = i.ConvertMediumToMd(postId)
+		return err
+	})
+	tracing.EndSpan(span, err)
+	if err != nil {
+		// the call may still be running and writing the output
+		return internet.ConvertMediumToMdOutput{}, err
+	}
+
+	return output, nil
+}
+
+// checkMedium tells if medium, used by the core service, is reachable.
+func checkMedium(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, mediumUrl, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("medium returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -17,6 +17,7 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 package internet
 
 import (
+	"context"
 	"errors"
 	"strings"
 	"testing"
@@ -25,10 +26,17 @@ import (
 	"net/http/httptest"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-api/pkg/requestid"
 	"github.com/renato0307/canivete-core/interface/internet"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func setupGin(serviceMock *internet.MockInterface) *gin.Engine {
@@ -39,6 +47,12 @@ func setupGin(serviceMock *internet.MockInterface) *gin.Engine {
 	return r
 }
 
+func setMediumUrl(t *testing.T, url string) {
+	previous := mediumUrl
+	mediumUrl = url
+	t.Cleanup(func() { mediumUrl = previous })
+}
+
 func TestPostFromUnix(t *testing.T) {
 	output := internet.ConvertMediumToMdOutput{
 		PostId:   "1638964800",
@@ -73,16 +87,20 @@ func TestPostFromUnixWithErrorFromCore(t *testing.T) {
 	w := httptest.NewRecorder()
 	body := strings.NewReader(output.PostId)
 	req, _ := http.NewRequest("POST", "/v1/internet/medium-to-md", body)
+	failures := metrics.MediumConversions.WithLabelValues(metrics.OutcomeFailure)
+	failed := testutil.ToFloat64(failures)
 
 	// act
 	r.ServeHTTP(w, req)
 
 	// assert
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, failed+1, testutil.ToFloat64(failures))
 
-	expectedError := apierrors.ApiError{Message: error.Error()}
-	apiError, _ := apierrors.FromResponseRecorder(w)
-	assert.Equal(t, expectedError, apiError)
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeConversionFailed, apiError.Code)
+	assert.Equal(t, error.Error(), apiError.Detail)
 
 }
 
@@ -100,6 +118,86 @@ func TestPostFromUnixWithErrorDueToNoBody(t *testing.T) {
 	// assert
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	apiError, _ := apierrors.FromResponseRecorder(w)
-	assert.Equal(t, "request body is invalid", apiError.Message)
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeInvalidBody, apiError.Code)
+	assert.Equal(t, "request body is invalid", apiError.Detail)
+}
+
+func TestCheckMedium(t *testing.T) {
+	// arrange
+	medium := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer medium.Close()
+	setMediumUrl(t, medium.URL)
+
+	// act
+	err := checkMedium(context.Background())
+
+	// assert
+	assert.Nil(t, err)
+}
+
+func TestCheckMediumWhenMediumFails(t *testing.T) {
+	// arrange
+	medium := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer medium.Close()
+	setMediumUrl(t, medium.URL)
+
+	// act
+	err := checkMedium(context.Background())
+
+	// assert
+	assert.EqualError(t, err, "medium returned status 502")
+}
+
+func TestCheckMediumForwardsRequestId(t *testing.T) {
+	// arrange
+	var forwarded string
+	medium := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get(requestid.DefaultHeader)
+	}))
+	defer medium.Close()
+	setMediumUrl(t, medium.URL)
+
+	r := gin.New()
+	r.Use(requestid.Middleware(requestid.DefaultHeader))
+	r.GET("/readyz", func(c *gin.Context) {
+		assert.Nil(t, checkMedium(c.Request.Context()))
+	})
+	req, _ := http.NewRequest("GET", "/readyz", nil)
+	req.Header.Set(requestid.DefaultHeader, "fake-request-id")
+
+	// act
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	// assert
+	assert.Equal(t, "fake-request-id", forwarded)
+}
+
+func TestConvertRecordsTheRequestIdOnTheSpan(t *testing.T) {
+	// arrange
+	previous := otel.GetTracerProvider()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	serviceMock := &internet.MockInterface{}
+	serviceMock.On("ConvertMediumToMd", "a6e4a6e1a3f1").Return(internet.ConvertMediumToMdOutput{PostId: "a6e4a6e1a3f1"}, nil)
+	ctx, _ := requestid.NewContext(context.Background(), requestid.DefaultHeader, "fake-request-id")
+
+	// act
+	output, err := convert(ctx, serviceMock, "a6e4a6e1a3f1")
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "a6e4a6e1a3f1", output.PostId)
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "internet.ConvertMediumToMd", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("medium.post_id", "a6e4a6e1a3f1"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("request.id", "fake-request-id"))
 }
@@ -0,0 +1,51 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package internet
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-api/pkg/tools"
+	internetcore "github.com/renato0307/canivete-core/pkg/internet"
+	"google.golang.org/grpc"
+)
+
+func init() {
+	service := &internetcore.Service{}
+	tools.Register(tools.Group{
+		Name:        config.GroupInternet,
+		Description: "Tools for the content of the web",
+		Tools: []tools.Tool{
+			{
+				Name: "medium-to-md", Method: http.MethodPost, Path: "/medium-to-md", Tags: []string{"converters", "markdown"},
+				GraphqlField: "Mutation.convertMediumToMd", GrpcMethod: "/canivete.v1.InternetService/ConvertMediumToMd",
+			},
+		},
+		SetRouterGroup: func(base *gin.RouterGroup) *gin.RouterGroup {
+			return SetRouterGroup(service, base)
+		},
+		RegisterGrpcService: func(registrar grpc.ServiceRegistrar) {
+			RegisterGrpcService(service, registrar)
+		},
+		RegisterGraphqlFields: func(g *graphqlserver.Group) {
+			RegisterGraphqlFields(service, g)
+		},
+	})
+}
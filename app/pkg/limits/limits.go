@@ -0,0 +1,93 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package limits
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+)
+
+// Middleware bounds the body and the duration of the requests with the
+// limits of their route.
+//
+// The body is read up front, so the handlers never read more than the
+// maximum size, and the request is answered with 413
+// (RequestEntityTooLarge) when it is bigger.
+// The request context gets a deadline, which the handlers pass to Call.
+func Middleware(cfg config.LimitsConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		maxBodySize, timeout := cfg.For(c.FullPath())
+
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			body, err := ioutil.ReadAll(io.LimitReader(c.Request.Body, maxBodySize+1))
+			if err != nil {
+				apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidBody, "error reading the body"))
+				return
+			}
+			if int64(len(body)) > maxBodySize {
+				detail := fmt.Sprintf("request body is larger than %d bytes", maxBodySize)
+				apierrors.Abort(c, apierrors.New(http.StatusRequestEntityTooLarge, apierrors.CodeBodyTooLarge, detail))
+				return
+			}
+			c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// Call runs call, a call into a core service, and returns its error.
+// The core services take no context, so when the context is done first
+// Call returns the context error without waiting for call to end. call may
+// still be writing its results then, so they must not be read, not even to
+// be returned, when Call fails.
+func Call(ctx context.Context, call func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- call()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Exceeded tells if err is returned by Call for a context done first.
+func Exceeded(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+// Timeout returns the error answered when a tool exceeds its deadline.
+func Timeout() apierrors.ApiError {
+	return apierrors.New(http.StatusGatewayTimeout, apierrors.CodeTimeout, "the tool did not complete in time")
+}
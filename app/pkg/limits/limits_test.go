@@ -0,0 +1,109 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package limits
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGin(cfg config.LimitsConfig) *gin.Engine {
+	r := gin.New()
+	r.Use(Middleware(cfg))
+	r.POST("/echo", func(c *gin.Context) {
+		body, _ := ioutil.ReadAll(c.Request.Body)
+		_, hasDeadline := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"body": string(body), "deadline": hasDeadline})
+	})
+
+	return r
+}
+
+func TestMiddlewarePassesTheBody(t *testing.T) {
+	// arrange
+	r := setupGin(config.LimitsConfig{MaxBodySize: 5, Timeout: time.Second})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/echo", strings.NewReader("12345"))
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"body":"12345","deadline":true}`, w.Body.String())
+}
+
+func TestMiddlewareRejectsLargeBodies(t *testing.T) {
+	// arrange
+	r := setupGin(config.LimitsConfig{
+		MaxBodySize: 100,
+		Timeout:     time.Second,
+		Routes:      map[string]config.RouteLimitsConfig{"/echo": {MaxBodySize: 4}},
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/echo", strings.NewReader("12345"))
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeBodyTooLarge, apiError.Code)
+}
+
+func TestCall(t *testing.T) {
+	// arrange
+	callErr := errors.New("fake error")
+
+	// act
+	err := Call(context.Background(), func() error { return callErr })
+
+	// assert
+	assert.Equal(t, callErr, err)
+	assert.False(t, Exceeded(err))
+}
+
+func TestCallExceedingTheDeadline(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	release := make(chan struct{})
+	defer close(release)
+
+	// act
+	err := Call(ctx, func() error {
+		<-release
+		return nil
+	})
+
+	// assert
+	assert.True(t, Exceeded(err))
+	assert.Equal(t, http.StatusGatewayTimeout, Timeout().Status)
+}
@@ -16,12 +16,169 @@ along with this program. If not, see <http://www.gnu.org/licenses/>.
 */
 package logging
 
-import "go.uber.org/zap"
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/renato0307/canivete-api/pkg/config"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	FormatJson    = "json"
+	FormatConsole = "console"
+)
+
+var (
+	mu    sync.RWMutex
+	level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	core  = zapcore.NewCore(
+		zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()),
+		zapcore.Lock(os.Stderr),
+		level,
+	)
+	closeOutputs = func() {}
+)
+
+// GetLogger returns a logger writing to the core built by Setup.
+// It can be called before Setup, usually to initialize a package variable:
+// the entries are logged to stderr until the logger is configured.
 func GetLogger() *zap.SugaredLogger {
-	logger, _ := zap.NewDevelopment()
-	defer logger.Sync() // flushes buffer, if any
-	sugar := logger.Sugar()
+	return zap.New(proxyCore{}, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel)).Sugar()
+}
+
+// Setup configures the loggers returned by GetLogger, including the ones
+// created before the call.
+func Setup(cfg config.LoggingConfig) error {
+	newLevel, err := parseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+
+	encoder, err := newEncoder(cfg.Format)
+	if err != nil {
+		return err
+	}
+
+	sink, closeSink, err := zap.Open(cfg.Outputs...)
+	if err != nil {
+		return fmt.Errorf("error opening log outputs: %s", err.Error())
+	}
+
+	newCore := zapcore.NewCore(encoder, sink, level)
+	if cfg.Sampling.Enabled {
+		newCore = zapcore.NewSamplerWithOptions(newCore, time.Second, cfg.Sampling.Initial, cfg.Sampling.Thereafter)
+	}
+
+	mu.Lock()
+	oldCore, closeOldOutputs := core, closeOutputs
+	core, closeOutputs = newCore, closeSink
+	mu.Unlock()
+
+	level.SetLevel(newLevel)
+	oldCore.Sync()
+	closeOldOutputs()
+
+	return nil
+}
+
+// Level returns the level of the loggers.
+func Level() zapcore.Level {
+	return level.Level()
+}
+
+// SetLevel changes the level of the loggers at runtime, including the
+// ones created before the call.
+func SetLevel(text string) error {
+	newLevel, err := parseLevel(text)
+	if err != nil {
+		return err
+	}
+
+	level.SetLevel(newLevel)
+	return nil
+}
+
+func parseLevel(text string) (zapcore.Level, error) {
+	var parsed zapcore.Level
+	err := parsed.UnmarshalText([]byte(text))
+	if err != nil {
+		return parsed, fmt.Errorf("invalid logging level %q", text)
+	}
+
+	return parsed, nil
+}
+
+func newEncoder(format string) (zapcore.Encoder, error) {
+	switch format {
+	case FormatJson:
+		encoderConfig := zap.NewProductionEncoderConfig()
+		encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		return zapcore.NewJSONEncoder(encoderConfig), nil
+	case FormatConsole:
+		return zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig()), nil
+	default:
+		return nil, fmt.Errorf("invalid logging format %q", format)
+	}
+}
+
+// Sync flushes the logger buffer.
+// The errors returned when syncing a console (stdout or stderr) are ignored.
+func Sync(logger *zap.SugaredLogger) error {
+	err := logger.Sync()
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) {
+		return nil
+	}
+
+	return err
+}
+
+func currentCore() zapcore.Core {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return core
+}
+
+// proxyCore delegates to the core built by Setup, so package loggers
+// follow the configuration whenever they were created.
+type proxyCore struct {
+	fields []zapcore.Field
+}
+
+func (p proxyCore) Enabled(lvl zapcore.Level) bool {
+	return currentCore().Enabled(lvl)
+}
+
+func (p proxyCore) With(fields []zapcore.Field) zapcore.Core {
+	all := make([]zapcore.Field, 0, len(p.fields)+len(fields))
+	all = append(all, p.fields...)
+	all = append(all, fields...)
+
+	return proxyCore{fields: all}
+}
+
+func (p proxyCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	target := currentCore()
+	if !target.Enabled(entry.Level) {
+		return checked
+	}
+	if len(p.fields) > 0 {
+		target = target.With(p.fields)
+	}
+
+	return target.Check(entry, checked)
+}
+
+func (p proxyCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return currentCore().With(p.fields).Write(entry, fields)
+}
 
-	return sugar
+func (p proxyCore) Sync() error {
+	return currentCore().Sync()
 }
@@ -0,0 +1,180 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/requestid"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupLogFile configures the loggers to write JSON entries to a
+// temporary file and returns its path.
+func setupLogFile(t *testing.T, cfg config.LoggingConfig) string {
+	path := filepath.Join(t.TempDir(), "canivete.log")
+	cfg.Format = FormatJson
+	cfg.Outputs = []string{path}
+
+	err := Setup(cfg)
+	assert.Nil(t, err)
+
+	return path
+}
+
+func readEntries(t *testing.T, path string) []map[string]interface{} {
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	entries := []map[string]interface{}{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+func TestSetupAppliesToExistingLoggers(t *testing.T) {
+	// arrange
+	logger := GetLogger().With("package", "test")
+	cfg := config.Default().Logging
+	cfg.Level = "warn"
+	path := setupLogFile(t, cfg)
+
+	// act
+	logger.Infow("not logged")
+	logger.Warnw("logged", "answer", 42)
+
+	// assert
+	entries := readEntries(t, path)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "logged", entries[0]["msg"])
+	assert.Equal(t, "warn", entries[0]["level"])
+	assert.Equal(t, "test", entries[0]["package"])
+	assert.Equal(t, float64(42), entries[0]["answer"])
+}
+
+func TestSetupWithSampling(t *testing.T) {
+	// arrange
+	cfg := config.Default().Logging
+	cfg.Sampling = config.SamplingConfig{Enabled: true, Initial: 2, Thereafter: 5}
+	path := setupLogFile(t, cfg)
+	logger := GetLogger()
+
+	// act
+	for i := 0; i < 12; i++ {
+		logger.Infow("repeated")
+	}
+
+	// assert
+	assert.Len(t, readEntries(t, path), 4)
+}
+
+func TestSetLevelAppliesToExistingLoggers(t *testing.T) {
+	// arrange
+	path := setupLogFile(t, config.Default().Logging)
+	logger := GetLogger()
+
+	// act
+	logger.Debugw("not logged")
+	err := SetLevel("debug")
+	logger.Debugw("logged")
+	invalidErr := SetLevel("verbose")
+
+	// assert
+	assert.Nil(t, err)
+	assert.NotNil(t, invalidErr)
+	assert.Equal(t, "debug", Level().String())
+	entries := readEntries(t, path)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "logged", entries[0]["msg"])
+}
+
+func TestSetupWithInvalidConfig(t *testing.T) {
+	tests := []config.LoggingConfig{
+		{Format: "text", Level: "info", Outputs: []string{"stderr"}},
+		{Format: FormatJson, Level: "verbose", Outputs: []string{"stderr"}},
+		{Format: FormatJson, Level: "info", Outputs: []string{"/does/not/exist/canivete.log"}},
+	}
+
+	for _, cfg := range tests {
+		err := Setup(cfg)
+		assert.NotNil(t, err, "expected error for %v", cfg)
+	}
+}
+
+func TestMiddlewareAndAccessLog(t *testing.T) {
+	// arrange
+	path := setupLogFile(t, config.Default().Logging)
+
+	r := gin.New()
+	r.Use(requestid.Middleware(requestid.DefaultHeader), Middleware(), AccessLog("/healthz"))
+	r.GET("/v1/tools/:name", func(c *gin.Context) {
+		FromContext(c).Infow("running tool")
+		c.String(http.StatusBadRequest, "")
+	})
+	r.GET("/healthz", func(c *gin.Context) {
+		c.String(http.StatusOK, "")
+	})
+
+	req, _ := http.NewRequest("GET", "/v1/tools/uuid?count=2", nil)
+	req.Header.Set("X-Request-ID", "fake-request-id")
+	health, _ := http.NewRequest("GET", "/healthz", nil)
+
+	// act
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	r.ServeHTTP(httptest.NewRecorder(), health)
+
+	// assert
+	entries := readEntries(t, path)
+	assert.Len(t, entries, 2)
+
+	handlerEntry, accessEntry := entries[0], entries[1]
+	assert.Equal(t, "running tool", handlerEntry["msg"])
+	assert.Equal(t, "fake-request-id", handlerEntry["requestId"])
+	assert.Equal(t, "/v1/tools/:name", handlerEntry["route"])
+
+	assert.Equal(t, "request completed", accessEntry["msg"])
+	assert.Equal(t, "warn", accessEntry["level"])
+	assert.Equal(t, "/v1/tools/uuid", accessEntry["path"])
+	assert.Equal(t, "count=2", accessEntry["query"])
+	assert.Equal(t, float64(http.StatusBadRequest), accessEntry["status"])
+	assert.Equal(t, "fake-request-id", accessEntry["requestId"])
+}
+
+func TestFromContextWithoutMiddleware(t *testing.T) {
+	// arrange
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	// act
+	logger := FromContext(c)
+
+	// assert
+	assert.NotNil(t, logger)
+}
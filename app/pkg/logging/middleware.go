@@ -0,0 +1,119 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package logging
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/requestid"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// loggerKey is the key of the request logger in the gin context.
+const loggerKey = "canivete-api/logger"
+
+type contextKey struct{}
+
+// Middleware adds to the gin context a logger carrying the request id,
+// the route and the client ip. Handlers get it with FromContext.
+// The request id is set by requestid.Middleware, which must run before.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fields := []interface{}{"route", c.FullPath(), "client", c.ClientIP()}
+		if requestId := requestid.Get(c); requestId != "" {
+			fields = append(fields, "requestId", requestId)
+		}
+
+		c.Set(loggerKey, GetLogger().With(fields...))
+		c.Next()
+	}
+}
+
+// With adds fields to the request logger, for the rest of the request.
+func With(c *gin.Context, fields ...interface{}) {
+	c.Set(loggerKey, FromContext(c).With(fields...))
+}
+
+// NewContext returns a context carrying the request logger, for the
+// requests not served by gin, like the gRPC calls.
+func NewContext(ctx context.Context, logger *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the request logger set by Middleware, or by
+// NewContext, or a logger without the request fields if there is none.
+func FromContext(ctx context.Context) *zap.SugaredLogger {
+	if logger, ok := ctx.Value(loggerKey).(*zap.SugaredLogger); ok {
+		return logger
+	}
+	if logger, ok := ctx.Value(contextKey{}).(*zap.SugaredLogger); ok {
+		return logger
+	}
+
+	return GetLogger()
+}
+
+// AccessLog logs an entry for each completed request, except for the
+// requests to skipPaths. Server errors are logged with the error level and
+// client errors with the warn level.
+func AccessLog(skipPaths ...string) gin.HandlerFunc {
+	skip := map[string]bool{}
+	for _, path := range skipPaths {
+		skip[path] = true
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		c.Next()
+
+		if skip[path] {
+			return
+		}
+
+		status := c.Writer.Status()
+		fields := []interface{}{
+			"method", c.Request.Method,
+			"path", path,
+			"status", status,
+			"duration", time.Since(start),
+			"size", c.Writer.Size(),
+			"userAgent", c.Request.UserAgent(),
+		}
+		if query := c.Request.URL.RawQuery; query != "" {
+			fields = append(fields, "query", query)
+		}
+		if errors := c.Errors.ByType(gin.ErrorTypePrivate).String(); errors != "" {
+			fields = append(fields, "errors", errors)
+		}
+
+		// the stack of the middleware says nothing about the request
+		logger := FromContext(c).Desugar().WithOptions(zap.AddStacktrace(zapcore.FatalLevel)).Sugar()
+		switch {
+		case status >= 500:
+			logger.Errorw("request completed", fields...)
+		case status >= 400:
+			logger.Warnw("request completed", fields...)
+		default:
+			logger.Infow("request completed", fields...)
+		}
+	}
+}
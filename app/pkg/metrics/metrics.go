@@ -0,0 +1,138 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+const namespace = "canivete"
+
+// Outcomes of the tool invocations, used as the outcome label.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of http requests handled, by method, route and status code.",
+	}, []string{"method", "route", "code"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of the http requests, by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	httpRequestsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Number of http requests being handled.",
+	})
+)
+
+// Domain counters, incremented by the service group handlers.
+var (
+	UuidsGenerated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "programming",
+		Name:      "uuids_generated_total",
+		Help:      "Number of UUIDs generated.",
+	})
+
+	JwtsDebugged = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "programming",
+		Name:      "jwts_debugged_total",
+		Help:      "Number of JWTs debugged, by outcome.",
+	}, []string{"outcome"})
+
+	UnixTimestampsConverted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "datetime",
+		Name:      "unix_timestamps_converted_total",
+		Help:      "Number of unix timestamps converted to UTC, by outcome.",
+	}, []string{"outcome"})
+
+	CompoundInterestsCalculated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "finance",
+		Name:      "compound_interests_calculated_total",
+		Help:      "Number of compound interests calculations, by outcome.",
+	}, []string{"outcome"})
+
+	MediumConversions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "internet",
+		Name:      "medium_conversions_total",
+		Help:      "Number of Medium posts converted to markdown, by outcome.",
+	}, []string{"outcome"})
+
+	RateLimitedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "rate_limited_requests_total",
+		Help:      "Number of http requests rejected by the rate limit, by route.",
+	}, []string{"route"})
+
+	DeprecatedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "deprecated_requests_total",
+		Help:      "Number of http requests to deprecated routes, by route.",
+	}, []string{"route"})
+
+	CacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "lookups_total",
+		Help:      "Number of responses looked up in the cache, by route and result (hit or miss).",
+	}, []string{"route", "result"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestsTotal,
+		httpRequestDuration,
+		httpRequestsInFlight,
+		UuidsGenerated,
+		JwtsDebugged,
+		UnixTimestampsConverted,
+		CompoundInterestsCalculated,
+		MediumConversions,
+		RateLimitedRequests,
+		CacheLookups,
+		DeprecatedRequests,
+	)
+}
+
+// Registry returns the registry holding the metrics of the api.
+func Registry() *prometheus.Registry {
+	return registry
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGin() *gin.Engine {
+	r := gin.Default()
+	r.Use(Middleware())
+	SetRouterGroup("/metrics", &r.RouterGroup)
+	r.GET("/v1/items/:id", func(c *gin.Context) {
+		c.String(http.StatusTeapot, "")
+	})
+
+	return r
+}
+
+func serve(r *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestMiddlewareUsesRouteTemplate(t *testing.T) {
+	// arrange
+	r := setupGin()
+	counter := httpRequestsTotal.WithLabelValues("GET", "/v1/items/:id", "418")
+	before := testutil.ToFloat64(counter)
+
+	// act
+	serve(r, "/v1/items/1")
+	serve(r, "/v1/items/2")
+
+	// assert
+	assert.Equal(t, before+2, testutil.ToFloat64(counter))
+	assert.Equal(t, float64(0), testutil.ToFloat64(httpRequestsInFlight))
+}
+
+func TestMiddlewareWithUnmatchedRoute(t *testing.T) {
+	// arrange
+	r := setupGin()
+	counter := httpRequestsTotal.WithLabelValues("GET", unmatchedRoute, "404")
+	before := testutil.ToFloat64(counter)
+
+	// act
+	serve(r, "/does/not/exist")
+
+	// assert
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	// arrange
+	r := setupGin()
+	UuidsGenerated.Inc()
+	serve(r, "/v1/items/1")
+
+	// act
+	w := serve(r, "/metrics")
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "canivete_programming_uuids_generated_total")
+	assert.Contains(t, w.Body.String(), `canivete_http_request_duration_seconds_bucket{method="GET",route="/v1/items/:id"`)
+	assert.Contains(t, w.Body.String(), "go_goroutines")
+}
+
+func TestOutcome(t *testing.T) {
+	assert.Equal(t, OutcomeSuccess, Outcome(nil))
+	assert.Equal(t, OutcomeFailure, Outcome(errors.New("fake error")))
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// unmatchedRoute labels the requests not matching any route, so that
+// random paths do not create new series.
+const unmatchedRoute = "unmatched"
+
+// Middleware records the http metrics of each request.
+// Requests are labelled with the route template (e.g. /v1/programming/uuid)
+// instead of the raw path.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		httpRequestsInFlight.Inc()
+		defer httpRequestsInFlight.Dec()
+
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		method := c.Request.Method
+		code := strconv.Itoa(c.Writer.Status())
+		httpRequestsTotal.WithLabelValues(method, route, code).Inc()
+		httpRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// SetRouterGroup adds the endpoint exposing the metrics in the Prometheus
+// text format to the base group.
+func SetRouterGroup(path string, base *gin.RouterGroup) *gin.RouterGroup {
+	handler := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	base.GET(path, gin.WrapH(handler))
+
+	return base
+}
+
+// Outcome returns the outcome label for an error.
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeFailure
+	}
+
+	return OutcomeSuccess
+}
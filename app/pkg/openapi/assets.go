@@ -0,0 +1,80 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package openapi
+
+import (
+	"embed"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+)
+
+//go:generate go run ./internal/fetchassets
+
+// EmbeddedAssetsUrl is the path the assets embedded in the binary are
+// served on, next to the api.
+const EmbeddedAssetsUrl = "/assets"
+
+// Assets are the pinned scripts and styles of the interactive
+// documentation and GraphiQL, by their path on the CDN.
+var Assets = []string{
+	"/swagger-ui-dist@4.1.3/swagger-ui.css",
+	"/swagger-ui-dist@4.1.3/swagger-ui-bundle.js",
+	"/graphiql@1.5.16/graphiql.min.css",
+	"/graphiql@1.5.16/graphiql.min.js",
+	"/react@17.0.2/umd/react.production.min.js",
+	"/react-dom@17.0.2/umd/react-dom.production.min.js",
+}
+
+//go:embed assets
+var assets embed.FS
+
+// AssetsEmbedded tells if every asset was fetched before the build, so
+// the pages can load them from EmbeddedAssetsUrl.
+func AssetsEmbedded() bool {
+	for _, asset := range Assets {
+		if _, err := assets.Open(path.Join("assets", asset)); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SetAssetsRouterGroup adds to the base group the endpoint serving the
+// embedded assets (/assets/*filepath). Their paths carry their versions,
+// so they are cached for good.
+func SetAssetsRouterGroup(base *gin.RouterGroup) *gin.RouterGroup {
+	base.GET(EmbeddedAssetsUrl+"/*filepath", getAsset)
+
+	return base
+}
+
+func getAsset(c *gin.Context) {
+	name := path.Join("assets", path.Clean("/"+c.Param("filepath")))
+	data, err := assets.ReadFile(name)
+	if err != nil || path.Base(name) == "README.md" {
+		apierrors.Abort(c, apierrors.New(http.StatusNotFound, apierrors.CodeNotFound, "no asset matches the request"))
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Data(http.StatusOK, mime.TypeByExtension(path.Ext(name)), data)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+// fetchassets downloads the pinned assets of the interactive
+// documentation and GraphiQL from the CDN, to embed them in the binary.
+// It runs in the directory of the openapi package, with go generate.
+package main
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/renato0307/canivete-api/pkg/openapi"
+)
+
+// cdn is where the assets are fetched from, with the paths they are
+// embedded with.
+const cdn = "https://unpkg.com"
+
+func main() {
+	client := &http.Client{Timeout: time.Minute}
+
+	for _, asset := range openapi.Assets {
+		resp, err := client.Get(cdn + asset)
+		if err != nil {
+			log.Fatalf("error fetching %s: %s\n", asset, err.Error())
+		}
+		data, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK {
+			log.Fatalf("error fetching %s: status %d\n", asset, resp.StatusCode)
+		}
+
+		name := filepath.Join("assets", filepath.FromSlash(asset))
+		if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+			log.Fatalf("error creating the directory of %s: %s\n", asset, err.Error())
+		}
+		if err := ioutil.WriteFile(name, data, 0644); err != nil {
+			log.Fatalf("error writing %s: %s\n", asset, err.Error())
+		}
+
+		// the hash to check against the integrity published for the version
+		digest := sha512.Sum384(data)
+		fmt.Printf("%s sha384-%s\n", asset, base64.StdEncoding.EncodeToString(digest[:]))
+	}
+}
@@ -0,0 +1,263 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+)
+
+const (
+	ContentTypeJson = "application/json"
+	ContentTypeText = "text/plain"
+)
+
+// Operation describes a route for the OpenAPI document.
+type Operation struct {
+	Summary     string
+	Description string
+	// Request is the body of the request, nil when there is none.
+	Request *Body
+	// Response is the body of a successful response.
+	Response Body
+	// Errors lists the status codes answered with an apierrors.ApiError.
+	Errors []int
+}
+
+// Body describes a request or a response body.
+type Body struct {
+	// ContentType defaults to application/json.
+	ContentType string
+	// Type is a value of the Go type the body is made of.
+	Type        interface{}
+	Description string
+	Example     interface{}
+}
+
+// Document is an OpenAPI 3 document.
+type Document struct {
+	OpenApi    string                          `json:"openapi"`
+	Info       Info                            `json:"info"`
+	Paths      map[string]map[string]operation `json:"paths"`
+	Components components                      `json:"components"`
+}
+
+// Info holds the metadata of the api.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+type operation struct {
+	Tags        []string            `json:"tags,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	OperationId string              `json:"operationId"`
+	RequestBody *requestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]response `json:"responses"`
+}
+
+type requestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required"`
+	Content     map[string]mediaType `json:"content"`
+}
+
+type response struct {
+	Description string               `json:"description"`
+	Content     map[string]mediaType `json:"content,omitempty"`
+}
+
+type mediaType struct {
+	Schema  *Schema     `json:"schema"`
+	Example interface{} `json:"example,omitempty"`
+}
+
+type route struct {
+	method string
+	path   string
+	tag    string
+	op     Operation
+}
+
+// Registry keeps the documented routes.
+type Registry struct {
+	mu     sync.Mutex
+	routes map[string]route
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{routes: map[string]route{}}
+}
+
+// Describe documents the route registered on the group with the method and
+// relative path. The last segment of the group path is used as tag.
+// Describing a route twice replaces the previous description.
+func (r *Registry) Describe(group *gin.RouterGroup, method, relativePath string, op Operation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	basePath := group.BasePath()
+	tag := basePath[strings.LastIndex(basePath, "/")+1:]
+	path := strings.TrimSuffix(basePath, "/") + relativePath
+
+	r.routes[method+" "+path] = route{method: method, path: path, tag: tag, op: op}
+}
+
+// Operation returns the description of the route with the method and full
+// path, like /v1/programming/uuid.
+func (r *Registry) Operation(method, path string) (Operation, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	route, ok := r.routes[method+" "+path]
+	return route.op, ok
+}
+
+var pathParameter = regexp.MustCompile(`[:*]([^/]+)`)
+
+// PathTemplate converts a gin path, like /tools/:name, to an OpenAPI path
+// template, like /tools/{name}.
+func PathTemplate(path string) string {
+	return pathParameter.ReplaceAllString(path, "{$1}")
+}
+
+// Document builds the OpenAPI document of the described routes under the
+// base path, like /v1, or of every route when it is empty.
+func (r *Registry) Document(info Info, basePath string) Document {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	doc := Document{
+		OpenApi:    "3.0.3",
+		Info:       info,
+		Paths:      map[string]map[string]operation{},
+		Components: components{Schemas: map[string]*Schema{}},
+	}
+
+	apiErrorSchema := schemaFor(apierrors.ApiError{}, doc.Components.Schemas)
+
+	prefix := strings.TrimSuffix(basePath, "/") + "/"
+	keys := []string{}
+	for key, route := range r.routes {
+		if basePath == "" || strings.HasPrefix(route.path, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		route := r.routes[key]
+		path := PathTemplate(route.path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = map[string]operation{}
+		}
+
+		doc.Paths[path][strings.ToLower(route.method)] = buildOperation(route, apiErrorSchema, doc.Components.Schemas)
+	}
+
+	return doc
+}
+
+func buildOperation(route route, apiErrorSchema *Schema, schemas map[string]*Schema) operation {
+	op := operation{
+		Tags:        []string{route.tag},
+		Summary:     route.op.Summary,
+		Description: route.op.Description,
+		OperationId: operationId(route),
+		Responses:   map[string]response{},
+	}
+
+	if route.op.Request != nil {
+		op.RequestBody = &requestBody{
+			Description: route.op.Request.Description,
+			Required:    true,
+			Content:     content(*route.op.Request, schemas),
+		}
+	}
+
+	op.Responses[strconv.Itoa(http.StatusOK)] = response{
+		Description: defaultString(route.op.Response.Description, http.StatusText(http.StatusOK)),
+		Content:     content(route.op.Response, schemas),
+	}
+
+	for _, status := range route.op.Errors {
+		op.Responses[strconv.Itoa(status)] = response{
+			Description: http.StatusText(status),
+			Content: map[string]mediaType{
+				apierrors.ContentType: {Schema: apiErrorSchema},
+			},
+		}
+	}
+
+	return op
+}
+
+func content(body Body, schemas map[string]*Schema) map[string]mediaType {
+	return map[string]mediaType{
+		defaultString(body.ContentType, ContentTypeJson): {
+			Schema:  schemaFor(body.Type, schemas),
+			Example: body.Example,
+		},
+	}
+}
+
+// operationId builds an id like postV1ProgrammingJwtDebugger.
+func operationId(route route) string {
+	id := strings.ToLower(route.method)
+	for _, part := range strings.FieldsFunc(route.path, func(r rune) bool {
+		return r == '/' || r == '-' || r == ':' || r == '*'
+	}) {
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+
+	return id
+}
+
+func defaultString(value, def string) string {
+	if value == "" {
+		return def
+	}
+
+	return value
+}
+
+var defaultRegistry = NewRegistry()
+
+// Describe documents a route in the default registry, served by the api.
+func Describe(group *gin.RouterGroup, method, relativePath string, op Operation) {
+	defaultRegistry.Describe(group, method, relativePath, op)
+}
+
+// DefaultRegistry returns the registry where the service groups describe
+// their routes.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
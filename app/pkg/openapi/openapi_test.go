@@ -0,0 +1,251 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/stretchr/testify/assert"
+)
+
+type testInput struct {
+	Name    string  `validate:"required"`
+	Amount  float64 `json:"amount" validate:"gt=0,required"`
+	Ignored string  `json:"-"`
+	hidden  string
+}
+
+type testOutput struct {
+	Items  []testItem
+	Labels map[string]interface{}
+	Parent *testOutput
+}
+
+type testItem struct {
+	Count int64
+}
+
+var info = Info{Title: "test", Version: "1.0.0"}
+
+func setupGin() (*gin.Engine, *Registry) {
+	registry := NewRegistry()
+	r := gin.Default()
+	v1 := r.Group("/v1")
+	SetRouterGroup(registry, info, v1)
+
+	tools := v1.Group("/tools")
+	registry.Describe(tools, http.MethodPost, "/run/:name", Operation{
+		Summary:  "Runs a tool",
+		Request:  &Body{Type: testInput{}},
+		Response: Body{Type: testOutput{}},
+		Errors:   []int{http.StatusBadRequest},
+	})
+	registry.Describe(tools, http.MethodGet, "/raw", Operation{
+		Response: Body{ContentType: ContentTypeText, Type: ""},
+	})
+
+	return r, registry
+}
+
+func TestDocument(t *testing.T) {
+	// arrange
+	_, registry := setupGin()
+
+	// act
+	doc := registry.Document(info, "")
+
+	// assert
+	assert.Equal(t, "3.0.3", doc.OpenApi)
+	assert.Len(t, doc.Paths, 2)
+
+	run := doc.Paths["/v1/tools/run/{name}"]["post"]
+	assert.Equal(t, "postV1ToolsRunName", run.OperationId)
+	assert.Equal(t, []string{"tools"}, run.Tags)
+	assert.Equal(t, "#/components/schemas/TestInput", run.RequestBody.Content[ContentTypeJson].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/ApiError", run.Responses["400"].Content[apierrors.ContentType].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/TestOutput", run.Responses["200"].Content[ContentTypeJson].Schema.Ref)
+
+	raw := doc.Paths["/v1/tools/raw"]["get"]
+	assert.Nil(t, raw.RequestBody)
+	assert.Equal(t, "string", raw.Responses["200"].Content[ContentTypeText].Schema.Type)
+}
+
+func TestDocumentOfBasePath(t *testing.T) {
+	// arrange
+	_, registry := setupGin()
+	v2 := gin.New().Group("/v2/tools")
+	registry.Describe(v2, http.MethodGet, "/raw", Operation{
+		Response: Body{ContentType: ContentTypeText, Type: ""},
+	})
+
+	// act
+	v1Doc := registry.Document(info, "/v1")
+	v2Doc := registry.Document(info, "/v2")
+	doc := registry.Document(info, "")
+
+	// assert
+	assert.Len(t, v1Doc.Paths, 2)
+	assert.NotContains(t, v1Doc.Paths, "/v2/tools/raw")
+	assert.Len(t, v2Doc.Paths, 1)
+	assert.Contains(t, v2Doc.Paths, "/v2/tools/raw")
+	assert.Len(t, doc.Paths, 3)
+}
+
+func TestDocumentSchemas(t *testing.T) {
+	// arrange
+	_, registry := setupGin()
+
+	// act
+	schemas := registry.Document(info, "").Components.Schemas
+
+	// assert
+	input := schemas["TestInput"]
+	assert.Equal(t, []string{"Name", "amount"}, input.Required)
+	assert.Len(t, input.Properties, 2)
+	assert.Equal(t, "number", input.Properties["amount"].Type)
+
+	output := schemas["TestOutput"]
+	assert.Equal(t, "array", output.Properties["Items"].Type)
+	assert.Equal(t, "#/components/schemas/TestItem", output.Properties["Items"].Items.Ref)
+	assert.Equal(t, true, output.Properties["Labels"].AdditionalProperties)
+	assert.Equal(t, "#/components/schemas/TestOutput", output.Properties["Parent"].Ref)
+
+	item := schemas["TestItem"]
+	assert.Equal(t, "integer", item.Properties["Count"].Type)
+	assert.Equal(t, "int64", item.Properties["Count"].Format)
+
+	assert.Contains(t, schemas, "ApiError")
+}
+
+func TestInlineSchema(t *testing.T) {
+	// act
+	schema := InlineSchema(testOutput{})
+
+	// assert
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, "integer", schema.Properties["Items"].Items.Properties["Count"].Type)
+	assert.Equal(t, "#/components/schemas/TestOutput", schema.Properties["Parent"].Ref)
+}
+
+func TestOperation(t *testing.T) {
+	// arrange
+	_, registry := setupGin()
+
+	// act
+	run, ok := registry.Operation(http.MethodPost, "/v1/tools/run/:name")
+	_, unknown := registry.Operation(http.MethodGet, "/v1/tools/run/:name")
+
+	// assert
+	assert.True(t, ok)
+	assert.Equal(t, "Runs a tool", run.Summary)
+	assert.False(t, unknown)
+}
+
+func TestGetDocument(t *testing.T) {
+	// arrange
+	r, _ := setupGin()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/openapi.json", nil)
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	doc := map[string]interface{}{}
+	err := json.Unmarshal(w.Body.Bytes(), &doc)
+	assert.Nil(t, err)
+	assert.Equal(t, "3.0.3", doc["openapi"])
+	assert.Contains(t, doc["paths"], "/v1/tools/raw")
+}
+
+func TestGetDocs(t *testing.T) {
+	// arrange
+	r, _ := setupGin()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/docs", nil)
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+	assert.Contains(t, w.Body.String(), "openapi.json")
+}
+
+func TestGetDocsLoadsTheAssetsFromTheAssetsUrl(t *testing.T) {
+	// arrange
+	r, _ := setupGin()
+	SetAssetsUrl("https://assets.example.com/")
+	t.Cleanup(func() { SetAssetsUrl(DefaultAssetsUrl) })
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/docs", nil)
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `src="https://assets.example.com/swagger-ui-dist@4.1.3/swagger-ui-bundle.js"`)
+	assert.NotContains(t, w.Body.String(), "unpkg.com")
+}
+
+func TestGetDocsLoadsTheEmbeddedAssetsByDefault(t *testing.T) {
+	// arrange
+	r, _ := setupGin()
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/docs", nil)
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `src="/assets/swagger-ui-dist@4.1.3/swagger-ui-bundle.js"`)
+}
+
+func TestGetAssetNotFound(t *testing.T) {
+	paths := []string{
+		"/assets/missing.js",
+		"/assets/README.md",
+		"/assets/../router.go",
+	}
+
+	for _, path := range paths {
+		// arrange
+		r := gin.New()
+		SetAssetsRouterGroup(&r.RouterGroup)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Equal(t, http.StatusNotFound, w.Code, path)
+		apiError, err := apierrors.Decode(w.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, apierrors.CodeNotFound, apiError.Code)
+	}
+}
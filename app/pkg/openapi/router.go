@@ -0,0 +1,95 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package openapi
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultAssetsUrl is where the interactive documentation loads its
+// scripts and styles from by default: the copies embedded in the binary.
+const DefaultAssetsUrl = EmbeddedAssetsUrl
+
+//go:embed docs.html
+var docsHtml string
+
+var docsTemplate = template.Must(template.New("docs").Parse(docsHtml))
+
+var (
+	assetsMu  sync.RWMutex
+	assetsUrl = DefaultAssetsUrl
+)
+
+// SetAssetsUrl changes the base url the interactive documentation loads its
+// scripts and styles from. It must serve the same paths as the CDN, like
+// /swagger-ui-dist@4.1.3/swagger-ui-bundle.js.
+func SetAssetsUrl(url string) {
+	assetsMu.Lock()
+	defer assetsMu.Unlock()
+
+	assetsUrl = strings.TrimSuffix(url, "/")
+}
+
+// AssetsUrl returns the base url of the assets of the interactive
+// documentation.
+func AssetsUrl() string {
+	assetsMu.RLock()
+	defer assetsMu.RUnlock()
+
+	return assetsUrl
+}
+
+// RenderPage renders an html page loading its assets from the assets url,
+// given to the template as its data.
+func RenderPage(c *gin.Context, page *template.Template) {
+	var html bytes.Buffer
+	err := page.Execute(&html, AssetsUrl())
+	if err != nil {
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", html.Bytes())
+}
+
+// SetRouterGroup adds to the base group the endpoints serving the OpenAPI
+// document (/openapi.json) and the interactive documentation (/docs).
+// The document lists the routes under the base group and is built on each
+// request, so it includes the routes described after this call.
+func SetRouterGroup(r *Registry, info Info, base *gin.RouterGroup) *gin.RouterGroup {
+	base.GET("/openapi.json", getDocument(r, info, base.BasePath()))
+	base.GET("/docs", getDocs)
+
+	return base
+}
+
+func getDocument(r *Registry, info Info, basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, r.Document(info, basePath))
+	}
+}
+
+func getDocs(c *gin.Context) {
+	RenderPage(c, docsTemplate)
+}
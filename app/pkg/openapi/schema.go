@@ -0,0 +1,194 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Schema is an OpenAPI 3 schema object.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+}
+
+// schemaFor returns the schema of the type of value.
+// Named structs are added to schemas and referenced.
+func schemaFor(value interface{}, schemas map[string]*Schema) *Schema {
+	return schemaForType(reflect.TypeOf(value), schemas)
+}
+
+// refPrefix prefixes the name of the schemas referenced in the components.
+const refPrefix = "#/components/schemas/"
+
+// InlineSchema returns the schema of the type of value, with the structs
+// expanded instead of referenced, for the documents without components.
+// Recursive types stay referenced where they recur.
+func InlineSchema(value interface{}) *Schema {
+	schemas := map[string]*Schema{}
+	return inline(schemaFor(value, schemas), schemas, map[string]bool{})
+}
+
+func inline(schema *Schema, schemas map[string]*Schema, expanding map[string]bool) *Schema {
+	if schema == nil {
+		return nil
+	}
+
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, refPrefix)
+		if expanding[name] {
+			return schema
+		}
+		expanding[name] = true
+		defer delete(expanding, name)
+		return inline(schemas[name], schemas, expanding)
+	}
+
+	expanded := *schema
+	if schema.Properties != nil {
+		expanded.Properties = map[string]*Schema{}
+		for name, property := range schema.Properties {
+			expanded.Properties[name] = inline(property, schemas, expanding)
+		}
+	}
+	expanded.Items = inline(schema.Items, schemas, expanding)
+	if additional, ok := schema.AdditionalProperties.(*Schema); ok {
+		expanded.AdditionalProperties = inline(additional, schemas, expanding)
+	}
+
+	return &expanded
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage{})
+
+func schemaForType(t reflect.Type, schemas map[string]*Schema) *Schema {
+	if t == rawMessageType {
+		// raw json accepts any value
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaForType(t.Elem(), schemas)
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: schemaForType(t.Elem(), schemas)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: additionalProperties(t.Elem(), schemas)}
+	case reflect.Struct:
+		return structSchema(t, schemas)
+	default:
+		// interface{} and friends accept any value
+		return &Schema{}
+	}
+}
+
+func additionalProperties(t reflect.Type, schemas map[string]*Schema) interface{} {
+	if t.Kind() == reflect.Interface {
+		return true
+	}
+
+	return schemaForType(t, schemas)
+}
+
+func structSchema(t reflect.Type, schemas map[string]*Schema) *Schema {
+	name := schemaName(t)
+	ref := &Schema{Ref: refPrefix + name}
+	if _, ok := schemas[name]; ok {
+		return ref
+	}
+
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	// registered before the fields, so recursive types end
+	schemas[name] = schema
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue // unexported
+		}
+
+		fieldName, skip := jsonName(field)
+		if skip {
+			continue
+		}
+
+		schema.Properties[fieldName] = schemaForType(field.Type, schemas)
+		if isRequired(field) {
+			schema.Required = append(schema.Required, fieldName)
+		}
+	}
+
+	return ref
+}
+
+// schemaName returns the name of a struct in the components, exported
+// even when the Go type is not.
+func schemaName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		return "Anonymous"
+	}
+
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// jsonName returns the name of the field once marshalled by encoding/json.
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+
+	name := strings.Split(tag, ",")[0]
+	if name == "" {
+		name = field.Name
+	}
+
+	return name, false
+}
+
+// isRequired tells if the field is required by the validator tags.
+func isRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("validate"), ",") {
+		if rule == "required" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package programming
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-api/pkg/limits"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-core/interface/programming"
+)
+
+var jwtType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Jwt",
+	Fields: graphql.Fields{
+		"header":  &graphql.Field{Type: graphql.NewNonNull(graphqlserver.JSON)},
+		"payload": &graphql.Field{Type: graphql.NewNonNull(graphqlserver.JSON)},
+	},
+})
+
+// RegisterGraphqlFields adds the programming tools to the GraphQL schema.
+func RegisterGraphqlFields(p programming.Interface, g *graphqlserver.Group) {
+	// the tokens are credentials
+	audit.SetRoutePolicy("Query.jwt", audit.Policy{Secrets: []string{"token"}})
+
+	g.Query("uuid", &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "Generates a random UUID",
+		Resolve: func(rp graphql.ResolveParams) (interface{}, error) {
+			var output programming.UuidOutput
+			err := limits.Call(rp.Context, func() error {
+				output = p.NewUuid()
+				return nil
+			})
+			if err != nil {
+				return nil, graphqlserver.Timeout()
+			}
+
+			metrics.UuidsGenerated.Inc()
+			return output.UUID, nil
+		},
+	})
+
+	g.Query("jwt", &graphql.Field{
+		Type:        jwtType,
+		Description: "Decodes the header and the payload of a JWT, without verifying it",
+		Args: graphql.FieldConfigArgument{
+			"token": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+		Resolve: func(rp graphql.ResolveParams) (interface{}, error) {
+			token, _ := rp.Args["token"].(string)
+
+			var output programming.JwtDebuggerOutput
+			err := limits.Call(rp.Context, func() (err error) {
+				output, err = p.DebugJwt(token)
+				return err
+			})
+			metrics.JwtsDebugged.WithLabelValues(metrics.Outcome(err)).Inc()
+			if limits.Exceeded(err) {
+				return nil, graphqlserver.Timeout()
+			}
+			if err != nil {
+				logging.FromContext(rp.Context).Debugw("error debugging a jwt", "error", err.Error())
+				return nil, graphqlserver.Error(apierrors.BadRequest(apierrors.CodeInvalidToken, err.Error()))
+			}
+
+			return map[string]interface{}{"header": output.Header, "payload": output.Payload}, nil
+		},
+	})
+}
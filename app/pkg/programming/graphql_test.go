@@ -0,0 +1,76 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package programming
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-core/interface/programming"
+	"github.com/stretchr/testify/assert"
+)
+
+func doGraphql(t *testing.T, p programming.Interface, query string) *graphql.Result {
+	s := graphqlserver.New(config.Default().Graphql, false)
+	RegisterGraphqlFields(p, s.Group(config.GroupProgramming))
+	schema, err := s.Schema()
+	assert.Nil(t, err)
+
+	return graphql.Do(graphql.Params{Schema: schema, RequestString: query, Context: context.Background()})
+}
+
+func TestGraphqlUuidAndJwt(t *testing.T) {
+	// arrange
+	serviceMock := programming.MockInterface{}
+	serviceMock.On("NewUuid").Return(programming.UuidOutput{UUID: "d967aaad-1df5-485d-96b4-43d4247972e7"})
+	serviceMock.On("DebugJwt", "token").Return(programming.JwtDebuggerOutput{
+		Header:  map[string]interface{}{"alg": "HS256"},
+		Payload: map[string]interface{}{"sub": "1234567890"},
+	}, nil)
+
+	// act
+	result := doGraphql(t, &serviceMock, `{ uuid jwt(token: "token") { header payload } }`)
+
+	// assert
+	assert.Empty(t, result.Errors)
+	data := result.Data.(map[string]interface{})
+	assert.Equal(t, "d967aaad-1df5-485d-96b4-43d4247972e7", data["uuid"])
+	assert.Equal(t, map[string]interface{}{
+		"header":  map[string]interface{}{"alg": "HS256"},
+		"payload": map[string]interface{}{"sub": "1234567890"},
+	}, data["jwt"])
+}
+
+func TestGraphqlJwtWithErrorFromCore(t *testing.T) {
+	// arrange
+	serviceMock := programming.MockInterface{}
+	serviceMock.On("DebugJwt", "token").Return(programming.JwtDebuggerOutput{}, errors.New("fake error"))
+
+	// act
+	result := doGraphql(t, &serviceMock, `{ jwt(token: "token") { header } }`)
+
+	// assert
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "fake error", result.Errors[0].Message)
+		assert.Equal(t, apierrors.CodeInvalidToken, result.Errors[0].Extensions["code"])
+	}
+}
@@ -0,0 +1,89 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package programming
+
+import (
+	"context"
+
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/grpcserver"
+	"github.com/renato0307/canivete-api/pkg/limits"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	canivetev1 "github.com/renato0307/canivete-api/pkg/pb/canivete/v1"
+	"github.com/renato0307/canivete-core/interface/programming"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// grpcService serves the programming tools over gRPC.
+type grpcService struct {
+	canivetev1.UnimplementedProgrammingServiceServer
+	p programming.Interface
+}
+
+// RegisterGrpcService registers the gRPC service of the programming tools.
+func RegisterGrpcService(p programming.Interface, registrar grpc.ServiceRegistrar) {
+	canivetev1.RegisterProgrammingServiceServer(registrar, &grpcService{p: p})
+
+	// the tokens are credentials
+	service := canivetev1.ProgrammingService_ServiceDesc.ServiceName
+	audit.SetRoutePolicy("/"+service+"/DebugJwt", audit.Policy{Secrets: []string{"token"}})
+}
+
+func (s *grpcService) NewUuid(ctx context.Context, req *canivetev1.NewUuidRequest) (*canivetev1.NewUuidResponse, error) {
+	var output programming.UuidOutput
+	err := limits.Call(ctx, func() error {
+		output = s.p.NewUuid()
+		return nil
+	})
+	if err != nil {
+		return nil, grpcserver.Timeout()
+	}
+
+	metrics.UuidsGenerated.Inc()
+	return &canivetev1.NewUuidResponse{Uuid: output.UUID}, nil
+}
+
+func (s *grpcService) DebugJwt(ctx context.Context, req *canivetev1.DebugJwtRequest) (*canivetev1.DebugJwtResponse, error) {
+	var output programming.JwtDebuggerOutput
+	err := limits.Call(ctx, func() (err error) {
+		output, err = s.p.DebugJwt(req.Token)
+		return err
+	})
+	metrics.JwtsDebugged.WithLabelValues(metrics.Outcome(err)).Inc()
+	if limits.Exceeded(err) {
+		return nil, grpcserver.Timeout()
+	}
+	if err != nil {
+		logging.FromContext(ctx).Debugw("error debugging a jwt", "error", err.Error())
+		return nil, grpcserver.Error(codes.InvalidArgument, apierrors.CodeInvalidToken, err.Error())
+	}
+
+	header, err := structpb.NewStruct(output.Header)
+	if err != nil {
+		return nil, grpcserver.Error(codes.InvalidArgument, apierrors.CodeInvalidToken, err.Error())
+	}
+	payload, err := structpb.NewStruct(output.Payload)
+	if err != nil {
+		return nil, grpcserver.Error(codes.InvalidArgument, apierrors.CodeInvalidToken, err.Error())
+	}
+
+	return &canivetev1.DebugJwtResponse{Header: header, Payload: payload}, nil
+}
@@ -0,0 +1,76 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package programming
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	canivetev1 "github.com/renato0307/canivete-api/pkg/pb/canivete/v1"
+	"github.com/renato0307/canivete-core/interface/programming"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGrpcNewUuid(t *testing.T) {
+	// arrange
+	serviceMock := programming.MockInterface{}
+	serviceMock.On("NewUuid").Return(programming.UuidOutput{UUID: "d967aaad-1df5-485d-96b4-43d4247972e7"})
+	s := grpcService{p: &serviceMock}
+
+	// act
+	resp, err := s.NewUuid(context.Background(), &canivetev1.NewUuidRequest{})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, "d967aaad-1df5-485d-96b4-43d4247972e7", resp.Uuid)
+}
+
+func TestGrpcDebugJwt(t *testing.T) {
+	// arrange
+	output := programming.JwtDebuggerOutput{
+		Header:  map[string]interface{}{"alg": "HS256"},
+		Payload: map[string]interface{}{"sub": "1234567890", "admin": true},
+	}
+	serviceMock := programming.MockInterface{}
+	serviceMock.On("DebugJwt", "token").Return(output, nil)
+	s := grpcService{p: &serviceMock}
+
+	// act
+	resp, err := s.DebugJwt(context.Background(), &canivetev1.DebugJwtRequest{Token: "token"})
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, output.Header, resp.Header.AsMap())
+	assert.Equal(t, output.Payload, resp.Payload.AsMap())
+}
+
+func TestGrpcDebugJwtWithErrorFromCore(t *testing.T) {
+	// arrange
+	serviceMock := programming.MockInterface{}
+	serviceMock.On("DebugJwt", "token").Return(programming.JwtDebuggerOutput{}, errors.New("fake error"))
+	s := grpcService{p: &serviceMock}
+
+	// act
+	_, err := s.DebugJwt(context.Background(), &canivetev1.DebugJwtRequest{Token: "token"})
+
+	// assert
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, "fake error", status.Convert(err).Message())
+}
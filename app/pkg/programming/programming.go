@@ -22,7 +22,14 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/cache"
+	"github.com/renato0307/canivete-api/pkg/limits"
 	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-api/pkg/openapi"
+	"github.com/renato0307/canivete-api/pkg/render"
+	"github.com/renato0307/canivete-api/pkg/tracing"
 	"github.com/renato0307/canivete-core/interface/programming"
 	"go.uber.org/zap"
 )
@@ -36,6 +43,28 @@ func SetRouterGroup(p programming.Interface, base *gin.RouterGroup) *gin.RouterG
 		programmingGroup.POST("/jwt-debugger", postJwtDebugger(p))
 	}
 
+	openapi.Describe(programmingGroup, http.MethodGet, "/uuid", openapi.Operation{
+		Summary:  "Generates a random UUID",
+		Response: openapi.Body{Type: programming.UuidOutput{}},
+	})
+	openapi.Describe(programmingGroup, http.MethodPost, "/jwt-debugger", openapi.Operation{
+		Summary:     "Decodes the header and the payload of a JWT",
+		Description: "The signature of the token is not verified.",
+		Request: &openapi.Body{
+			ContentType: openapi.ContentTypeText,
+			Type:        "",
+			Description: "The raw token, without the Bearer prefix.",
+			Example:     "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiIxMjM0NTY3ODkwIn0.sig",
+		},
+		Response: openapi.Body{Type: programming.JwtDebuggerOutput{}},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	})
+
+	// each uuid is new, and the tokens are credentials
+	cache.SetPolicy(programmingGroup, "/uuid", cache.Policy{CacheControl: cache.NoStore})
+	cache.SetPolicy(programmingGroup, "/jwt-debugger", cache.Policy{CacheControl: cache.NoStore})
+	audit.SetPolicy(programmingGroup, "/jwt-debugger", audit.Policy{Secrets: []string{"body"}})
+
 	return programmingGroup
 }
 
@@ -43,10 +72,21 @@ func SetRouterGroup(p programming.Interface, base *gin.RouterGroup) *gin.RouterG
 // It returns 200 on success.
 func getUuid(p programming.Interface) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		logger.Debugw("getting a new UUID")
-		output := p.NewUuid()
-		logger.Debugw("new UUID created", "uuid", output.UUID)
-		c.JSON(http.StatusOK, output)
+		logging.FromContext(c).Debugw("getting a new UUID")
+		span := tracing.StartSpan(c, "programming.NewUuid")
+		var output programming.UuidOutput
+		err := limits.Call(c.Request.Context(), func() error {
+			output = p.NewUuid()
+			return nil
+		})
+		tracing.EndSpan(span, err)
+		if err != nil {
+			apierrors.Abort(c, limits.Timeout())
+			return
+		}
+		metrics.UuidsGenerated.Inc()
+		logging.FromContext(c).Debugw("new UUID created", "uuid", output.UUID)
+		render.Render(c, http.StatusOK, output, func() string { return output.UUID })
 	}
 }
 
@@ -55,23 +95,34 @@ func getUuid(p programming.Interface) gin.HandlerFunc {
 func postJwtDebugger(p programming.Interface) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if c.Request.Body == nil {
-			c.JSON(http.StatusBadRequest, apierrors.ApiError{Message: "request body is invalid"})
+			apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidBody, "request body is invalid"))
 			return
 		}
 
 		tokenString, err := ioutil.ReadAll(c.Request.Body)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, apierrors.ApiError{Message: "request body is invalid"})
+			apierrors.Abort(c, apierrors.Internal(apierrors.CodeInternal, "request body is invalid"))
 			return
 		}
 
-		output, err := p.DebugJwt(string(tokenString))
+		span := tracing.StartSpan(c, "programming.DebugJwt")
+		var output programming.JwtDebuggerOutput
+		err = limits.Call(c.Request.Context(), func() (err error) {
+			output, err = p.DebugJwt(string(tokenString))
+			return err
+		})
+		tracing.EndSpan(span, err)
+		metrics.JwtsDebugged.WithLabelValues(metrics.Outcome(err)).Inc()
+		if limits.Exceeded(err) {
+			apierrors.Abort(c, limits.Timeout())
+			return
+		}
 		if err != nil {
-			logger.Debugw("error debugging a jwt", "error", err.Error())
-			c.JSON(http.StatusBadRequest, apierrors.ApiError{Message: err.Error()})
+			logging.FromContext(c).Debugw("error debugging a jwt", "error", err.Error())
+			apierrors.Abort(c, apierrors.BadRequest(apierrors.CodeInvalidToken, err.Error()))
 			return
 		}
 
-		c.JSON(200, output)
+		render.Render(c, http.StatusOK, output, nil)
 	}
 }
@@ -26,7 +26,9 @@ import (
 	"net/http/httptest"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/metrics"
 	"github.com/renato0307/canivete-core/interface/programming"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -57,12 +59,14 @@ func TestGetUuid(t *testing.T) {
 	r := setupGin(&serviceMock)
 	w := httptest.NewRecorder()
 	req, _ := http.NewRequest("GET", "/v1/programming/uuid", nil)
+	generated := testutil.ToFloat64(metrics.UuidsGenerated)
 
 	// act
 	r.ServeHTTP(w, req)
 
 	// assert
 	assert.Equal(t, w.Code, http.StatusOK)
+	assert.Equal(t, generated+1, testutil.ToFloat64(metrics.UuidsGenerated))
 }
 
 func TestPostJwtDebugger(t *testing.T) {
@@ -109,9 +113,10 @@ func TestPostJwtDebuggerNoBodyShouldReturn500(t *testing.T) {
 	// assert
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	expectedError := apierrors.ApiError{Message: "request body is invalid"}
-	apiError, _ := apierrors.FromResponseRecorder(w)
-	assert.Equal(t, expectedError, apiError)
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeInvalidBody, apiError.Code)
+	assert.Equal(t, "request body is invalid", apiError.Detail)
 }
 
 func TestPostJwtDebuggerShouldReturn500IfCoreFails(t *testing.T) {
@@ -132,7 +137,43 @@ func TestPostJwtDebuggerShouldReturn500IfCoreFails(t *testing.T) {
 	// assert
 	assert.Equal(t, http.StatusBadRequest, w.Code)
 
-	expectedError := apierrors.ApiError{Message: error.Error()}
-	apiError, _ := apierrors.FromResponseRecorder(w)
-	assert.Equal(t, expectedError, apiError)
+	apiError, err := apierrors.FromResponse(w.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeInvalidToken, apiError.Code)
+	assert.Equal(t, error.Error(), apiError.Detail)
+}
+
+func TestGetUuidAsText(t *testing.T) {
+	// arrange
+	uuid := "d967aaad-1df5-485d-96b4-43d4247972e7"
+	serviceMock := programming.MockInterface{}
+	serviceMock.On("NewUuid", mock.Anything).Return(programming.UuidOutput{UUID: uuid}, nil)
+
+	r := setupGin(&serviceMock)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/programming/uuid", nil)
+	req.Header.Set("Accept", "text/plain")
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, uuid+"\n", w.Body.String())
+}
+
+func TestPostJwtDebuggerAsText(t *testing.T) {
+	// arrange
+	serviceMock := programming.MockInterface{}
+	serviceMock.On("DebugJwt", mock.Anything).Return(programming.JwtDebuggerOutput{}, nil)
+
+	r := setupGin(&serviceMock)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/v1/programming/jwt-debugger?format=text", strings.NewReader("token"))
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
 }
@@ -0,0 +1,55 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package programming
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-api/pkg/tools"
+	programmingcore "github.com/renato0307/canivete-core/pkg/programming"
+	"google.golang.org/grpc"
+)
+
+func init() {
+	service := &programmingcore.Service{}
+	tools.Register(tools.Group{
+		Name:        config.GroupProgramming,
+		Description: "Tools for developers, like UUIDs and JWTs",
+		Tools: []tools.Tool{
+			{
+				Name: "uuid", Method: http.MethodGet, Path: "/uuid", Tags: []string{"generators"},
+				GraphqlField: "Query.uuid", GrpcMethod: "/canivete.v1.ProgrammingService/NewUuid",
+			},
+			{
+				Name: "jwt-debugger", Method: http.MethodPost, Path: "/jwt-debugger", Tags: []string{"decoders", "jwt"},
+				GraphqlField: "Query.jwt", GrpcMethod: "/canivete.v1.ProgrammingService/DebugJwt",
+			},
+		},
+		SetRouterGroup: func(base *gin.RouterGroup) *gin.RouterGroup {
+			return SetRouterGroup(service, base)
+		},
+		RegisterGrpcService: func(registrar grpc.ServiceRegistrar) {
+			RegisterGrpcService(service, registrar)
+		},
+		RegisterGraphqlFields: func(g *graphqlserver.Group) {
+			RegisterGraphqlFields(service, g)
+		},
+	})
+}
@@ -0,0 +1,183 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+)
+
+// Costs keeps the number of tokens taken by the requests to each route.
+type Costs struct {
+	mu    sync.Mutex
+	costs map[string]int
+}
+
+// NewCosts returns an empty cost registry, where every route costs 1.
+func NewCosts() *Costs {
+	return &Costs{costs: map[string]int{}}
+}
+
+// Set sets the cost of the route registered on the group with the
+// relative path.
+func (c *Costs) Set(group *gin.RouterGroup, relativePath string, cost int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.costs[strings.TrimSuffix(group.BasePath(), "/")+relativePath] = cost
+}
+
+// Get returns the cost of the route, 1 if it was not set.
+func (c *Costs) Get(route string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cost, ok := c.costs[route]
+	if !ok {
+		return 1
+	}
+
+	return cost
+}
+
+// Limiter takes the cost of the routes from the buckets of the callers.
+// It is shared by the http endpoints, the GraphQL fields and the gRPC
+// methods, so a caller has the same budget whatever the protocol.
+type Limiter struct {
+	store     Store
+	limit     Limit
+	costs     *Costs
+	overrides map[string]int
+}
+
+// NewLimiter returns a limiter keeping the buckets in the store.
+// overrides replaces the costs of the registry for some routes.
+func NewLimiter(store Store, limit Limit, costs *Costs, overrides map[string]int) *Limiter {
+	return &Limiter{store: store, limit: limit, costs: costs, overrides: overrides}
+}
+
+// Limit returns the size and the refill rate of the buckets.
+func (l *Limiter) Limit() Limit {
+	return l.limit
+}
+
+// Cost returns the cost of the route, lowered to the burst.
+func (l *Limiter) Cost(route string) int {
+	cost, ok := l.overrides[route]
+	if !ok {
+		cost = l.costs.Get(route)
+	}
+	// a request costing more than the burst would never be served
+	if cost > l.limit.Burst {
+		cost = l.limit.Burst
+	}
+
+	return cost
+}
+
+// Take takes the cost of the route from the bucket of the caller key.
+func (l *Limiter) Take(ctx context.Context, key string, route string) (Result, error) {
+	return l.store.Take(ctx, key, l.Cost(route), l.limit)
+}
+
+// Refused returns the error of the requests refused with the result and
+// the seconds to wait before retrying them, at least one.
+func Refused(result Result) (apierrors.ApiError, int) {
+	retryAfter := ceilSeconds(result.RetryAfter)
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+
+	detail := fmt.Sprintf("rate limit exceeded, retry in %d seconds", retryAfter)
+	return apierrors.New(http.StatusTooManyRequests, apierrors.CodeRateLimited, detail), retryAfter
+}
+
+// Middleware takes the cost of the route from the bucket of the caller and
+// answers 429 (TooManyRequests) if there are not enough tokens.
+// The caller is the identity set by auth.Middleware or the client ip.
+func Middleware(limiter *Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		result, err := limiter.Take(c.Request.Context(), key(c), route)
+		if err != nil {
+			// the store being down must not take the api down
+			logging.FromContext(c).Warnw("error taking rate limit tokens, allowing the request", "error", err.Error())
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(limiter.Limit().Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			apiError, retryAfter := Refused(result)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			metrics.RateLimitedRequests.WithLabelValues(route).Inc()
+			apierrors.Abort(c, apiError)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// key identifies the bucket of the caller.
+func key(c *gin.Context) string {
+	identity, ok := auth.GetIdentity(c)
+	return Key(identity, ok, c.ClientIP())
+}
+
+// Key identifies the bucket of a caller by its identity or, when it has
+// none or an unnamed one, by its ip, so the unnamed callers do not share
+// a single bucket.
+func Key(identity auth.Identity, ok bool, ip string) string {
+	if ok && identity.Name != "" {
+		return identity.Method + ":" + identity.Name
+	}
+
+	return "ip:" + ip
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+var defaultCosts = NewCosts()
+
+// SetCost sets the cost of a route in the default registry.
+func SetCost(group *gin.RouterGroup, relativePath string, cost int) {
+	defaultCosts.Set(group, relativePath, cost)
+}
+
+// DefaultCosts returns the registry where the service groups set the cost
+// of their routes.
+func DefaultCosts() *Costs {
+	return defaultCosts
+}
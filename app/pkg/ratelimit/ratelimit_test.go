@@ -0,0 +1,205 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, int, Limit) (Result, error) {
+	return Result{}, errors.New("fake error")
+}
+
+func TestMemoryStoreTakeAndRefill(t *testing.T) {
+	// arrange
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 2, Burst: 4}
+
+	// act
+	first, _ := store.Take(context.Background(), "ip:1", 3, limit)
+	refused, _ := store.Take(context.Background(), "ip:1", 3, limit)
+	other, _ := store.Take(context.Background(), "ip:2", 3, limit)
+	now = now.Add(time.Second)
+	refilled, _ := store.Take(context.Background(), "ip:1", 3, limit)
+
+	// assert
+	assert.Equal(t, Result{Allowed: true, Remaining: 1, Reset: 1500 * time.Millisecond}, first)
+	assert.False(t, refused.Allowed)
+	assert.Equal(t, time.Second, refused.RetryAfter)
+	assert.True(t, other.Allowed)
+	assert.True(t, refilled.Allowed)
+	assert.Equal(t, 0, refilled.Remaining)
+}
+
+func TestMemoryStoreSweepsFullBuckets(t *testing.T) {
+	// arrange
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	limit := Limit{Rate: 1, Burst: 10}
+	store.Take(context.Background(), "ip:1", 1, limit)
+
+	// act
+	now = now.Add(sweepInterval)
+	store.Take(context.Background(), "ip:2", 1, limit)
+
+	// assert
+	assert.NotContains(t, store.buckets, "ip:1")
+	assert.Contains(t, store.buckets, "ip:2")
+}
+
+func setupGin(store Store, overrides map[string]int) *gin.Engine {
+	costs := NewCosts()
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if caller := c.GetHeader("X-Caller"); caller != "" {
+			auth.SetIdentity(c, auth.Identity{Name: caller, Method: auth.MethodApiKey})
+		}
+	})
+	v1 := r.Group("/v1", Middleware(NewLimiter(store, Limit{Rate: 0.1, Burst: 10}, costs, overrides)))
+	v1.GET("/cheap", func(c *gin.Context) { c.String(http.StatusOK, "") })
+	v1.GET("/expensive", func(c *gin.Context) { c.String(http.StatusOK, "") })
+	costs.Set(v1, "/expensive", 6)
+
+	return r
+}
+
+func serve(r *gin.Engine, path, caller string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", path, nil)
+	if caller != "" {
+		req.Header.Set("X-Caller", caller)
+	}
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestMiddlewareTakesTheCostOfTheRoute(t *testing.T) {
+	// arrange
+	r := setupGin(NewMemoryStore(), nil)
+	limited := metrics.RateLimitedRequests.WithLabelValues("/v1/expensive")
+	before := testutil.ToFloat64(limited)
+
+	// act
+	expensive := serve(r, "/v1/expensive", "")
+	cheap := serve(r, "/v1/cheap", "")
+	refused := serve(r, "/v1/expensive", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, expensive.Code)
+	assert.Equal(t, "10", expensive.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "4", expensive.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", expensive.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "3", cheap.Header().Get("RateLimit-Remaining"))
+
+	assert.Equal(t, http.StatusTooManyRequests, refused.Code)
+	assert.Equal(t, "30", refused.Header().Get("Retry-After"))
+	assert.Equal(t, before+1, testutil.ToFloat64(limited))
+
+	apiError, err := apierrors.FromResponse(refused.Result())
+	assert.Nil(t, err)
+	assert.Equal(t, apierrors.CodeRateLimited, apiError.Code)
+}
+
+func TestMiddlewareKeysByCaller(t *testing.T) {
+	// arrange
+	r := setupGin(NewMemoryStore(), nil)
+
+	// act
+	serve(r, "/v1/expensive", "ci")
+	ci := serve(r, "/v1/expensive", "ci")
+	anonymous := serve(r, "/v1/expensive", "")
+
+	// assert
+	assert.Equal(t, http.StatusTooManyRequests, ci.Code)
+	assert.Equal(t, http.StatusOK, anonymous.Code)
+}
+
+func TestKeyFallsBackToTheIpForUnnamedIdentities(t *testing.T) {
+	// arrange
+	unnamed := auth.Identity{Method: auth.MethodJwt}
+
+	// act
+	first := Key(unnamed, true, "10.0.0.1")
+	second := Key(unnamed, true, "10.0.0.2")
+	named := Key(auth.Identity{Name: "ci", Method: auth.MethodJwt}, true, "10.0.0.1")
+	anonymous := Key(auth.Identity{}, false, "10.0.0.1")
+
+	// assert
+	assert.Equal(t, "ip:10.0.0.1", first)
+	assert.Equal(t, "ip:10.0.0.2", second)
+	assert.Equal(t, "jwt:ci", named)
+	assert.Equal(t, "ip:10.0.0.1", anonymous)
+}
+
+func TestMiddlewareWithOverrides(t *testing.T) {
+	// arrange
+	r := setupGin(NewMemoryStore(), map[string]int{"/v1/expensive": 20, "/v1/cheap": 0})
+
+	// act
+	free := serve(r, "/v1/cheap", "")
+	capped := serve(r, "/v1/expensive", "")
+
+	// assert
+	assert.Equal(t, "10", free.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, http.StatusOK, capped.Code, "costs above the burst are capped")
+	assert.Equal(t, "0", capped.Header().Get("RateLimit-Remaining"))
+}
+
+func TestMiddlewareAllowsWhenStoreFails(t *testing.T) {
+	// arrange
+	r := setupGin(failingStore{}, nil)
+
+	// act
+	w := serve(r, "/v1/cheap", "")
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestSetCost(t *testing.T) {
+	// arrange
+	r := gin.New()
+	group := r.Group("/v1/" + config.GroupInternet)
+
+	// act
+	SetCost(group, "/medium-to-md", 10)
+
+	// assert
+	assert.Equal(t, 10, DefaultCosts().Get("/v1/internet/medium-to-md"))
+	assert.Equal(t, 1, DefaultCosts().Get("/v1/programming/uuid"))
+}
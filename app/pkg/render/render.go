@@ -0,0 +1,269 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package render
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"gopkg.in/yaml.v2"
+)
+
+// Formats of the responses, as given in the format query parameter.
+const (
+	FormatJson = "json"
+	FormatYaml = "yaml"
+	FormatXml  = "xml"
+	FormatText = "text"
+	FormatCsv  = "csv"
+)
+
+// FormatQuery is the query parameter overriding the Accept header.
+const FormatQuery = "format"
+
+// Text returns the plain text form of an output, like the UUID alone, or
+// its CSV form.
+type Text func() string
+
+type format struct {
+	name        string
+	contentType string
+	// mediaTypes are the types of the Accept header matching the format.
+	mediaTypes []string
+}
+
+// structured are the formats of every output, in the order of preference,
+// used for wildcards.
+var structured = []format{
+	{FormatJson, "application/json", []string{"application/json"}},
+	{FormatYaml, "application/yaml", []string{"application/yaml", "application/x-yaml", "text/yaml"}},
+	{FormatXml, "application/xml", []string{"application/xml", "text/xml"}},
+}
+
+var (
+	textFormat = format{FormatText, "text/plain", []string{"text/plain"}}
+	csvFormat  = format{FormatCsv, "text/csv", []string{"text/csv"}}
+)
+
+// formats are all the formats, offered by some of the outputs.
+var formats = append(append([]format{}, structured...), textFormat, csvFormat)
+
+// Middleware answers with 406 (NotAcceptable) the requests accepting none
+// of the formats, before the tools are called.
+// The formats offered by each output are only known by Render.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := negotiate(c, formats); !ok {
+			apierrors.Abort(c, notAcceptable(c, formats))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Render writes output in the format asked by the client: the format query
+// parameter or else the Accept header, JSON when both are missing.
+// text is the plain text form of output, nil when there is none.
+// It answers with 406 (NotAcceptable) when no format can be written.
+func Render(c *gin.Context, status int, output interface{}, text Text) {
+	offered := structured
+	if text != nil {
+		offered = append(append([]format{}, structured...), textFormat)
+	}
+
+	render(c, status, output, offered, text)
+}
+
+// RenderCsv writes output like Render, with csv as its CSV form instead of
+// a plain text one.
+func RenderCsv(c *gin.Context, status int, output interface{}, csv Text) {
+	render(c, status, output, append(append([]format{}, structured...), csvFormat), csv)
+}
+
+func render(c *gin.Context, status int, output interface{}, offered []format, text Text) {
+	f, ok := negotiate(c, offered)
+	if !ok {
+		apierrors.Abort(c, notAcceptable(c, offered))
+		return
+	}
+
+	body, err := encode(f, output, text)
+	if err != nil {
+		// only xml has outputs without a form, like maps
+		apierrors.Abort(c, apierrors.New(
+			http.StatusNotAcceptable,
+			apierrors.CodeNotAcceptable,
+			fmt.Sprintf("the response cannot be written as %s", f.name),
+		))
+		return
+	}
+
+	c.Data(status, f.contentType+"; charset=utf-8", body)
+}
+
+// negotiate returns the offered format to write, if any.
+func negotiate(c *gin.Context, offered []format) (format, bool) {
+	if name, ok := c.GetQuery(FormatQuery); ok {
+		for _, f := range offered {
+			if f.name == strings.ToLower(name) {
+				return f, true
+			}
+		}
+		return format{}, false
+	}
+
+	accepted := parseAccept(c.GetHeader("Accept"))
+	if len(accepted) == 0 {
+		return offered[0], true
+	}
+
+	for _, mediaType := range accepted {
+		for _, f := range offered {
+			if f.matches(mediaType) {
+				return f, true
+			}
+		}
+	}
+
+	return format{}, false
+}
+
+// parseAccept returns the media types of an Accept header, from the most
+// to the least preferred, without the ones with a zero quality.
+func parseAccept(header string) []string {
+	type accepted struct {
+		mediaType string
+		quality   float64
+	}
+
+	parsed := []accepted{}
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+		if mediaType == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range params[1:] {
+			key, value := splitParam(param)
+			if key != "q" {
+				continue
+			}
+			if q, err := strconv.ParseFloat(value, 64); err == nil {
+				quality = q
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+
+		parsed = append(parsed, accepted{mediaType, quality})
+	}
+
+	sort.SliceStable(parsed, func(i, j int) bool {
+		return parsed[i].quality > parsed[j].quality
+	})
+
+	mediaTypes := []string{}
+	for _, a := range parsed {
+		mediaTypes = append(mediaTypes, a.mediaType)
+	}
+
+	return mediaTypes
+}
+
+func splitParam(param string) (string, string) {
+	parts := strings.SplitN(param, "=", 2)
+	if len(parts) != 2 {
+		return strings.TrimSpace(parts[0]), ""
+	}
+
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+}
+
+// matches tells if an accepted media type matches the format. Wildcards,
+// like text/*, only match its content type and not the aliases.
+func (f format) matches(accepted string) bool {
+	if accepted == "*/*" {
+		return true
+	}
+	if strings.HasSuffix(accepted, "/*") {
+		return strings.HasPrefix(f.contentType, strings.TrimSuffix(accepted, "*"))
+	}
+
+	for _, mediaType := range f.mediaTypes {
+		if accepted == mediaType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func encode(f format, output interface{}, text Text) ([]byte, error) {
+	switch f.name {
+	case FormatYaml:
+		// going through json keeps the keys and their order of the json form
+		jsonBody, err := json.Marshal(output)
+		if err != nil {
+			return nil, err
+		}
+		var generic yaml.MapSlice
+		if err := yaml.Unmarshal(jsonBody, &generic); err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(generic)
+	case FormatXml:
+		body, err := xml.Marshal(output)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(xml.Header), body...), nil
+	case FormatText, FormatCsv:
+		body := text()
+		if !strings.HasSuffix(body, "\n") {
+			body += "\n"
+		}
+		return []byte(body), nil
+	default:
+		return json.Marshal(output)
+	}
+}
+
+func notAcceptable(c *gin.Context, offered []format) apierrors.ApiError {
+	names := []string{}
+	for _, f := range offered {
+		names = append(names, f.name)
+	}
+
+	detail := fmt.Sprintf("accept header %q is not supported, the formats are %s",
+		c.GetHeader("Accept"), strings.Join(names, ", "))
+	if name, ok := c.GetQuery(FormatQuery); ok {
+		detail = fmt.Sprintf("format %q is not supported, the formats are %s", name, strings.Join(names, ", "))
+	}
+
+	return apierrors.New(http.StatusNotAcceptable, apierrors.CodeNotAcceptable, detail)
+}
@@ -0,0 +1,126 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package render
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/stretchr/testify/assert"
+)
+
+type output struct {
+	Name  string
+	Count int
+}
+
+func setupGin(text Text) *gin.Engine {
+	r := gin.New()
+	r.Use(Middleware())
+	r.GET("/output", func(c *gin.Context) {
+		Render(c, http.StatusOK, output{Name: "canivete", Count: 2}, text)
+	})
+	r.GET("/csv", func(c *gin.Context) {
+		RenderCsv(c, http.StatusOK, output{Name: "canivete", Count: 2}, func() string { return "name,count\ncanivete,2" })
+	})
+	r.GET("/map", func(c *gin.Context) {
+		Render(c, http.StatusOK, map[string]interface{}{"name": "canivete"}, nil)
+	})
+
+	return r
+}
+
+func serve(r *gin.Engine, path string, accept string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		path        string
+		accept      string
+		contentType string
+		body        string
+	}{
+		{"/output", "", "application/json; charset=utf-8", `{"Name":"canivete","Count":2}`},
+		{"/output", "*/*", "application/json; charset=utf-8", `{"Name":"canivete","Count":2}`},
+		{"/output", "application/x-yaml", "application/yaml; charset=utf-8", "Name: canivete\nCount: 2\n"},
+		{"/output", "text/html, application/xml;q=0.9", "application/xml; charset=utf-8",
+			`<?xml version="1.0" encoding="UTF-8"?>` + "\n<output><Name>canivete</Name><Count>2</Count></output>"},
+		{"/output", "application/json;q=0.5, text/*", "text/plain; charset=utf-8", "canivete\n"},
+		{"/output", "text/plain;q=0, */*", "application/json; charset=utf-8", `{"Name":"canivete","Count":2}`},
+		{"/output?format=YAML", "application/json", "application/yaml; charset=utf-8", "Name: canivete\nCount: 2\n"},
+		{"/output?format=text", "", "text/plain; charset=utf-8", "canivete\n"},
+		{"/csv", "text/csv", "text/csv; charset=utf-8", "name,count\ncanivete,2\n"},
+		{"/csv", "application/json;q=0.5, text/*", "text/csv; charset=utf-8", "name,count\ncanivete,2\n"},
+		{"/csv?format=csv", "", "text/csv; charset=utf-8", "name,count\ncanivete,2\n"},
+		{"/csv", "", "application/json; charset=utf-8", `{"Name":"canivete","Count":2}`},
+	}
+
+	for _, test := range tests {
+		// arrange
+		r := setupGin(func() string { return "canivete" })
+
+		// act
+		w := serve(r, test.path, test.accept)
+
+		// assert
+		assert.Equal(t, http.StatusOK, w.Code, "%s with %s", test.path, test.accept)
+		assert.Equal(t, test.contentType, w.Header().Get("Content-Type"), "%s with %s", test.path, test.accept)
+		assert.Equal(t, test.body, w.Body.String(), "%s with %s", test.path, test.accept)
+	}
+}
+
+func TestRenderNotAcceptable(t *testing.T) {
+	tests := []struct {
+		path   string
+		accept string
+		text   Text
+	}{
+		{"/output", "image/png", func() string { return "canivete" }},
+		{"/output?format=csv", "", func() string { return "canivete" }},
+		{"/output", "text/plain", nil},
+		{"/output?format=text", "", nil},
+		{"/output", "text/csv", func() string { return "canivete" }},
+		{"/csv", "text/plain", nil},
+		{"/csv?format=text", "", nil},
+		{"/map", "application/xml", nil},
+	}
+
+	for _, test := range tests {
+		// arrange
+		r := setupGin(test.text)
+
+		// act
+		w := serve(r, test.path, test.accept)
+
+		// assert
+		assert.Equal(t, http.StatusNotAcceptable, w.Code, "%s with %s", test.path, test.accept)
+		apiError, err := apierrors.FromResponse(w.Result())
+		assert.Nil(t, err)
+		assert.Equal(t, apierrors.CodeNotAcceptable, apiError.Code)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// DefaultHeader is the header carrying the request id.
+const DefaultHeader = "X-Request-ID"
+
+// ginKey is the key of the request id in the gin context.
+const ginKey = "canivete-api/request-id"
+
+type contextKey struct{}
+
+// value is stored in the request context, with the header name so the
+// id is forwarded with the same header it was received with.
+type value struct {
+	header string
+	id     string
+}
+
+// validId restricts the ids accepted from the clients, as they end up in
+// the logs and in the responses.
+var validId = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// Middleware reads the request id from the header, or generates one when
+// it is missing or invalid, and sets it on the response.
+// The id is available to the handlers with Get and to the calls made with
+// the request context with FromContext.
+func Middleware(header string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, id := NewContext(c.Request.Context(), header, c.GetHeader(header))
+
+		c.Set(ginKey, id)
+		c.Request = c.Request.WithContext(ctx)
+		c.Header(header, id)
+
+		c.Next()
+	}
+}
+
+// Get returns the id of the request, empty if Middleware is not used.
+func Get(c *gin.Context) string {
+	return c.GetString(ginKey)
+}
+
+// FromContext returns the request id stored in the context, empty if
+// there is none.
+func FromContext(ctx context.Context) string {
+	v, _ := ctx.Value(contextKey{}).(value)
+	return v.id
+}
+
+// HeaderFromContext returns the header the request id stored in the
+// context was received with, empty if there is none.
+func HeaderFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(contextKey{}).(value)
+	return v.header
+}
+
+// NewContext returns a context carrying the id received with the header,
+// or a generated one when it is missing or invalid, and the id.
+func NewContext(ctx context.Context, header, received string) (context.Context, string) {
+	id := received
+	if !validId.MatchString(id) {
+		id = uuid.NewString()
+	}
+
+	return context.WithValue(ctx, contextKey{}, value{header: header, id: id}), id
+}
+
+// Transport forwards the request id of the context to the outbound
+// requests.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport returns a Transport delegating to base.
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{Base: base}
+}
+
+// RoundTrip sets the request id header, unless it is already set, and
+// sends the request with the base transport.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	v, ok := req.Context().Value(contextKey{}).(value)
+	if ok && req.Header.Get(v.header) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(v.header, v.id)
+	}
+
+	return t.Base.RoundTrip(req)
+}
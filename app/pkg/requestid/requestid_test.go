@@ -0,0 +1,113 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package requestid
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGin(header string) (*gin.Engine, *string) {
+	var got string
+
+	r := gin.New()
+	r.Use(Middleware(header))
+	r.GET("/v1/tools", func(c *gin.Context) {
+		got = Get(c)
+		c.String(http.StatusOK, FromContext(c.Request.Context()))
+	})
+
+	return r, &got
+}
+
+func TestMiddlewareKeepsValidId(t *testing.T) {
+	// arrange
+	r, got := setupGin(DefaultHeader)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/tools", nil)
+	req.Header.Set(DefaultHeader, "fake-request-id")
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, "fake-request-id", *got)
+	assert.Equal(t, "fake-request-id", w.Body.String())
+	assert.Equal(t, "fake-request-id", w.Header().Get(DefaultHeader))
+}
+
+func TestMiddlewareGeneratesId(t *testing.T) {
+	tests := []string{"", "invalid id", strings.Repeat("a", 129)}
+
+	for _, id := range tests {
+		// arrange
+		r, got := setupGin(DefaultHeader)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/v1/tools", nil)
+		req.Header.Set(DefaultHeader, id)
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Len(t, *got, 36, "expected a uuid for %q", id)
+		assert.Equal(t, *got, w.Header().Get(DefaultHeader))
+	}
+}
+
+func TestMiddlewareWithCustomHeader(t *testing.T) {
+	// arrange
+	r, got := setupGin("X-Correlation-ID")
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/tools", nil)
+	req.Header.Set("X-Correlation-ID", "fake-correlation-id")
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, "fake-correlation-id", *got)
+	assert.Equal(t, "fake-correlation-id", w.Header().Get("X-Correlation-ID"))
+	assert.Empty(t, w.Header().Get(DefaultHeader))
+}
+
+func TestTransportForwardsId(t *testing.T) {
+	// arrange
+	var forwarded string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.Header.Get("X-Correlation-ID")
+	}))
+	defer upstream.Close()
+
+	client := http.Client{Transport: NewTransport(http.DefaultTransport)}
+	ctx, _ := NewContext(httptest.NewRequest("GET", "/", nil).Context(), "X-Correlation-ID", "fake-correlation-id")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, upstream.URL, nil)
+
+	// act
+	resp, err := client.Do(req)
+
+	// assert
+	assert.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "fake-correlation-id", forwarded)
+	assert.Empty(t, req.Header.Get("X-Correlation-ID"), "the original request must not be changed")
+}
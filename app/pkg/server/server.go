@@ -0,0 +1,116 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/shutdown"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+var logger *zap.SugaredLogger = logging.GetLogger()
+
+// Server is an http server which drains its connections before stopping.
+type Server struct {
+	httpServer           *http.Server
+	shutdownTimeout      time.Duration
+	shutdownHooksTimeout time.Duration
+	shutdownHooks        func(ctx context.Context) error
+}
+
+// New creates a server for the handler using the server configuration.
+// It serves TLS when tlsConfig is not nil. The hooks registered in the
+// shutdown package run when the server stops.
+func New(cfg config.ServerConfig, handler http.Handler, tlsConfig *tls.Config) *Server {
+	return &Server{
+		httpServer: &http.Server{
+			Addr:              cfg.Address,
+			Handler:           handler,
+			TLSConfig:         tlsConfig,
+			ReadTimeout:       cfg.ReadTimeout,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+			WriteTimeout:      cfg.WriteTimeout,
+			IdleTimeout:       cfg.IdleTimeout,
+		},
+		shutdownTimeout:      cfg.ShutdownTimeout,
+		shutdownHooksTimeout: cfg.ShutdownHooksTimeout,
+		shutdownHooks:        shutdown.Run,
+	}
+}
+
+// Run listens on the configured address and serves until ctx is done.
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ctx, listener)
+}
+
+// Serve accepts connections on the listener until ctx is done.
+// It then stops accepting new connections, waits up to the shutdown
+// timeout for in-flight requests to complete and runs the shutdown hooks,
+// which have their own timeout, so a slow drain does not cancel them.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	errs := make(chan error, 1)
+	go func() {
+		if s.httpServer.TLSConfig != nil {
+			// the certificates are served by the tls configuration
+			logger.Infow("server listening with tls", "address", listener.Addr().String())
+			errs <- s.httpServer.ServeTLS(listener, "", "")
+			return
+		}
+		logger.Infow("server listening", "address", listener.Addr().String())
+		errs <- s.httpServer.Serve(listener)
+	}()
+
+	var err error
+	select {
+	case err = <-errs:
+		// the server stopped on its own, there is nothing to drain
+	case <-ctx.Done():
+		logger.Infow("shutting down server", "timeout", s.shutdownTimeout.String())
+	}
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancelDrain()
+
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	if shutdownErr := s.httpServer.Shutdown(drainCtx); shutdownErr != nil {
+		logger.Warnw("connections were not drained before the timeout", "error", shutdownErr.Error())
+		err = multierr.Append(err, shutdownErr)
+	}
+
+	logger.Infow("server stopped, running shutdown hooks", "timeout", s.shutdownHooksTimeout.String())
+	hooksCtx, cancelHooks := context.WithTimeout(context.Background(), s.shutdownHooksTimeout)
+	defer cancelHooks()
+	err = multierr.Append(err, s.shutdownHooks(hooksCtx))
+
+	return err
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupServer creates a server whose handler blocks until release is closed
+// and starts serving it until ctx is done.
+func setupServer(t *testing.T, ctx context.Context, shutdownTimeout time.Duration) (string, chan struct{}, chan struct{}, chan error, *bool) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cfg := config.Default().Server
+	cfg.ShutdownTimeout = shutdownTimeout
+	s := New(cfg, handler, nil)
+
+	hooksCalled := false
+	s.shutdownHooks = func(ctx context.Context) error {
+		// the hooks get a live context, even after a slow drain
+		hooksCalled = ctx.Err() == nil
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Serve(ctx, listener)
+	}()
+
+	return "http://" + listener.Addr().String(), started, release, done, &hooksCalled
+}
+
+func TestServeDrainsInFlightRequests(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	url, started, release, done, hooksCalled := setupServer(t, ctx, 5*time.Second)
+
+	responses := make(chan int, 1)
+	go func() {
+		resp, err := http.Get(url)
+		if err != nil {
+			responses <- 0
+			return
+		}
+		resp.Body.Close()
+		responses <- resp.StatusCode
+	}()
+	<-started
+
+	// act
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	// assert
+	assert.Equal(t, http.StatusOK, <-responses)
+	assert.Nil(t, <-done)
+	assert.True(t, *hooksCalled)
+}
+
+func TestServeReturnsErrorWhenDrainTimesOut(t *testing.T) {
+	// arrange
+	ctx, cancel := context.WithCancel(context.Background())
+	url, started, release, done, hooksCalled := setupServer(t, ctx, 50*time.Millisecond)
+	defer close(release)
+
+	go func() {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	// act
+	cancel()
+
+	// assert
+	assert.ErrorIs(t, <-done, context.DeadlineExceeded)
+	assert.True(t, *hooksCalled)
+}
+
+func TestRunFailsWhenAddressIsInvalid(t *testing.T) {
+	// arrange
+	cfg := config.Default().Server
+	cfg.Address = "invalid address"
+	s := New(cfg, http.NotFoundHandler(), nil)
+
+	// act
+	err := s.Run(context.Background())
+
+	// assert
+	assert.NotNil(t, err)
+}
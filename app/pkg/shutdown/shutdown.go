@@ -0,0 +1,87 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package shutdown
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/multierr"
+)
+
+// Hook releases the resources held by a package when the server stops.
+type Hook func(ctx context.Context) error
+
+// Registry keeps the hooks to run on shutdown.
+type Registry struct {
+	mu    sync.Mutex
+	names []string
+	hooks map[string]Hook
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{hooks: map[string]Hook{}}
+}
+
+// Register adds a hook to the registry.
+// Registering a name twice replaces the previous hook.
+func (r *Registry) Register(name string, hook Hook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.hooks[name]; !ok {
+		r.names = append(r.names, name)
+	}
+	r.hooks[name] = hook
+}
+
+// Run calls every hook, in reverse order of registration.
+// All hooks are called even if some fail; their errors are combined.
+func (r *Registry) Run(ctx context.Context) error {
+	r.mu.Lock()
+	names := append([]string{}, r.names...)
+	hooks := map[string]Hook{}
+	for name, hook := range r.hooks {
+		hooks[name] = hook
+	}
+	r.mu.Unlock()
+
+	var err error
+	for i := len(names) - 1; i >= 0; i-- {
+		name := names[i]
+		hookErr := hooks[name](ctx)
+		if hookErr != nil {
+			err = multierr.Append(err, fmt.Errorf("shutdown hook %s failed: %s", name, hookErr.Error()))
+		}
+	}
+
+	return err
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register adds a hook to the default registry, used by the server.
+func Register(name string, hook Hook) {
+	defaultRegistry.Register(name, hook)
+}
+
+// Run calls the hooks of the default registry.
+func Run(ctx context.Context) error {
+	return defaultRegistry.Run(ctx)
+}
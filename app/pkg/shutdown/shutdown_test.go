@@ -0,0 +1,86 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCallsHooksInReverseOrder(t *testing.T) {
+	// arrange
+	calls := []string{}
+	registry := NewRegistry()
+	for _, name := range []string{"a", "b", "c"} {
+		name := name
+		registry.Register(name, func(ctx context.Context) error {
+			calls = append(calls, name)
+			return nil
+		})
+	}
+
+	// act
+	err := registry.Run(context.Background())
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"c", "b", "a"}, calls)
+}
+
+func TestRegisterTwiceReplacesHook(t *testing.T) {
+	// arrange
+	calls := []string{}
+	registry := NewRegistry()
+	registry.Register("a", func(ctx context.Context) error {
+		calls = append(calls, "first")
+		return nil
+	})
+	registry.Register("a", func(ctx context.Context) error {
+		calls = append(calls, "second")
+		return nil
+	})
+
+	// act
+	err := registry.Run(context.Background())
+
+	// assert
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"second"}, calls)
+}
+
+func TestRunCallsAllHooksOnError(t *testing.T) {
+	// arrange
+	called := false
+	registry := NewRegistry()
+	registry.Register("ok", func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	registry.Register("failing", func(ctx context.Context) error {
+		return errors.New("fake error")
+	})
+
+	// act
+	err := registry.Run(context.Background())
+
+	// assert
+	assert.True(t, called)
+	assert.EqualError(t, err, "shutdown hook failing failed: fake error")
+}
@@ -0,0 +1,138 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/graphqlserver"
+	"github.com/renato0307/canivete-api/pkg/versioning"
+	"google.golang.org/grpc"
+)
+
+// Tool is a tool served by a service group.
+type Tool struct {
+	// Name identifies the tool in the catalogue, like uuid.
+	Name string
+	// Method and Path are the REST route, relative to the group.
+	Method string
+	Path   string
+	// GraphqlField and GrpcMethod are the other calls of the tool, if
+	// any, like Query.uuid and /canivete.v1.ProgrammingService/NewUuid.
+	GraphqlField string
+	GrpcMethod   string
+	// Tags classify the tool, besides the name of its group.
+	Tags []string
+}
+
+// Group is a service group, registered by its package. The routes it
+// describes with openapi.Describe give the summary and the schemas of
+// its tools in the catalogue.
+type Group struct {
+	// Name is the name of the group in the configuration, the scope its
+	// callers need and the base path of its routes.
+	Name        string
+	Description string
+	Tools       []Tool
+	// SetRouterGroup mounts the REST routes of the group on base.
+	SetRouterGroup func(base *gin.RouterGroup) *gin.RouterGroup
+	// Versions mounts the REST routes of the group changed in a later
+	// version of the api, by version, like v2. They share the services of
+	// SetRouterGroup and keep the paths of the tools.
+	Versions map[string]func(base *gin.RouterGroup) *gin.RouterGroup
+	// RegisterGrpcService registers the gRPC service of the group, if any.
+	RegisterGrpcService func(registrar grpc.ServiceRegistrar)
+	// RegisterGraphqlFields adds the fields of the group to the GraphQL
+	// schema, if any.
+	RegisterGraphqlFields func(g *graphqlserver.Group)
+}
+
+// RouterGroup returns the function mounting the REST routes of the group
+// in the version of the api: the routes of the version, or else of the
+// closest earlier version changing them, or else SetRouterGroup.
+func (g Group) RouterGroup(version string) func(base *gin.RouterGroup) *gin.RouterGroup {
+	setRouterGroup := g.SetRouterGroup
+	for _, v := range versioning.Versions {
+		if versionRouterGroup, ok := g.Versions[v]; ok {
+			setRouterGroup = versionRouterGroup
+		}
+		if v == version {
+			break
+		}
+	}
+
+	return setRouterGroup
+}
+
+// Registry keeps the service groups of the api.
+type Registry struct {
+	mu     sync.Mutex
+	groups map[string]Group
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{groups: map[string]Group{}}
+}
+
+// Register adds the group to the registry. It panics if the group has no
+// name, no routes or the name of another group, like sql.Register.
+func (r *Registry) Register(group Group) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if group.Name == "" || group.SetRouterGroup == nil {
+		panic("tools: a group needs a name and routes")
+	}
+	if _, ok := r.groups[group.Name]; ok {
+		panic(fmt.Sprintf("tools: group %s registered twice", group.Name))
+	}
+
+	r.groups[group.Name] = group
+}
+
+// Groups returns the registered groups, sorted by name.
+func (r *Registry) Groups() []Group {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	groups := []Group{}
+	for _, group := range r.groups {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].Name < groups[j].Name
+	})
+
+	return groups
+}
+
+var defaultRegistry = NewRegistry()
+
+// Register adds the group to the default registry, iterated by the api.
+// The packages of the groups call it from their init function.
+func Register(group Group) {
+	defaultRegistry.Register(group)
+}
+
+// DefaultRegistry returns the registry where the service groups register.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
@@ -0,0 +1,169 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package tools
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/openapi"
+	"github.com/renato0307/canivete-api/pkg/versioning"
+	"github.com/stretchr/testify/assert"
+)
+
+type testOutput struct {
+	Value string
+}
+
+func testGroup(name string, docs *openapi.Registry) Group {
+	return Group{
+		Name:        name,
+		Description: "The " + name + " tools",
+		Tools: []Tool{
+			{Name: "echo", Method: http.MethodPost, Path: "/echo", Tags: []string{"text"}},
+			{Name: "ping", Method: http.MethodGet, Path: "/ping"},
+		},
+		SetRouterGroup: func(base *gin.RouterGroup) *gin.RouterGroup {
+			group := base.Group("/" + name)
+			group.POST("/echo", func(c *gin.Context) {})
+			docs.Describe(group, http.MethodPost, "/echo", openapi.Operation{
+				Summary:  "Echoes the body",
+				Request:  &openapi.Body{ContentType: openapi.ContentTypeText, Type: "", Example: "hi"},
+				Response: openapi.Body{Type: testOutput{}},
+			})
+			return group
+		},
+	}
+}
+
+func setupGin(t *testing.T, served func(name string) bool) *gin.Engine {
+	registry := NewRegistry()
+	docs := openapi.NewRegistry()
+	registry.Register(testGroup("beta", docs))
+	registry.Register(testGroup("alpha", docs))
+
+	r := gin.New()
+	v1 := r.Group("/v1")
+	for _, group := range registry.Groups() {
+		group.SetRouterGroup(v1)
+	}
+	SetRouterGroup(registry, served, docs, v1)
+
+	return r
+}
+
+func serveCatalogue(t *testing.T, r *gin.Engine, url string) CatalogueOutput {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	catalogue := CatalogueOutput{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &catalogue))
+	return catalogue
+}
+
+func TestRegister(t *testing.T) {
+	// arrange
+	registry := NewRegistry()
+	docs := openapi.NewRegistry()
+
+	// act
+	registry.Register(testGroup("beta", docs))
+	registry.Register(testGroup("alpha", docs))
+
+	// assert
+	groups := registry.Groups()
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "alpha", groups[0].Name)
+	assert.Equal(t, "beta", groups[1].Name)
+	assert.Panics(t, func() { registry.Register(testGroup("alpha", docs)) })
+	assert.Panics(t, func() { registry.Register(Group{Name: "empty"}) })
+}
+
+func TestRouterGroupOfVersion(t *testing.T) {
+	// arrange
+	docs := openapi.NewRegistry()
+	group := testGroup("alpha", docs)
+	group.Versions = map[string]func(base *gin.RouterGroup) *gin.RouterGroup{
+		versioning.V2: func(base *gin.RouterGroup) *gin.RouterGroup {
+			return base.Group("/alpha-v2")
+		},
+	}
+	r := gin.New()
+
+	// act
+	v1 := group.RouterGroup(versioning.V1)(r.Group("/v1"))
+	v2 := group.RouterGroup(versioning.V2)(r.Group("/v2"))
+	unversioned := testGroup("beta", docs).RouterGroup(versioning.V2)(r.Group("/v2"))
+
+	// assert
+	assert.Equal(t, "/v1/alpha", v1.BasePath())
+	assert.Equal(t, "/v2/alpha-v2", v2.BasePath())
+	assert.Equal(t, "/v2/beta", unversioned.BasePath())
+}
+
+func TestGetCatalogue(t *testing.T) {
+	// arrange
+	r := setupGin(t, func(name string) bool { return name == "alpha" })
+
+	// act
+	catalogue := serveCatalogue(t, r, "/v1/tools")
+
+	// assert
+	assert.Equal(t, []GroupOutput{{Name: "alpha", Description: "The alpha tools"}}, catalogue.Groups)
+	if assert.Len(t, catalogue.Tools, 2) {
+		echo := catalogue.Tools[0]
+		assert.Equal(t, "echo", echo.Name)
+		assert.Equal(t, "alpha", echo.Group)
+		assert.Equal(t, "Echoes the body", echo.Summary)
+		assert.Equal(t, http.MethodPost, echo.Method)
+		assert.Equal(t, "/v1/alpha/echo", echo.Path)
+		assert.Equal(t, []string{"alpha", "text"}, echo.Tags)
+		assert.Equal(t, openapi.ContentTypeText, echo.Input.ContentType)
+		assert.Equal(t, "string", echo.Input.Schema.Type)
+		assert.Equal(t, "hi", echo.Input.Example)
+		assert.Equal(t, openapi.ContentTypeJson, echo.Output.ContentType)
+		assert.Equal(t, "string", echo.Output.Schema.Properties["Value"].Type)
+
+		// undescribed routes are listed without their schemas
+		ping := catalogue.Tools[1]
+		assert.Equal(t, "/v1/alpha/ping", ping.Path)
+		assert.Nil(t, ping.Input)
+		assert.Nil(t, ping.Output)
+	}
+}
+
+func TestGetCatalogueByTag(t *testing.T) {
+	// arrange
+	r := setupGin(t, func(name string) bool { return true })
+
+	// act
+	text := serveCatalogue(t, r, "/v1/tools?tag=text")
+	beta := serveCatalogue(t, r, "/v1/tools?tag=beta")
+	unknown := serveCatalogue(t, r, "/v1/tools?tag=unknown")
+
+	// assert
+	assert.Len(t, text.Tools, 2)
+	assert.Len(t, beta.Tools, 2)
+	assert.Equal(t, "beta", beta.Tools[0].Group)
+	assert.Empty(t, unknown.Tools)
+	assert.Len(t, unknown.Groups, 2)
+}
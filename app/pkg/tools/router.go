@@ -0,0 +1,148 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package tools
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/openapi"
+)
+
+// CatalogueOutput lists the tools served by the api.
+type CatalogueOutput struct {
+	Groups []GroupOutput `json:"groups"`
+	Tools  []ToolOutput  `json:"tools"`
+}
+
+// GroupOutput describes a service group.
+type GroupOutput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ToolOutput describes a tool and how to call it.
+type ToolOutput struct {
+	Name        string      `json:"name"`
+	Group       string      `json:"group"`
+	Summary     string      `json:"summary,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Method      string      `json:"method"`
+	Path        string      `json:"path"`
+	Tags        []string    `json:"tags"`
+	Input       *BodyOutput `json:"input,omitempty"`
+	Output      *BodyOutput `json:"output,omitempty"`
+}
+
+// BodyOutput describes the body a tool takes or returns.
+type BodyOutput struct {
+	ContentType string          `json:"contentType"`
+	Description string          `json:"description,omitempty"`
+	Schema      *openapi.Schema `json:"schema"`
+	Example     interface{}     `json:"example,omitempty"`
+}
+
+// SetRouterGroup adds to the base group, where the groups are mounted, the
+// /tools endpoint listing the tools of the groups served. The catalogue is
+// built on each request, from the routes described in docs.
+func SetRouterGroup(r *Registry, served func(name string) bool, docs *openapi.Registry, base *gin.RouterGroup) *gin.RouterGroup {
+	base.GET("/tools", getCatalogue(r, served, docs, base.BasePath()))
+
+	openapi.Describe(base, http.MethodGet, "/tools", openapi.Operation{
+		Summary:     "Lists the tools served",
+		Description: "The tag parameter keeps the tools with the tag.",
+		Response:    openapi.Body{Type: CatalogueOutput{}},
+	})
+
+	return base
+}
+
+func getCatalogue(r *Registry, served func(name string) bool, docs *openapi.Registry, basePath string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, Catalogue(r, served, docs, basePath, c.Query("tag")))
+	}
+}
+
+// Catalogue lists the tools of the groups served, mounted on basePath,
+// keeping those with the tag when it is not empty.
+func Catalogue(r *Registry, served func(name string) bool, docs *openapi.Registry, basePath, tag string) CatalogueOutput {
+	catalogue := CatalogueOutput{Groups: []GroupOutput{}, Tools: []ToolOutput{}}
+	for _, group := range r.Groups() {
+		if !served(group.Name) {
+			continue
+		}
+		catalogue.Groups = append(catalogue.Groups, GroupOutput{Name: group.Name, Description: group.Description})
+
+		for _, tool := range group.Tools {
+			output := toolOutput(group, tool, docs, basePath)
+			if tag == "" || hasTag(output.Tags, tag) {
+				catalogue.Tools = append(catalogue.Tools, output)
+			}
+		}
+	}
+
+	return catalogue
+}
+
+func toolOutput(group Group, tool Tool, docs *openapi.Registry, basePath string) ToolOutput {
+	output := ToolOutput{
+		Name:   tool.Name,
+		Group:  group.Name,
+		Method: tool.Method,
+		Path:   strings.TrimSuffix(basePath, "/") + "/" + group.Name + tool.Path,
+		Tags:   append([]string{group.Name}, tool.Tags...),
+	}
+
+	op, ok := docs.Operation(output.Method, output.Path)
+	if !ok {
+		return output
+	}
+
+	output.Summary = op.Summary
+	output.Description = op.Description
+	if op.Request != nil {
+		output.Input = bodyOutput(*op.Request)
+	}
+	output.Output = bodyOutput(op.Response)
+
+	return output
+}
+
+func bodyOutput(body openapi.Body) *BodyOutput {
+	contentType := body.ContentType
+	if contentType == "" {
+		contentType = openapi.ContentTypeJson
+	}
+
+	return &BodyOutput{
+		ContentType: contentType,
+		Description: body.Description,
+		Schema:      openapi.InlineSchema(body.Type),
+		Example:     body.Example,
+	}
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func setupGin(recorder *tracetest.SpanRecorder, handlerErr error) *gin.Engine {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	r := gin.Default()
+	r.Use(Middleware("canivete-api-test"))
+	r.GET("/v1/tools/:id", func(c *gin.Context) {
+		span := StartSpan(c, "tools.Run")
+		EndSpan(span, handlerErr)
+		c.String(http.StatusOK, "")
+	})
+
+	return r
+}
+
+func TestMiddlewareContinuesTraceAndCreatesChildSpans(t *testing.T) {
+	// arrange
+	_, err := Setup(context.Background(), config.TracingConfig{})
+	assert.Nil(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	r := setupGin(recorder, nil)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/tools/1", nil)
+	req.Header.Set("traceparent", traceparent)
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	child, server := spans[0], spans[1]
+	assert.Equal(t, "tools.Run", child.Name())
+	assert.Equal(t, "/v1/tools/:id", server.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID())
+}
+
+func TestEndSpanRecordsError(t *testing.T) {
+	// arrange
+	recorder := tracetest.NewSpanRecorder()
+	r := setupGin(recorder, errors.New("fake error"))
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/tools/1", nil)
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	child := recorder.Ended()[0]
+	assert.Equal(t, codes.Error, child.Status().Code)
+	assert.Equal(t, "fake error", child.Status().Description)
+	assert.Len(t, child.Events(), 1)
+}
+
+func TestSetupWithStdoutExporter(t *testing.T) {
+	// arrange
+	out := bytes.Buffer{}
+	stdout = &out
+	cfg := config.Default().Tracing
+	cfg.Enabled = true
+	cfg.Exporter = ExporterStdout
+
+	shutdown, err := Setup(context.Background(), cfg)
+	assert.Nil(t, err)
+
+	// act
+	_, span := otel.Tracer(instrumentationName).Start(context.Background(), "stdout-span")
+	span.End()
+	err = shutdown(context.Background())
+
+	// assert
+	assert.Nil(t, err)
+	assert.Contains(t, out.String(), "stdout-span")
+	assert.Contains(t, out.String(), "canivete-api")
+}
+
+func TestSetupWithNoneExporter(t *testing.T) {
+	// arrange
+	cfg := config.Default().Tracing
+	cfg.Enabled = true
+	cfg.Exporter = ExporterNone
+
+	// act
+	shutdown, err := Setup(context.Background(), cfg)
+
+	// assert
+	assert.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+}
+
+func TestSetupWithInvalidExporter(t *testing.T) {
+	// arrange
+	cfg := config.Default().Tracing
+	cfg.Enabled = true
+	cfg.Exporter = "zipkin"
+
+	// act
+	_, err := Setup(context.Background(), cfg)
+
+	// assert
+	assert.NotNil(t, err)
+}
+
+func TestSetupWithOtlpExporter(t *testing.T) {
+	for _, protocol := range []string{ProtocolGrpc, ProtocolHttp} {
+		// arrange
+		cfg := config.Default().Tracing
+		cfg.Enabled = true
+		cfg.Protocol = protocol
+
+		// act
+		shutdown, err := Setup(context.Background(), cfg)
+
+		// assert
+		assert.Nil(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		shutdown(ctx)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package versioning
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+)
+
+// Versions of the REST api, mounted side by side, like /v1 and /v2.
+const (
+	V1 = "v1"
+	V2 = "v2"
+)
+
+// Versions lists the versions of the api, from the oldest.
+var Versions = []string{V1, V2}
+
+// Middleware announces the deprecation of the deprecated routes in the
+// Deprecation (RFC 9745), Sunset (RFC 8594) and Link headers of their
+// responses, and counts their calls.
+func Middleware(cfg config.VersioningConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		deprecation, ok := cfg.For(route)
+		if route == "" || !ok {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("Deprecation", "@"+strconv.FormatInt(deprecation.Since.Unix(), 10))
+		if !deprecation.Sunset.IsZero() {
+			header.Set("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if deprecation.Link != "" {
+			header.Add("Link", "<"+deprecation.Link+`>; rel="deprecation"; type="text/html"`)
+		}
+		if deprecation.Successor != "" {
+			header.Add("Link", "<"+deprecation.Successor+`>; rel="successor-version"`)
+		}
+		metrics.DeprecatedRequests.WithLabelValues(route).Inc()
+
+		c.Next()
+	}
+}
+
+// NewValidator returns a validator naming the invalid fields by their JSON
+// name, as the bodies of the routes from v2 are in camel case.
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+
+	return validate
+}
@@ -0,0 +1,109 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package versioning
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupGin(cfg config.VersioningConfig) *gin.Engine {
+	r := gin.New()
+	r.Use(Middleware(cfg))
+	r.GET("/v1/datetime/fromunix", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/v2/datetime/fromunix", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return r
+}
+
+func TestMiddlewareAnnouncesTheDeprecation(t *testing.T) {
+	// arrange
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := setupGin(config.VersioningConfig{
+		Deprecations: map[string]config.DeprecationConfig{
+			"/v1": {
+				Since:     since,
+				Sunset:    since.AddDate(1, 0, 0),
+				Link:      "https://docs.example.com/migrate-to-v2",
+				Successor: "/v2",
+			},
+		},
+	})
+	counter := metrics.DeprecatedRequests.WithLabelValues("/v1/datetime/fromunix")
+	before := testutil.ToFloat64(counter)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/v1/datetime/fromunix", nil)
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "@1767225600", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, []string{
+		`<https://docs.example.com/migrate-to-v2>; rel="deprecation"; type="text/html"`,
+		`</v2>; rel="successor-version"`,
+	}, w.Header().Values("Link"))
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
+
+func TestMiddlewareSkipsTheOtherRoutes(t *testing.T) {
+	// arrange
+	r := setupGin(config.VersioningConfig{
+		Deprecations: map[string]config.DeprecationConfig{
+			"/v1": {Since: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	})
+
+	for _, url := range []string{"/v2/datetime/fromunix", "/v1/unknown"} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Empty(t, w.Header().Get("Deprecation"), url)
+		assert.Empty(t, w.Header().Get("Sunset"), url)
+		assert.Empty(t, w.Header().Get("Link"), url)
+	}
+}
+
+func TestNewValidatorNamesTheFieldsByTheirJsonName(t *testing.T) {
+	// arrange
+	input := struct {
+		InvestAmount float64 `json:"investAmount" validate:"required"`
+		Untagged     float64 `validate:"required"`
+	}{}
+
+	// act
+	err := NewValidator().Struct(input)
+
+	// assert
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "'investAmount'")
+	assert.Contains(t, err.Error(), "'Untagged'")
+}
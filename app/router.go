@@ -0,0 +1,316 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/admin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/batch"
+	"github.com/renato0307/canivete-api/pkg/cache"
+	"github.com/renato0307/canivete-api/pkg/certs"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/cors"
+	"github.com/renato0307/canivete-api/pkg/health"
+	"github.com/renato0307/canivete-api/pkg/limits"
+	"github.com/renato0307/canivete-api/pkg/logging"
+	"github.com/renato0307/canivete-api/pkg/metrics"
+	"github.com/renato0307/canivete-api/pkg/openapi"
+	"github.com/renato0307/canivete-api/pkg/ratelimit"
+	"github.com/renato0307/canivete-api/pkg/render"
+	"github.com/renato0307/canivete-api/pkg/requestid"
+	"github.com/renato0307/canivete-api/pkg/tools"
+	"github.com/renato0307/canivete-api/pkg/tracing"
+	"github.com/renato0307/canivete-api/pkg/versioning"
+)
+
+// apiInfo is the metadata published in the OpenAPI document.
+var apiInfo = openapi.Info{
+	Title:       "canivete-api",
+	Description: "A swiss army knife of tools for developers.",
+	Version:     "1.0.0",
+}
+
+// apiVersions are the versions of the OpenAPI documents of the versions
+// of the api.
+var apiVersions = map[string]string{
+	versioning.V1: "1.0.0",
+	versioning.V2: "2.0.0",
+}
+
+// newRouter creates the gin engine with the middlewares and the service
+// groups enabled in the configuration. The authenticators are shared with
+// the gRPC server, so the keys of the JWKS are fetched once, and so are the
+// toggles, so the routes disabled by the admin endpoints are disabled over
+// gRPC too, and so is the rate limiter, so the callers have one budget.
+func newRouter(cfg config.Config, authenticators []auth.Authenticator, toggles *admin.Toggles, limiter *ratelimit.Limiter) (*gin.Engine, error) {
+	gin.SetMode(cfg.Server.Mode)
+	r := gin.New()
+	r.HandleMethodNotAllowed = true
+	r.Use(requestid.Middleware(cfg.Server.RequestIdHeader), logging.Middleware())
+	if cfg.Server.Tls.Enabled() {
+		r.Use(certs.Middleware())
+	}
+	if cfg.Logging.AccessLog {
+		r.Use(logging.AccessLog("/healthz", "/readyz", "/livez", cfg.Metrics.Path))
+	}
+	r.Use(gin.CustomRecoveryWithWriter(ioutil.Discard, recovery))
+	if cfg.Cors.Enabled {
+		// before the authentication, the preflights carry no credentials
+		r.Use(cors.Middleware(cfg.Cors))
+	}
+	if len(cfg.Versioning.Deprecations) > 0 {
+		r.Use(versioning.Middleware(cfg.Versioning))
+	}
+
+	r.NoRoute(func(c *gin.Context) {
+		apierrors.Abort(c, apierrors.New(http.StatusNotFound, apierrors.CodeNotFound, "no route matches the request"))
+	})
+	r.NoMethod(func(c *gin.Context) {
+		apierrors.Abort(c, apierrors.New(http.StatusMethodNotAllowed, apierrors.CodeMethodNotAllowed, "method not allowed for the route"))
+	})
+
+	if cfg.Tracing.Enabled {
+		r.Use(tracing.Middleware(cfg.Tracing.ServiceName))
+	}
+
+	if cfg.Metrics.Enabled {
+		r.Use(metrics.Middleware())
+		metrics.SetRouterGroup(cfg.Metrics.Path, &r.RouterGroup)
+	}
+
+	// https://pkg.go.dev/github.com/gin-gonic/gin#readme-don-t-trust-all-proxies
+	var trustedProxies []string
+	if len(cfg.Server.TrustedProxies) > 0 {
+		trustedProxies = cfg.Server.TrustedProxies
+	}
+	err := r.SetTrustedProxies(trustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	r.GET("/", func(c *gin.Context) {
+		c.String(200, "Welcome to canivete-api!")
+	})
+
+	openapi.SetAssetsUrl(cfg.Server.AssetsUrl)
+	openapi.SetAssetsRouterGroup(&r.RouterGroup)
+	if cfg.Server.AssetsUrl == openapi.EmbeddedAssetsUrl && !openapi.AssetsEmbedded() {
+		logging.GetLogger().Warnw("the docs and GraphiQL assets are not embedded, run go generate ./pkg/openapi before the build",
+			"assetsUrl", cfg.Server.AssetsUrl)
+	}
+
+	healthRegistry := health.DefaultRegistry()
+	healthRegistry.SetTimeout(cfg.Health.CheckTimeout)
+	healthRegistry.SetCacheTtl(cfg.Health.CacheTtl)
+	health.SetRouterGroup(healthRegistry, &r.RouterGroup)
+
+	apiHandlers := newApiHandlers(cfg, authenticators, limiter)
+	toolGroup := newToolGroups(cfg, apiHandlers, toggles)
+
+	var v1, api *gin.RouterGroup
+	for _, version := range versioning.Versions {
+		base, versionApi := newVersionGroup(cfg, r, version, apiHandlers, toolGroup, toggles)
+		if version == versioning.V1 {
+			// the batch and GraphQL endpoints are not versioned
+			v1, api = base, versionApi
+		}
+	}
+	for _, route := range cfg.Admin.DisabledRoutes {
+		err = toggles.Set(route, false)
+		if err != nil {
+			return nil, fmt.Errorf("error disabling route: %s", err.Error())
+		}
+	}
+
+	if cfg.Batch.Enabled {
+		// the requests of the batch are authenticated and limited on their own
+		batch.SetRouterGroup(r, cfg.Batch, v1.Group("", limits.Middleware(cfg.Limits)))
+	}
+
+	if cfg.Graphql.Enabled {
+		// the fields check the scopes of the caller on their own
+		_, err = newGraphqlServer(cfg, toggles, limiter).SetRouterGroup(api)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Admin.Enabled && cfg.Auth.Enabled {
+		// the configuration refuses the admin endpoints without auth, they
+		// are never mounted for anonymous callers either way
+		adminGroup := newAdminGroup(r, authenticators)
+		admin.SetRouterGroup(cfg, toggles, apiInfo.Version, adminGroup)
+		if cfg.Audit.Enabled {
+			audit.SetRouterGroup(adminGroup)
+		}
+	}
+
+	if cfg.Cors.Enabled {
+		cors.SetPreflightRoutes(r)
+	}
+
+	return r, nil
+}
+
+// newVersionGroup mounts a version of the api, like /v2, with the routes
+// the service groups serve in the version, their catalogue and their
+// OpenAPI document. It returns the group of the version and the group of
+// the endpoints calling the tools on their own, like GraphQL.
+func newVersionGroup(cfg config.Config, r *gin.Engine, version string, apiHandlers []gin.HandlerFunc,
+	toolGroup func(base *gin.RouterGroup, scope string) *gin.RouterGroup, toggles *admin.Toggles) (*gin.RouterGroup, *gin.RouterGroup) {
+	base := r.Group("/" + version)
+	info := apiInfo
+	info.Version = apiVersions[version]
+	openapi.SetRouterGroup(openapi.DefaultRegistry(), info, base)
+
+	api := base.Group("", apiHandlers...)
+	for _, group := range tools.DefaultRegistry().Groups() {
+		if cfg.GroupEnabled(group.Name) {
+			group.RouterGroup(version)(toolGroup(base, group.Name))
+			for _, tool := range group.Tools {
+				toggles.Add(toolRoute(version, group, tool))
+			}
+		}
+	}
+	tools.SetRouterGroup(tools.DefaultRegistry(), cfg.GroupEnabled, openapi.DefaultRegistry(), base)
+
+	return base, api
+}
+
+// toolRoute returns the route of the tool in the version of the api, like
+// /v1/programming/uuid.
+func toolRoute(version string, group tools.Group, tool tools.Tool) string {
+	return "/" + version + "/" + group.Name + tool.Path
+}
+
+// newRateLimiter returns the rate limiter of the configuration, keeping the
+// buckets in memory, with the costs set by the service groups.
+func newRateLimiter(cfg config.RateLimitConfig) *ratelimit.Limiter {
+	limit := ratelimit.Limit{Rate: cfg.Rate, Burst: cfg.Burst}
+	return ratelimit.NewLimiter(ratelimit.NewMemoryStore(), limit, ratelimit.DefaultCosts(), cfg.Costs)
+}
+
+// newApiHandlers returns the middlewares of the endpoints calling the
+// tools, shared by the versions of the api. When auth is enabled, they
+// require credentials. When rate limiting is enabled, the requests take
+// tokens from the bucket of the caller, whatever the version they call.
+// The bodies and the durations of the requests are always bounded.
+func newApiHandlers(cfg config.Config, authenticators []auth.Authenticator, limiter *ratelimit.Limiter) []gin.HandlerFunc {
+	handlers := []gin.HandlerFunc{}
+
+	if cfg.Auth.Enabled {
+		handlers = append(handlers, auth.Middleware(authenticators...))
+	}
+
+	if cfg.RateLimit.Enabled {
+		handlers = append(handlers, ratelimit.Middleware(limiter))
+	}
+
+	handlers = append(handlers, limits.Middleware(cfg.Limits))
+
+	return handlers
+}
+
+// newToolGroups returns a function giving the group of a version where a
+// service group is mounted, behind the api handlers. When audit is enabled,
+// the calls are recorded, the refused and the cached ones included. When
+// auth is enabled, the group requires the scope of the service group. The
+// routes disabled in the toggles are answered with 503. The formats the
+// requests accept are checked and the responses cached with the policy of
+// their route.
+func newToolGroups(cfg config.Config, apiHandlers []gin.HandlerFunc, toggles *admin.Toggles) func(base *gin.RouterGroup, scope string) *gin.RouterGroup {
+	var lru *cache.LRU
+	if cfg.Cache.Enabled {
+		lru = cache.NewLRU(cfg.Cache.Size)
+	}
+	cached := cache.Middleware(cache.DefaultPolicies(), cfg.Cache.CacheControl, lru)
+
+	return func(base *gin.RouterGroup, scope string) *gin.RouterGroup {
+		handlers := []gin.HandlerFunc{}
+		if cfg.Audit.Enabled {
+			// first, so the calls refused by the next handlers are recorded
+			handlers = append(handlers, audit.Middleware())
+		}
+		handlers = append(handlers, apiHandlers...)
+		handlers = append(handlers, render.Middleware())
+		if cfg.Auth.Enabled {
+			// the cached responses are only served to the callers with the scope
+			handlers = append(handlers, auth.RequireScope(scope))
+		}
+		// the disabled routes are not served from the cache either
+		handlers = append(handlers, admin.Middleware(toggles))
+		handlers = append(handlers, cached)
+
+		return base.Group("", handlers...)
+	}
+}
+
+// newAdminGroup returns the group of the admin endpoints, requiring
+// credentials with the admin scope.
+func newAdminGroup(r *gin.Engine, authenticators []auth.Authenticator) *gin.RouterGroup {
+	return r.Group("/admin", auth.Middleware(authenticators...), auth.RequireScope(config.ScopeAdmin))
+}
+
+// newAuthenticators returns the authenticators of the credentials
+// configured: api keys and bearer JWTs. It returns none when auth is
+// disabled.
+func newAuthenticators(cfg config.AuthConfig) ([]auth.Authenticator, error) {
+	authenticators := []auth.Authenticator{}
+	if !cfg.Enabled {
+		return authenticators, nil
+	}
+
+	keys := cfg.ApiKeys
+	if cfg.ApiKeysFile != "" {
+		fileKeys, err := auth.LoadApiKeys(cfg.ApiKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(append([]config.ApiKeyConfig{}, keys...), fileKeys...)
+	}
+
+	if len(keys) > 0 {
+		apiKeys, err := auth.NewApiKeyAuthenticator(keys)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, apiKeys)
+	}
+
+	if cfg.Jwt.Enabled {
+		jwks := auth.NewJwksFromUrl(cfg.Jwt.JwksUrl, cfg.Jwt.JwksRefreshInterval)
+		if cfg.Jwt.JwksFile != "" {
+			jwks = auth.NewJwksFromFile(cfg.Jwt.JwksFile, cfg.Jwt.JwksRefreshInterval)
+		}
+		authenticators = append(authenticators, auth.NewJwtAuthenticator(cfg.Jwt, jwks))
+	}
+
+	return authenticators, nil
+}
+
+// recovery logs the panic of a handler and answers with an internal error.
+func recovery(c *gin.Context, recovered interface{}) {
+	logging.FromContext(c).Errorw("handler panicked", "panic", recovered)
+	apierrors.Abort(c, apierrors.Internal(apierrors.CodeInternal, "unexpected error"))
+}
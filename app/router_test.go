@@ -0,0 +1,720 @@
+/*
+Copyright © 2021 Renato Torres <renato.torres@pm.me>
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Lesser General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Lesser General Public License for more details.
+
+You should have received a copy of the GNU Lesser General Public License
+along with this program. If not, see <http://www.gnu.org/licenses/>.
+*/
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/renato0307/canivete-api/pkg/admin"
+	"github.com/renato0307/canivete-api/pkg/apierrors"
+	"github.com/renato0307/canivete-api/pkg/audit"
+	"github.com/renato0307/canivete-api/pkg/auth"
+	"github.com/renato0307/canivete-api/pkg/batch"
+	"github.com/renato0307/canivete-api/pkg/config"
+	"github.com/renato0307/canivete-api/pkg/openapi"
+	"github.com/renato0307/canivete-api/pkg/tools"
+	"github.com/renato0307/canivete-api/pkg/versioning"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupRouter creates the router with the authenticators of the
+// configuration, like main.
+func setupRouter(cfg config.Config) (*gin.Engine, error) {
+	authenticators, err := newAuthenticators(cfg.Auth)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRouter(cfg, authenticators, admin.NewToggles(), newRateLimiter(cfg.RateLimit))
+}
+
+func TestOpenApiDocumentsEveryRoute(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+
+	for _, version := range versioning.Versions {
+		base := "/" + version
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", base+"/openapi.json", nil)
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		doc := openapi.Document{}
+		err = json.Unmarshal(w.Body.Bytes(), &doc)
+		assert.Nil(t, err)
+		assert.Equal(t, apiVersions[version], doc.Info.Version)
+
+		undocumented := map[string]bool{
+			base + "/openapi.json": true,
+			base + "/docs":         true,
+		}
+		for _, route := range r.Routes() {
+			if !strings.HasPrefix(route.Path, base+"/") || undocumented[route.Path] {
+				continue
+			}
+
+			operations, ok := doc.Paths[openapi.PathTemplate(route.Path)]
+			if assert.True(t, ok, "route %s is missing from the OpenAPI document", route.Path) {
+				assert.Contains(t, operations, strings.ToLower(route.Method),
+					"method %s of route %s is missing from the OpenAPI document", route.Method, route.Path)
+			}
+		}
+		for path := range doc.Paths {
+			assert.True(t, strings.HasPrefix(path, base+"/"), "route %s is in the OpenAPI document of %s", path, version)
+		}
+	}
+}
+
+func TestV2ServesTheChangedRoutesNextToV1(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		path   string
+		body   string
+		status int
+	}{
+		{"/v1/datetime/fromunix", "1638964800", http.StatusOK},
+		{"/v2/datetime/fromunix", `{"timestamp": 1638964800}`, http.StatusOK},
+		{"/v2/datetime/fromunix", "1638964800", http.StatusBadRequest},
+		{"/v2/programming/uuid", "", http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		method := http.MethodPost
+		if tc.body == "" {
+			method = http.MethodGet
+		}
+		req, _ := http.NewRequest(method, tc.path, strings.NewReader(tc.body))
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Equal(t, tc.status, w.Code, "unexpected status for %s %s", tc.path, tc.body)
+	}
+}
+
+func TestDeprecatedRoutesAnnounceTheirDeprecation(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.Versioning.Deprecations = map[string]config.DeprecationConfig{
+		"/v1": {
+			Since:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			Sunset:    time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			Successor: "/v2",
+		},
+	}
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		path       string
+		deprecated bool
+	}{
+		{"/v1/programming/uuid", true},
+		{"/v2/programming/uuid", false},
+		{"/healthz", false},
+	}
+
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, tc.path, nil)
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Equal(t, http.StatusOK, w.Code, tc.path)
+		if !tc.deprecated {
+			assert.Empty(t, w.Header().Get("Deprecation"), tc.path)
+			continue
+		}
+		assert.Equal(t, "@1767225600", w.Header().Get("Deprecation"))
+		assert.Equal(t, "Fri, 01 Jan 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+		assert.Equal(t, `</v2>; rel="successor-version"`, w.Header().Get("Link"))
+	}
+}
+
+func TestUnknownRoutesAnswerWithProblems(t *testing.T) {
+	cases := []struct {
+		method string
+		path   string
+		status int
+		code   string
+	}{
+		{"GET", "/v1/unknown", http.StatusNotFound, apierrors.CodeNotFound},
+		{"POST", "/v1/programming/uuid", http.StatusMethodNotAllowed, apierrors.CodeMethodNotAllowed},
+	}
+
+	for _, tc := range cases {
+		// arrange
+		cfg := config.Default()
+		cfg.Server.Mode = "test"
+		r, err := setupRouter(cfg)
+		assert.Nil(t, err)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tc.method, tc.path, nil)
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Equal(t, tc.status, w.Code)
+
+		apiError, err := apierrors.FromResponse(w.Result())
+		assert.Nil(t, err)
+		assert.Equal(t, tc.code, apiError.Code)
+		assert.Equal(t, tc.path, apiError.Instance)
+	}
+}
+
+func TestAuthProtectsOnlyTheServiceGroups(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.Auth.Enabled = true
+	cfg.Auth.ApiKeys = []config.ApiKeyConfig{
+		{Name: "ci", Hash: auth.HashApiKey("ci-key"), Scopes: []string{config.GroupProgramming}},
+	}
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		path   string
+		apiKey string
+		status int
+	}{
+		{"/v1/programming/uuid", "", http.StatusUnauthorized},
+		{"/v1/programming/uuid", "ci-key", http.StatusOK},
+		{"/v1/openapi.json", "", http.StatusOK},
+		{"/livez", "", http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", tc.path, nil)
+		if tc.apiKey != "" {
+			req.Header.Set(auth.ApiKeyHeader, tc.apiKey)
+		}
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Equal(t, tc.status, w.Code, "unexpected status for %s", tc.path)
+	}
+}
+
+func TestRateLimitAppliesToTheServiceGroups(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.Burst = 1
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// act
+	first := serve("/v1/programming/uuid")
+	second := serve("/v1/programming/uuid")
+	docs := serve("/v1/openapi.json")
+
+	// assert
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+	assert.NotEmpty(t, second.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusOK, docs.Code)
+}
+
+func TestLimitsApplyToTheServiceGroups(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.Limits.MaxBodySize = 16
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+	w := httptest.NewRecorder()
+	body := strings.NewReader(strings.Repeat("1", 17))
+	req, _ := http.NewRequest("POST", "/v1/datetime/fromunix", body)
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, apierrors.ContentType, w.Header().Get("Content-Type"))
+}
+
+func TestServiceGroupsNegotiateTheFormat(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+
+	serve := func(path string, accept string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", accept)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// act
+	text := serve("/v1/programming/uuid", "text/plain")
+	refused := serve("/v1/programming/uuid", "image/png")
+	docs := serve("/v1/openapi.json", "image/png")
+
+	// assert
+	assert.Equal(t, http.StatusOK, text.Code)
+	assert.Len(t, strings.TrimSpace(text.Body.String()), 36)
+	assert.Equal(t, http.StatusNotAcceptable, refused.Code)
+	assert.Equal(t, http.StatusOK, docs.Code)
+}
+
+func TestBatchRequestsAreAuthenticatedOnTheirOwn(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.Auth.Enabled = true
+	cfg.Auth.ApiKeys = []config.ApiKeyConfig{
+		{Name: "ci", Hash: auth.HashApiKey("ci-key"), Scopes: []string{config.GroupProgramming}},
+	}
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+	w := httptest.NewRecorder()
+	body := `[
+		{"method": "GET", "path": "/v1/programming/uuid"},
+		{"method": "POST", "path": "/v1/datetime/fromunix", "body": "1638964800"}
+	]`
+	req, _ := http.NewRequest("POST", "/v1/batch", strings.NewReader(body))
+	req.Header.Set(auth.ApiKeyHeader, "ci-key")
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	results := []batch.Result{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &results))
+	assert.Equal(t, http.StatusOK, results[0].Status)
+	assert.Equal(t, http.StatusForbidden, results[1].Status)
+}
+
+func TestServiceGroupsSetTheCachePolicies(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.Cache.Enabled = true
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+
+	serve := func(method string, path string, body string, ifNoneMatch string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// act
+	uuid := serve("GET", "/v1/programming/uuid", "", "")
+	date := serve("POST", "/v1/datetime/fromunix", "1638964800", "")
+	cached := serve("POST", "/v1/datetime/fromunix", "1638964800", date.Header().Get("ETag"))
+
+	// assert
+	assert.Equal(t, "no-store", uuid.Header().Get("Cache-Control"))
+	assert.Empty(t, uuid.Header().Get("ETag"))
+	assert.Equal(t, http.StatusOK, date.Code)
+	assert.NotEmpty(t, date.Header().Get("ETag"))
+	assert.Equal(t, http.StatusNotModified, cached.Code)
+}
+
+func TestCachedResponsesRequireTheScope(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.Cache.Enabled = true
+	cfg.Auth.Enabled = true
+	cfg.Auth.ApiKeys = []config.ApiKeyConfig{
+		{Name: "datetime", Hash: auth.HashApiKey("datetime-key"), Scopes: []string{config.GroupDatetime}},
+		{Name: "finance", Hash: auth.HashApiKey("finance-key"), Scopes: []string{config.GroupFinance}},
+	}
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+
+	serve := func(apiKey string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/datetime/fromunix", strings.NewReader("1638964800"))
+		req.Header.Set(auth.ApiKeyHeader, apiKey)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// act
+	allowed := serve("datetime-key")
+	forbidden := serve("finance-key")
+
+	// assert
+	assert.Equal(t, http.StatusOK, allowed.Code)
+	assert.Equal(t, http.StatusForbidden, forbidden.Code)
+}
+
+func TestAuditRecordsTheToolCalls(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.Auth.Enabled = true
+	cfg.Auth.ApiKeys = []config.ApiKeyConfig{
+		{Name: "ci", Hash: auth.HashApiKey("ci-key"), Scopes: []string{config.ScopeAll}},
+		{Name: "datetime", Hash: auth.HashApiKey("datetime-key"), Scopes: []string{config.GroupDatetime}},
+		{Name: "ops", Hash: auth.HashApiKey("ops-key"), Scopes: []string{config.ScopeAdmin}},
+	}
+	cfg.Audit.Enabled = true
+	cfg.Audit.Sink = "memory"
+	cfg.Audit.Redaction = audit.Keep
+	cfg.Admin.Enabled = true
+	cfg.Admin.DisabledRoutes = []string{"/v1/programming/uuid"}
+	_, err := audit.Setup(cfg.Audit)
+	assert.Nil(t, err)
+	defer audit.SetDefault(nil)
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+
+	serve := func(method, path, body, apiKey string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(auth.ApiKeyHeader, apiKey)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// act
+	serve("POST", "/v1/programming/jwt-debugger", "secret-token", "ci-key")
+	serve("POST", "/v1/programming/jwt-debugger", "other-token", "datetime-key")
+	serve("POST", "/v1/programming/jwt-debugger", "unknown-token", "unknown-key")
+	serve("GET", "/v1/programming/uuid", "", "ci-key")
+	forbidden := serve("GET", "/admin/audit", "", "ci-key")
+	listed := serve("GET", "/admin/audit", "", "ops-key")
+
+	// assert
+	assert.Equal(t, http.StatusForbidden, forbidden.Code)
+	assert.Equal(t, http.StatusOK, listed.Code)
+
+	output := audit.RecordsOutput{}
+	json.Unmarshal(listed.Body.Bytes(), &output)
+	assert.Len(t, output.Records, 4)
+	disabled, unauthorized, forbiddenCall, allowed := output.Records[0], output.Records[1], output.Records[2], output.Records[3]
+	assert.Equal(t, "ci", allowed.Caller)
+	assert.Equal(t, "/v1/programming/jwt-debugger", allowed.Route)
+	assert.Equal(t, audit.Digest("secret-token"), allowed.Inputs["body"])
+	assert.Equal(t, "datetime", forbiddenCall.Caller)
+	assert.Equal(t, "403", forbiddenCall.Status)
+	assert.Empty(t, unauthorized.Caller)
+	assert.Equal(t, "401", unauthorized.Status)
+	assert.Empty(t, unauthorized.Inputs["body"])
+	assert.Equal(t, "/v1/programming/uuid", disabled.Route)
+	assert.Equal(t, "503", disabled.Status)
+}
+
+func TestAuditRecordsAreOnlyListedByTheAdminEndpoints(t *testing.T) {
+	testCases := []struct {
+		name  string
+		admin bool
+		auth  bool
+		code  int
+	}{
+		{"admin and auth enabled", true, true, http.StatusOK},
+		{"admin disabled", false, true, http.StatusNotFound},
+		{"auth disabled", true, false, http.StatusNotFound},
+		{"admin and auth disabled", false, false, http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		// arrange
+		cfg := config.Default()
+		cfg.Server.Mode = "test"
+		cfg.Auth.Enabled = tc.auth
+		cfg.Auth.ApiKeys = []config.ApiKeyConfig{
+			{Name: "ops", Hash: auth.HashApiKey("ops-key"), Scopes: []string{config.ScopeAdmin}},
+		}
+		cfg.Admin.Enabled = tc.admin
+		cfg.Audit.Enabled = true
+		cfg.Audit.Sink = "memory"
+		_, err := audit.Setup(cfg.Audit)
+		assert.Nil(t, err)
+		r, err := setupRouter(cfg)
+		assert.Nil(t, err)
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/admin/audit", nil)
+		req.Header.Set(auth.ApiKeyHeader, "ops-key")
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Equal(t, tc.code, w.Code, tc.name)
+		audit.SetDefault(nil)
+	}
+}
+
+func TestAdminDisablesTheToolRoutes(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.Cache.Enabled = true
+	cfg.Auth.Enabled = true
+	cfg.Auth.ApiKeys = []config.ApiKeyConfig{
+		{Name: "ci", Hash: auth.HashApiKey("ci-key"), Scopes: []string{config.ScopeAll}},
+		{Name: "ops", Hash: auth.HashApiKey("ops-key"), Scopes: []string{config.ScopeAdmin}},
+	}
+	cfg.Admin.Enabled = true
+	cfg.Admin.DisabledRoutes = []string{"/v1/programming/uuid"}
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+
+	serve := func(method, path, body, apiKey string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(auth.ApiKeyHeader, apiKey)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// act
+	uuid := serve("GET", "/v1/programming/uuid", "", "ci-key")
+	cached := serve("POST", "/v1/datetime/fromunix", "1638964800", "ci-key")
+	forbidden := serve("PUT", "/admin/routes/v1/datetime/fromunix", `{"enabled":false}`, "ci-key")
+	toggled := serve("PUT", "/admin/routes/v1/datetime/fromunix", `{"enabled":false}`, "ops-key")
+	disabled := serve("POST", "/v1/datetime/fromunix", "1638964800", "ci-key")
+
+	// assert
+	assert.Equal(t, http.StatusServiceUnavailable, uuid.Code)
+	assert.Equal(t, http.StatusOK, cached.Code)
+	assert.Equal(t, http.StatusForbidden, forbidden.Code)
+	assert.Equal(t, http.StatusOK, toggled.Code)
+	assert.Equal(t, http.StatusServiceUnavailable, disabled.Code)
+}
+
+func TestAdminDisablesTheToolsOverGraphqlAndGrpc(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.Auth.Enabled = true
+	cfg.Auth.ApiKeys = []config.ApiKeyConfig{
+		{Name: "ci", Hash: auth.HashApiKey("ci-key"), Scopes: []string{config.ScopeAll}},
+		{Name: "ops", Hash: auth.HashApiKey("ops-key"), Scopes: []string{config.ScopeAdmin}},
+	}
+	cfg.Admin.Enabled = true
+	authenticators, err := newAuthenticators(cfg.Auth)
+	assert.Nil(t, err)
+	toggles := admin.NewToggles()
+	limiter := newRateLimiter(cfg.RateLimit)
+	r, err := newRouter(cfg, authenticators, toggles, limiter)
+	assert.Nil(t, err)
+	newGrpcServer(cfg, authenticators, toggles, limiter, nil)
+
+	serve := func(method, path, body, apiKey string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(auth.ApiKeyHeader, apiKey)
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// act
+	toggled := serve("PUT", "/admin/routes/v1/programming/uuid", `{"enabled":false}`, "ops-key")
+	disabled := serve("POST", "/v1/graphql", `{"query": "{ uuid }"}`, "ci-key")
+	enabled := serve("POST", "/v1/graphql", `{"query": "{ fromUnix(unixTimestamp: 1638964800) { utcTimestamp } }"}`, "ci-key")
+
+	// assert
+	assert.Equal(t, http.StatusOK, toggled.Code)
+	assert.Contains(t, disabled.Body.String(), apierrors.CodeRouteDisabled)
+	assert.Contains(t, enabled.Body.String(), "Wed Dec  8 12:00:00 UTC 2021")
+	assert.False(t, toggles.Enabled("/canivete.v1.ProgrammingService/NewUuid"))
+	assert.True(t, toggles.Enabled("/canivete.v1.ProgrammingService/DebugJwt"))
+}
+
+func TestAdminRejectsUnknownDisabledRoutes(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.Admin.DisabledRoutes = []string{"/v1/programming/unknown"}
+
+	// act
+	_, err := setupRouter(cfg)
+
+	// assert
+	assert.NotNil(t, err)
+}
+
+func TestGraphqlCallsSeveralToolsInOneQuery(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.Auth.Enabled = true
+	cfg.Auth.ApiKeys = []config.ApiKeyConfig{
+		{Name: "ci", Hash: auth.HashApiKey("ci-key"), Scopes: []string{config.GroupProgramming, config.GroupFinance}},
+	}
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+	query := `{
+		uuid
+		fromUnix(unixTimestamp: 1638964800) { utcTimestamp }
+		compoundInterests(input: {investAmount: 5000, compoundPeriods: 12, time: 1, interestRate: 8, regularContributionsPeriod: 12}) {
+			total { finalAmount }
+		}
+	}`
+	body, _ := json.Marshal(map[string]string{"query": query})
+
+	tests := []struct {
+		apiKey string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"ci-key", http.StatusOK},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("POST", "/v1/graphql", strings.NewReader(string(body)))
+		req.Header.Set(auth.ApiKeyHeader, test.apiKey)
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Equal(t, test.status, w.Code)
+		if test.status != http.StatusOK {
+			continue
+		}
+		response := struct {
+			Data   map[string]interface{}
+			Errors []struct{ Path []string }
+		}{}
+		assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Data["uuid"], 36)
+		assert.NotNil(t, response.Data["compoundInterests"])
+		assert.Nil(t, response.Data["fromUnix"])
+		if assert.Len(t, response.Errors, 1) {
+			assert.Equal(t, []string{"fromUnix"}, response.Errors[0].Path)
+		}
+	}
+}
+
+func TestToolsListsTheEnabledGroups(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	disabled := false
+	cfg.Groups[config.GroupInternet] = config.GroupConfig{Enabled: &disabled}
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/v1/tools", nil)
+
+	// act
+	r.ServeHTTP(w, req)
+
+	// assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	catalogue := tools.CatalogueOutput{}
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &catalogue))
+
+	paths := []string{}
+	for _, tool := range catalogue.Tools {
+		paths = append(paths, tool.Method+" "+tool.Path)
+		assert.NotEmpty(t, tool.Summary, "tool %s has no summary", tool.Name)
+	}
+	assert.Equal(t, []string{
+		"POST /v1/datetime/fromunix",
+		"POST /v1/finance/calculate-compound-interests",
+		"GET /v1/programming/uuid",
+		"POST /v1/programming/jwt-debugger",
+	}, paths)
+}
+
+func TestCorsPreflightsSkipTheAuthentication(t *testing.T) {
+	// arrange
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+	cfg.Auth.Enabled = true
+	cfg.Auth.ApiKeys = []config.ApiKeyConfig{
+		{Name: "ci", Hash: auth.HashApiKey("ci-key"), Scopes: []string{config.GroupProgramming}},
+	}
+	cfg.Cors.Enabled = true
+	cfg.Cors.AllowedOrigins = []string{"https://portal.example.com"}
+	r, err := setupRouter(cfg)
+	assert.Nil(t, err)
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodOptions, "/v1/programming/uuid", http.StatusNoContent},
+		{http.MethodOptions, "/v1/graphql", http.StatusNoContent},
+		{http.MethodOptions, "/v1/unknown", http.StatusNotFound},
+		{http.MethodGet, "/v1/programming/uuid", http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("Origin", "https://portal.example.com")
+		if tc.method == http.MethodOptions {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+
+		// act
+		r.ServeHTTP(w, req)
+
+		// assert
+		assert.Equal(t, tc.status, w.Code, "unexpected status for %s %s", tc.method, tc.path)
+		if tc.status != http.StatusNotFound {
+			assert.Equal(t, "https://portal.example.com", w.Header().Get("Access-Control-Allow-Origin"), "%s %s", tc.method, tc.path)
+		}
+	}
+}